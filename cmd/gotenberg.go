@@ -38,73 +38,25 @@ var Version = "snapshot"
 func Run() {
 	gotenberg.Version = Version
 
-	// Create the root FlagSet and adds the modules flags to it.
-	fs := flag.NewFlagSet("gotenberg", flag.ExitOnError)
-	fs.Bool("gotenberg-hide-banner", false, "Hide the banner")
-	fs.Duration("gotenberg-graceful-shutdown-duration", time.Duration(30)*time.Second, "Set the graceful shutdown duration")
-	fs.Bool("gotenberg-build-debug-data", true, "Set if build data is needed")
-
-	// Logging & telemetry flags.
-	fs.String("log-level", gotenberg.InfoLoggingLevel, "Set the log level")
-	fs.String("log-fields-prefix", "", "Prepend a specified prefix to each log field key")
-	fs.String("log-std-format", gotenberg.AutoLoggingFormat, "Set the log format for standard output")
-	fs.Bool("log-std-enable-gcp-fields", false, "Use GCP-compatible field names in log output")
-	fs.String("log-std-level-case", gotenberg.LowerLevelCase, "Set the case of the level field in the standard output, either lower or upper")
-
-	// Deprecated logging flags.
-	fs.String("log-format", gotenberg.AutoLoggingFormat, "Set the log format")
-	fs.Bool("log-enable-gcp-fields", false, "Use GCP-compatible field names")
-
-	if err := errors.Join(
-		fs.MarkDeprecated("log-format", "use --log-std-format instead"),
-		fs.MarkDeprecated("log-enable-gcp-fields", "use --log-std-enable-gcp-fields instead"),
-	); err != nil {
-		fmt.Printf("[FATAL] mark deprecated flags: %s\n", err)
-		os.Exit(1)
-	}
-
 	descriptors := gotenberg.GetModuleDescriptors()
 	var modsInfo strings.Builder
 	for _, desc := range descriptors {
-		fs.AddFlagSet(desc.FlagSet)
 		modsInfo.WriteString(desc.ID + " ")
 	}
 
-	// Parse the flags.
-	err := fs.Parse(os.Args[1:])
+	// Create the root FlagSet and adds the modules flags to it.
+	fs, err := newFlagSet(descriptors, flag.ExitOnError)
 	if err != nil {
-		fmt.Println(err)
+		fmt.Printf("[FATAL] %s\n", err)
 		os.Exit(1)
 	}
 
-	// Override their values if the corresponding environment variables are
-	// set.
-	fs.VisitAll(func(f *flag.Flag) {
-		envName := strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
-		val, ok := os.LookupEnv(envName)
-		if !ok {
-			return
-		}
-
-		sliceVal, ok := f.Value.(flag.SliceValue)
-		if ok {
-			// We don't want to append the values (default pflag behavior).
-			items := strings.Split(val, ",")
-			err = sliceVal.Replace(items)
-			if err != nil {
-				fmt.Printf("[FATAL] invalid overriding value '%s' from %s: %v\n", val, envName, err)
-				os.Exit(1)
-			}
-			f.Changed = true
-			return
-		}
-
-		err = fs.Set(f.Name, val)
-		if err != nil {
-			fmt.Printf("[FATAL] invalid overriding value '%s' from %s: %v\n", val, envName, err)
-			os.Exit(1)
-		}
-	})
+	// Parse the flags.
+	err = parseFlags(fs, os.Args[1:])
+	if err != nil {
+		fmt.Printf("[FATAL] %s\n", err)
+		os.Exit(1)
+	}
 
 	// Create a wrapper around our flags.
 	parsedFlags := gotenberg.ParsedFlags{FlagSet: fs}
@@ -196,6 +148,24 @@ func Run() {
 		gotenberg.BuildDebug(ctx)
 	}
 
+	reloadSignal := make(chan os.Signal, 1)
+
+	// We'll reload the modules which support it on SIGHUP, without
+	// restarting the supervised processes.
+	signal.Notify(reloadSignal, syscall.SIGHUP)
+
+	go func() {
+		for range reloadSignal {
+			err := reload(ctx)
+			if err != nil {
+				fmt.Printf("[SYSTEM] reload configuration: %s\n", err)
+				continue
+			}
+
+			fmt.Println("[SYSTEM] configuration reloaded")
+		}
+	}()
+
 	quit := make(chan os.Signal, 1)
 
 	// We'll accept graceful shutdowns when quit via SIGINT (Ctrl+C) or SIGTERM (Kubernetes).
@@ -255,3 +225,133 @@ func Run() {
 
 	os.Exit(0)
 }
+
+// newFlagSet creates the root FlagSet and adds the flags of the given modules
+// to it.
+func newFlagSet(descriptors []gotenberg.ModuleDescriptor, errorHandling flag.ErrorHandling) (*flag.FlagSet, error) {
+	fs := flag.NewFlagSet("gotenberg", errorHandling)
+	fs.Bool("gotenberg-hide-banner", false, "Hide the banner")
+	fs.Duration("gotenberg-graceful-shutdown-duration", time.Duration(30)*time.Second, "Set the graceful shutdown duration")
+	fs.Bool("gotenberg-build-debug-data", true, "Set if build data is needed")
+	fs.String("config", "", "Set the path to a YAML or TOML configuration file, which keys are the flag names - flags and environment variables take precedence")
+
+	// Logging & telemetry flags.
+	fs.String("log-level", gotenberg.InfoLoggingLevel, "Set the log level")
+	fs.String("log-fields-prefix", "", "Prepend a specified prefix to each log field key")
+	fs.String("log-std-format", gotenberg.AutoLoggingFormat, "Set the log format for standard output")
+	fs.Bool("log-std-enable-gcp-fields", false, "Use GCP-compatible field names in log output")
+	fs.String("log-std-level-case", gotenberg.LowerLevelCase, "Set the case of the level field in the standard output, either lower or upper")
+
+	// Deprecated logging flags.
+	fs.String("log-format", gotenberg.AutoLoggingFormat, "Set the log format")
+	fs.Bool("log-enable-gcp-fields", false, "Use GCP-compatible field names")
+
+	err := errors.Join(
+		fs.MarkDeprecated("log-format", "use --log-std-format instead"),
+		fs.MarkDeprecated("log-enable-gcp-fields", "use --log-std-enable-gcp-fields instead"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("mark deprecated flags: %w", err)
+	}
+
+	for _, desc := range descriptors {
+		fs.AddFlagSet(desc.FlagSet)
+	}
+
+	return fs, nil
+}
+
+// parseFlags parses the command line arguments, then sets the flags not given
+// there from the environment variables and, finally, from the configuration
+// file, if any.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	// Flags from the command line take precedence over environment variables
+	// and the configuration file.
+	var sourceErr error
+	fs.Visit(func(f *flag.Flag) {
+		sourceErr = errors.Join(sourceErr, gotenberg.SetFlagSource(fs, f.Name, gotenberg.FlagSourceCommandLine))
+	})
+	if sourceErr != nil {
+		return fmt.Errorf("set flags source: %w", sourceErr)
+	}
+
+	// Set the values of the flags not given on the command line if the
+	// corresponding environment variables are set.
+	var envErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Changed {
+			return
+		}
+
+		envName := strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		val, ok := os.LookupEnv(envName)
+		if !ok {
+			return
+		}
+
+		sliceVal, ok := f.Value.(flag.SliceValue)
+		if ok {
+			// We don't want to append the values (default pflag behavior).
+			items := strings.Split(val, ",")
+			err = sliceVal.Replace(items)
+			f.Changed = err == nil
+		} else {
+			err = fs.Set(f.Name, val)
+		}
+		if err != nil {
+			envErr = errors.Join(envErr, fmt.Errorf("invalid overriding value '%s' from %s: %w", val, envName, err))
+			return
+		}
+
+		envErr = errors.Join(envErr, gotenberg.SetFlagSource(fs, f.Name, gotenberg.FlagSourceEnv))
+	})
+	if envErr != nil {
+		return envErr
+	}
+
+	// Finally, set the values of the remaining flags from the configuration
+	// file, if any.
+	configPath, err := fs.GetString("config")
+	if err != nil {
+		return fmt.Errorf("get configuration file path: %w", err)
+	}
+
+	if configPath == "" {
+		return nil
+	}
+
+	err = gotenberg.ApplyConfigFile(fs, configPath)
+	if err != nil {
+		return fmt.Errorf("invalid configuration file '%s': %w", configPath, err)
+	}
+
+	return nil
+}
+
+// reload parses the flags again and applies them to the modules which satisfy
+// the [gotenberg.Reloader] interface.
+func reload(ctx *gotenberg.Context) error {
+	// The FlagSets of the descriptors have already been parsed. New
+	// instances give us fresh ones.
+	descriptors := gotenberg.GetModuleDescriptors()
+	for i, desc := range descriptors {
+		descriptors[i] = desc.New().Descriptor()
+	}
+
+	fs, err := newFlagSet(descriptors, flag.ContinueOnError)
+	if err != nil {
+		return err
+	}
+
+	err = parseFlags(fs, os.Args[1:])
+	if err != nil {
+		return err
+	}
+
+	return ctx.Reload(gotenberg.ParsedFlags{FlagSet: fs})
+}
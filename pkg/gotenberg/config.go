@@ -0,0 +1,211 @@
+package gotenberg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	flag "github.com/spf13/pflag"
+	"go.yaml.in/yaml/v3"
+)
+
+// The sources of a flag value, from the lowest to the highest precedence.
+const (
+	FlagSourceDefault     = "default"
+	FlagSourceFile        = "file"
+	FlagSourceEnv         = "env"
+	FlagSourceCommandLine = "flag"
+)
+
+// flagSourceAnnotation is the [flag.Flag] annotation which records the source
+// of its value.
+const flagSourceAnnotation = "gotenberg_source"
+
+// SetFlagSource records the source of the value of a flag given by name, e.g.,
+// [FlagSourceEnv].
+func SetFlagSource(fs *flag.FlagSet, name, source string) error {
+	return fs.SetAnnotation(name, flagSourceAnnotation, []string{source})
+}
+
+// FlagSource returns the source of the value of a flag, or
+// [FlagSourceDefault] if none has been recorded.
+func FlagSource(f *flag.Flag) string {
+	source, ok := f.Annotations[flagSourceAnnotation]
+	if !ok || len(source) == 0 {
+		return FlagSourceDefault
+	}
+
+	return source[0]
+}
+
+// ApplyConfigFile sets the flags from a YAML (.yaml, .yml) or TOML (.toml)
+// configuration file. Keys are the flag names, either flat (e.g.,
+// "api-port: 3000") or nested by their dash-separated segments (e.g.,
+// "api: { port: 3000 }"). It skips flags which are already set, as the
+// command line and the environment variables take precedence over the file.
+// It returns an error if the file contains unknown keys.
+func ApplyConfigFile(fs *flag.FlagSet, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read configuration file: %w", err)
+	}
+
+	raw := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &raw)
+	case ".toml":
+		err = toml.Unmarshal(b, &raw)
+	default:
+		return fmt.Errorf("unsupported configuration file extension '%s': want '.yaml', '.yml' or '.toml'", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("parse configuration file: %w", err)
+	}
+
+	values := make(map[string]any)
+	err = flattenConfig("", raw, values)
+	if err != nil {
+		return fmt.Errorf("flatten configuration file: %w", err)
+	}
+
+	var unknown []string
+	for key := range values {
+		if fs.Lookup(key) == nil {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown configuration keys: %s", strings.Join(unknown, ", "))
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		f := fs.Lookup(key)
+		if f.Changed {
+			continue
+		}
+
+		setErr := setConfigValue(fs, f, values[key])
+		if setErr != nil {
+			err = errors.Join(err, fmt.Errorf("invalid value for '%s': %w", key, setErr))
+			continue
+		}
+
+		setErr = SetFlagSource(fs, key, FlagSourceFile)
+		if setErr != nil {
+			err = errors.Join(err, fmt.Errorf("set source of '%s': %w", key, setErr))
+		}
+	}
+
+	return err
+}
+
+// flattenConfig flattens the nested tables of a configuration file into
+// dash-separated keys.
+func flattenConfig(prefix string, raw map[string]any, values map[string]any) error {
+	for key, value := range raw {
+		if prefix != "" {
+			key = fmt.Sprintf("%s-%s", prefix, key)
+		}
+
+		table, ok := value.(map[string]any)
+		if ok {
+			err := flattenConfig(key, table, values)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, ok = values[key]
+		if ok {
+			return fmt.Errorf("duplicate key '%s'", key)
+		}
+
+		values[key] = value
+	}
+
+	return nil
+}
+
+// setConfigValue sets the value of a flag from a configuration file value.
+func setConfigValue(fs *flag.FlagSet, f *flag.Flag, value any) error {
+	items, isList := value.([]any)
+	sliceVal, isSlice := f.Value.(flag.SliceValue)
+
+	if !isList {
+		str, err := configScalar(value)
+		if err != nil {
+			return err
+		}
+
+		if isSlice {
+			// We don't want to append the values (default pflag behavior).
+			err = sliceVal.Replace(strings.Split(str, ","))
+			if err != nil {
+				return err
+			}
+			f.Changed = true
+
+			return nil
+		}
+
+		return fs.Set(f.Name, str)
+	}
+
+	if !isSlice {
+		return errors.New("got a list, but the flag does not support multiple values")
+	}
+
+	strs := make([]string, len(items))
+	for i, item := range items {
+		str, err := configScalar(item)
+		if err != nil {
+			return err
+		}
+		strs[i] = str
+	}
+
+	err := sliceVal.Replace(strs)
+	if err != nil {
+		return err
+	}
+	f.Changed = true
+
+	return nil
+}
+
+// configScalar converts a scalar value of a configuration file to its flag
+// string representation.
+func configScalar(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", value)
+	}
+}
@@ -0,0 +1,195 @@
+package gotenberg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+func TestApplyConfigFile(t *testing.T) {
+	newFlagSet := func() *flag.FlagSet {
+		fs := flag.NewFlagSet("tests", flag.ContinueOnError)
+		fs.Int("api-port", 3000, "")
+		fs.Duration("api-timeout", time.Duration(30)*time.Second, "")
+		fs.Bool("chromium-auto-start", false, "")
+		fs.Float64("chromium-scale", 1.0, "")
+		fs.StringSlice("pdfengines-merge-engines", []string{"qpdf"}, "")
+		fs.String("log-level", "info", "")
+		return fs
+	}
+
+	for _, tc := range []struct {
+		scenario      string
+		filename      string
+		content       string
+		args          []string
+		expectValues  map[string]string
+		expectSources map[string]string
+		expectError   string
+	}{
+		{
+			scenario: "flat YAML keys",
+			filename: "gotenberg.yaml",
+			content: `
+api-port: 4000
+api-timeout: 1m
+chromium-auto-start: true
+chromium-scale: 1.5
+pdfengines-merge-engines: [pdfcpu, pdftk]
+`,
+			expectValues: map[string]string{
+				"api-port":                 "4000",
+				"api-timeout":              "1m0s",
+				"chromium-auto-start":      "true",
+				"chromium-scale":           "1.5",
+				"pdfengines-merge-engines": "[pdfcpu,pdftk]",
+				"log-level":                "info",
+			},
+			expectSources: map[string]string{
+				"api-port":                 FlagSourceFile,
+				"api-timeout":              FlagSourceFile,
+				"chromium-auto-start":      FlagSourceFile,
+				"chromium-scale":           FlagSourceFile,
+				"pdfengines-merge-engines": FlagSourceFile,
+				"log-level":                FlagSourceDefault,
+			},
+		},
+		{
+			scenario: "nested YAML keys",
+			filename: "gotenberg.yml",
+			content: `
+api:
+  port: 4000
+pdfengines:
+  merge-engines: pdfcpu,pdftk
+`,
+			expectValues: map[string]string{
+				"api-port":                 "4000",
+				"pdfengines-merge-engines": "[pdfcpu,pdftk]",
+			},
+		},
+		{
+			scenario: "TOML tables",
+			filename: "gotenberg.toml",
+			content: `
+log-level = "debug"
+
+[api]
+port = 4000
+timeout = "10s"
+
+[pdfengines]
+merge-engines = ["pdfcpu"]
+`,
+			expectValues: map[string]string{
+				"api-port":                 "4000",
+				"api-timeout":              "10s",
+				"log-level":                "debug",
+				"pdfengines-merge-engines": "[pdfcpu]",
+			},
+		},
+		{
+			scenario: "command line takes precedence",
+			filename: "gotenberg.yaml",
+			content:  "api-port: 4000\nlog-level: debug\n",
+			args:     []string{"--api-port=5000"},
+			expectValues: map[string]string{
+				"api-port":  "5000",
+				"log-level": "debug",
+			},
+			expectSources: map[string]string{
+				"log-level": FlagSourceFile,
+			},
+		},
+		{
+			scenario:    "unknown keys",
+			filename:    "gotenberg.yaml",
+			content:     "api-port: 4000\nfoo: bar\napi:\n  bar: baz\n",
+			expectError: "unknown configuration keys: api-bar, foo",
+		},
+		{
+			scenario:    "duplicate keys",
+			filename:    "gotenberg.yaml",
+			content:     "api-port: 4000\napi:\n  port: 5000\n",
+			expectError: "duplicate key 'api-port'",
+		},
+		{
+			scenario:    "invalid value",
+			filename:    "gotenberg.yaml",
+			content:     "api-port: foo\n",
+			expectError: "invalid value for 'api-port'",
+		},
+		{
+			scenario:    "list for a single value flag",
+			filename:    "gotenberg.yaml",
+			content:     "log-level: [debug, info]\n",
+			expectError: "invalid value for 'log-level': got a list",
+		},
+		{
+			scenario:    "malformed file",
+			filename:    "gotenberg.toml",
+			content:     "api-port = \n",
+			expectError: "parse configuration file",
+		},
+		{
+			scenario:    "unsupported extension",
+			filename:    "gotenberg.json",
+			content:     "{}",
+			expectError: "unsupported configuration file extension '.json'",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.filename)
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			fs := newFlagSet()
+			err = fs.Parse(tc.args)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = ApplyConfigFile(fs, path)
+
+			if tc.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectError) {
+					t.Fatalf("expected error containing '%s' but got: %v", tc.expectError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			values := make(map[string]string)
+			for name := range tc.expectValues {
+				values[name] = fs.Lookup(name).Value.String()
+			}
+			if !reflect.DeepEqual(tc.expectValues, values) {
+				t.Errorf("expected values %+v but got %+v", tc.expectValues, values)
+			}
+
+			for name, expect := range tc.expectSources {
+				source := FlagSource(fs.Lookup(name))
+				if source != expect {
+					t.Errorf("expected source of '%s' to be '%s' but got '%s'", name, expect, source)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyConfigFile_MissingFile(t *testing.T) {
+	err := ApplyConfigFile(flag.NewFlagSet("tests", flag.ContinueOnError), filepath.Join(t.TempDir(), "gotenberg.yaml"))
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
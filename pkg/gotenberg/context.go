@@ -1,6 +1,7 @@
 package gotenberg
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 )
@@ -122,3 +123,29 @@ func (ctx *Context) loadModule(id string, instance any) error {
 
 	return nil
 }
+
+// Reload applies the given flags to the initialized modules which satisfy the
+// [Reloader] interface. A module failing to reload does not prevent the others
+// from applying their new settings; the errors are joined.
+func (ctx *Context) Reload(flags ParsedFlags) error {
+	var err error
+
+	for _, desc := range ctx.descriptors {
+		instance, ok := ctx.moduleInstances[desc.ID]
+		if !ok {
+			continue
+		}
+
+		reloader, ok := instance.(Reloader)
+		if !ok {
+			continue
+		}
+
+		reloadErr := reloader.Reload(flags)
+		if reloadErr != nil {
+			err = errors.Join(err, fmt.Errorf("reload module %s: %w", desc.ID, reloadErr))
+		}
+	}
+
+	return err
+}
@@ -2,6 +2,8 @@ package gotenberg
 
 import (
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -215,3 +217,58 @@ func TestContext_loadModule(t *testing.T) {
 		})
 	}
 }
+
+func TestContext_Reload(t *testing.T) {
+	var reloaded []string
+
+	newReloader := func(id string, err error) ModuleDescriptor {
+		mod := &struct {
+			ModuleMock
+			ReloaderMock
+		}{}
+		mod.DescriptorMock = func() ModuleDescriptor {
+			return ModuleDescriptor{ID: id, New: func() Module { return mod }}
+		}
+		mod.ReloadMock = func(flags ParsedFlags) error {
+			reloaded = append(reloaded, id)
+			return err
+		}
+		return mod.Descriptor()
+	}
+
+	notReloader := func() ModuleDescriptor {
+		mod := new(ModuleMock)
+		mod.DescriptorMock = func() ModuleDescriptor {
+			return ModuleDescriptor{ID: "baz", New: func() Module { return mod }}
+		}
+		return mod.Descriptor()
+	}()
+
+	ctx := NewContext(ParsedFlags{}, []ModuleDescriptor{
+		newReloader("foo", errors.New("foo")),
+		notReloader,
+		newReloader("bar", nil),
+		newReloader("qux", nil),
+	})
+
+	// Only initialized modules are reloaded.
+	for _, id := range []string{"foo", "bar", "baz"} {
+		for _, desc := range ctx.descriptors {
+			if desc.ID == id {
+				err := ctx.loadModule(id, desc.New())
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+		}
+	}
+
+	err := ctx.Reload(ParsedFlags{})
+	if err == nil || !strings.Contains(err.Error(), "reload module foo") {
+		t.Fatalf("expected error from module foo but got: %v", err)
+	}
+
+	if !reflect.DeepEqual(reloaded, []string{"foo", "bar"}) {
+		t.Errorf("expected modules foo and bar to be reloaded, got %v", reloaded)
+	}
+}
@@ -17,6 +17,7 @@ type DebugInfo struct {
 	Modules               []string                  `json:"modules"`
 	ModulesAdditionalData map[string]map[string]any `json:"modules_additional_data"`
 	Flags                 map[string]any            `json:"flags"`
+	FlagsSources          map[string]string         `json:"flags_sources"`
 }
 
 // BuildDebug builds the debug data from modules.
@@ -31,6 +32,7 @@ func BuildDebug(ctx *Context) {
 		Modules:               make([]string, len(ctx.moduleInstances)),
 		ModulesAdditionalData: make(map[string]map[string]any),
 		Flags:                 make(map[string]any),
+		FlagsSources:          make(map[string]string),
 	}
 
 	i := 0
@@ -50,6 +52,13 @@ func BuildDebug(ctx *Context) {
 
 	ctx.ParsedFlags().VisitAll(func(f *flag.Flag) {
 		debug.Flags[f.Name] = f.Value.String()
+
+		// Only the flags not using their default value, so that one may tell
+		// where the effective configuration comes from.
+		source := FlagSource(f)
+		if source != FlagSourceDefault {
+			debug.FlagsSources[f.Name] = source
+		}
 	})
 }
 
@@ -17,6 +17,15 @@ func TestBuildDebug(t *testing.T) {
 
 	fs := flag.NewFlagSet("gotenberg", flag.ExitOnError)
 	fs.String("foo", "bar", "Set foo")
+	fs.String("baz", "", "Set baz")
+	err := fs.Set("baz", "qux")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	err = SetFlagSource(fs, "baz", FlagSourceEnv)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
 	ctx := NewContext(ParsedFlags{
 		FlagSet: fs,
 	}, func() []ModuleDescriptor {
@@ -43,7 +52,7 @@ func TestBuildDebug(t *testing.T) {
 	}())
 
 	// Load modules.
-	_, err := ctx.Modules(new(Module))
+	_, err = ctx.Modules(new(Module))
 	if err != nil {
 		t.Errorf("expected no error but got: %v", err)
 	}
@@ -66,6 +75,10 @@ func TestBuildDebug(t *testing.T) {
 		},
 		Flags: map[string]any{
 			"foo": "bar",
+			"baz": "qux",
+		},
+		FlagsSources: map[string]string{
+			"baz": FlagSourceEnv,
 		},
 	}
 
@@ -0,0 +1,49 @@
+package gotenberg
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// UsedEngines gathers the engines which have processed a request, e.g., for
+// an audit record.
+type UsedEngines struct {
+	mu    sync.Mutex
+	names []string
+}
+
+type usedEnginesKey struct{}
+
+// ContextWithUsedEngines returns a copy of the context which gathers the
+// engines recorded with [RecordEngine].
+func ContextWithUsedEngines(ctx context.Context) (context.Context, *UsedEngines) {
+	engines := new(UsedEngines)
+	return context.WithValue(ctx, usedEnginesKey{}, engines), engines
+}
+
+// RecordEngine records that the engine with the given name has processed the
+// request of the context. It does nothing if the context does not gather the
+// used engines.
+func RecordEngine(ctx context.Context, name string) {
+	engines, ok := ctx.Value(usedEnginesKey{}).(*UsedEngines)
+	if !ok {
+		return
+	}
+
+	engines.mu.Lock()
+	defer engines.mu.Unlock()
+
+	if !slices.Contains(engines.names, name) {
+		engines.names = append(engines.names, name)
+	}
+}
+
+// Names returns the names of the used engines, in the order of their first
+// use.
+func (engines *UsedEngines) Names() []string {
+	engines.mu.Lock()
+	defer engines.mu.Unlock()
+
+	return slices.Clone(engines.names)
+}
@@ -0,0 +1,22 @@
+package gotenberg
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRecordEngine(t *testing.T) {
+	// Without a gatherer, it does nothing.
+	RecordEngine(context.Background(), "chromium")
+
+	ctx, engines := ContextWithUsedEngines(context.Background())
+	RecordEngine(ctx, "chromium")
+	RecordEngine(ctx, "qpdf")
+	RecordEngine(ctx, "chromium")
+
+	expect := []string{"chromium", "qpdf"}
+	if !reflect.DeepEqual(engines.Names(), expect) {
+		t.Errorf("expected engines %+v but got %+v", expect, engines.Names())
+	}
+}
@@ -11,8 +11,23 @@ type Metric struct {
 	Description string
 
 	// Read returns the current value.
-	// Required.
+	// Required, unless Label is set.
 	Read func() float64
+
+	// Counter tells if the value only ever increases, e.g., a total number
+	// of requests. Otherwise, the value is a gauge. Ignored if Label is set.
+	// Optional.
+	Counter bool
+
+	// Label is the name of the label distinguishing the series of a metric
+	// with one value per, e.g., client. If set, ReadSeries replaces Read.
+	// Optional.
+	Label string
+
+	// ReadSeries returns the current value of each series, keyed by label
+	// value.
+	// Required if Label is set.
+	ReadSeries func() map[string]float64
 }
 
 // MetricsProvider is a module interface which provides a list of [Metric].
@@ -33,6 +33,15 @@ func (mod *ValidatorMock) Validate() error {
 	return mod.ValidateMock()
 }
 
+// ReloaderMock is a mock for the [Reloader] interface.
+type ReloaderMock struct {
+	ReloadMock func(flags ParsedFlags) error
+}
+
+func (mod *ReloaderMock) Reload(flags ParsedFlags) error {
+	return mod.ReloadMock(flags)
+}
+
 type DebuggableMock struct {
 	DebugMock func() map[string]any
 }
@@ -172,6 +181,7 @@ type ProcessSupervisorMock struct {
 	HealthyMock                 func() bool
 	RunMock                     func(ctx context.Context, logger *slog.Logger, task func() error) error
 	ReqQueueSizeMock            func() int64
+	ReqQueueSizeByPriorityMock  func(priority Priority) int64
 	RestartsCountMock           func() int64
 	ActiveTasksCountMock        func() int64
 	ConversionsSinceRestartMock func() int64
@@ -197,6 +207,10 @@ func (s *ProcessSupervisorMock) ReqQueueSize() int64 {
 	return s.ReqQueueSizeMock()
 }
 
+func (s *ProcessSupervisorMock) ReqQueueSizeByPriority(priority Priority) int64 {
+	return s.ReqQueueSizeByPriorityMock(priority)
+}
+
 func (s *ProcessSupervisorMock) RestartsCount() int64 {
 	return s.RestartsCountMock()
 }
@@ -240,6 +254,7 @@ func (rename *PathRenameMock) Rename(oldpath, newpath string) error {
 var (
 	_ Module            = (*ModuleMock)(nil)
 	_ Validator         = (*ValidatorMock)(nil)
+	_ Reloader          = (*ReloaderMock)(nil)
 	_ PdfEngine         = (*PdfEngineMock)(nil)
 	_ PdfEngineProvider = (*PdfEngineProviderMock)(nil)
 	_ Process           = (*ProcessMock)(nil)
@@ -75,6 +75,15 @@ type SystemLogger interface {
 	SystemMessages() []string
 }
 
+// Reloader is a module interface for modules which may apply a new
+// configuration without being restarted, e.g., on SIGHUP. The flags are parsed
+// again from the command line, the environment variables and the
+// configuration file. A module must swap the settings it supports atomically
+// and keep its current ones if it returns an error.
+type Reloader interface {
+	Reload(flags ParsedFlags) error
+}
+
 // Debuggable is a module interface for modules which want to provide
 // additional debug data.
 type Debuggable interface {
@@ -0,0 +1,193 @@
+package gotenberg
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Priority is the class of a request in the queue of a [ProcessSupervisor].
+// Requests of a higher class are served first.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+// Priorities returns the priority classes, from the highest to the lowest.
+func Priorities() []Priority {
+	return []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+}
+
+// String returns the name of the priority class.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+// ParsePriority returns the priority class of the given name, either "high",
+// "normal" or "low".
+func ParsePriority(s string) (Priority, error) {
+	for _, p := range Priorities() {
+		if s == p.String() {
+			return p, nil
+		}
+	}
+
+	return PriorityNormal, fmt.Errorf("unknown priority '%s'", s)
+}
+
+type priorityKey struct{}
+
+// ContextWithPriority returns a copy of the context with the given priority
+// class.
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority class of the context, or
+// [PriorityNormal] if none.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, ok := ctx.Value(priorityKey{}).(Priority)
+	if !ok {
+		return PriorityNormal
+	}
+
+	return p
+}
+
+// priorityStarvationTimeout is the duration after which a waiter is served
+// before any other, regardless of its class, so that a steady flow of high
+// priority requests does not starve the low priority ones. Waiters past this
+// duration are served in arrival order.
+const priorityStarvationTimeout = time.Duration(10) * time.Second
+
+type priorityWaiter struct {
+	priority   Priority
+	enqueuedAt time.Time
+	turn       chan struct{}
+}
+
+// priorityQueue is a turnstile in front of the slots of a
+// [ProcessSupervisor]: only the waiter holding the turn contends for a slot,
+// and it hands the turn over to the next waiter by class once done.
+type priorityQueue struct {
+	mu                sync.Mutex
+	waiters           map[Priority][]*priorityWaiter
+	busy              bool
+	starvationTimeout time.Duration
+	now               func() time.Time
+}
+
+func newPriorityQueue(starvationTimeout time.Duration) *priorityQueue {
+	return &priorityQueue{
+		waiters:           make(map[Priority][]*priorityWaiter),
+		starvationTimeout: starvationTimeout,
+		now:               time.Now,
+	}
+}
+
+// wait blocks until the caller holds the turn or the context is done. On
+// success, the caller must call next once it no longer contends for a slot.
+func (q *priorityQueue) wait(ctx context.Context, p Priority) error {
+	q.mu.Lock()
+	if !q.busy {
+		q.busy = true
+		q.mu.Unlock()
+		return nil
+	}
+
+	w := &priorityWaiter{
+		priority:   p,
+		enqueuedAt: q.now(),
+		turn:       make(chan struct{}),
+	}
+	q.waiters[p] = append(q.waiters[p], w)
+	q.mu.Unlock()
+
+	select {
+	case <-w.turn:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		removed := q.remove(w)
+		q.mu.Unlock()
+
+		if !removed {
+			// The turn came in the meantime: hand it over.
+			q.next()
+		}
+
+		return ctx.Err()
+	}
+}
+
+// next hands the turn over to the next waiter, if any.
+func (q *priorityQueue) next() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	w := q.pop()
+	if w == nil {
+		q.busy = false
+		return
+	}
+
+	close(w.turn)
+}
+
+// pop removes and returns the next waiter: the oldest starving one if any,
+// otherwise the oldest one of the highest class. The caller must hold the
+// lock.
+func (q *priorityQueue) pop() *priorityWaiter {
+	var next *priorityWaiter
+	now := q.now()
+
+	for _, p := range Priorities() {
+		if len(q.waiters[p]) == 0 {
+			continue
+		}
+
+		head := q.waiters[p][0]
+		if now.Sub(head.enqueuedAt) >= q.starvationTimeout && (next == nil || head.enqueuedAt.Before(next.enqueuedAt)) {
+			next = head
+		}
+	}
+
+	if next == nil {
+		for _, p := range Priorities() {
+			if len(q.waiters[p]) > 0 {
+				next = q.waiters[p][0]
+				break
+			}
+		}
+	}
+
+	if next != nil {
+		q.waiters[next.priority] = q.waiters[next.priority][1:]
+	}
+
+	return next
+}
+
+// remove removes a waiter from the queue. It returns false if the waiter
+// is not in the queue anymore. The caller must hold the lock.
+func (q *priorityQueue) remove(w *priorityWaiter) bool {
+	for i, waiter := range q.waiters[w.priority] {
+		if waiter == w {
+			q.waiters[w.priority] = append(q.waiters[w.priority][:i], q.waiters[w.priority][i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParsePriority(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		s           string
+		expect      Priority
+		expectError bool
+	}{
+		{scenario: "high", s: "high", expect: PriorityHigh},
+		{scenario: "normal", s: "normal", expect: PriorityNormal},
+		{scenario: "low", s: "low", expect: PriorityLow},
+		{scenario: "unknown", s: "urgent", expect: PriorityNormal, expectError: true},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			p, err := ParsePriority(tc.s)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if p != tc.expect {
+				t.Errorf("expected priority '%s' but got '%s'", tc.expect, p)
+			}
+		})
+	}
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	if p := PriorityFromContext(context.Background()); p != PriorityNormal {
+		t.Errorf("expected priority '%s' but got '%s'", PriorityNormal, p)
+	}
+
+	ctx := ContextWithPriority(context.Background(), PriorityLow)
+	if p := PriorityFromContext(context.WithoutCancel(ctx)); p != PriorityLow {
+		t.Errorf("expected priority '%s' but got '%s'", PriorityLow, p)
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		// waiters are enqueued in this order, each one second after the
+		// previous.
+		waiters []Priority
+		expect  []int
+	}{
+		{
+			scenario: "by class then arrival order",
+			waiters:  []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityLow, PriorityHigh},
+			expect:   []int{2, 4, 1, 0, 3},
+		},
+		{
+			scenario: "starving waiters first",
+			waiters:  []Priority{PriorityLow, PriorityLow, PriorityHigh, PriorityHigh, PriorityHigh, PriorityHigh, PriorityHigh, PriorityHigh, PriorityHigh, PriorityHigh, PriorityHigh, PriorityHigh},
+			expect:   []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			now := time.Now()
+			q := newPriorityQueue(priorityStarvationTimeout)
+			q.now = func() time.Time { return now }
+
+			// Hold the turn.
+			err := q.wait(context.Background(), PriorityNormal)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			waiters := make(map[*priorityWaiter]int)
+			for i, p := range tc.waiters {
+				w := &priorityWaiter{priority: p, enqueuedAt: now, turn: make(chan struct{})}
+				q.waiters[p] = append(q.waiters[p], w)
+				waiters[w] = i
+				now = now.Add(time.Second)
+			}
+
+			var order []int
+			for range tc.waiters {
+				w := q.pop()
+				if w == nil {
+					t.Fatal("expected a waiter but got none")
+				}
+				order = append(order, waiters[w])
+			}
+
+			if q.pop() != nil {
+				t.Error("expected no more waiters")
+			}
+
+			for i := range tc.expect {
+				if order[i] != tc.expect[i] {
+					t.Fatalf("expected order %v but got %v", tc.expect, order)
+				}
+			}
+		})
+	}
+}
+
+func TestPriorityQueue_wait(t *testing.T) {
+	q := newPriorityQueue(priorityStarvationTimeout)
+
+	err := q.wait(context.Background(), PriorityNormal)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	// A waiter gives up before its turn.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = q.wait(ctx, PriorityHigh)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error but got: %v", err)
+	}
+
+	// The turn goes to the next waiter.
+	done := make(chan error)
+	go func() {
+		done <- q.wait(context.Background(), PriorityLow)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	q.next()
+
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the waiter to get the turn")
+	}
+
+	// No waiter left: the turn is free.
+	q.next()
+	if q.busy {
+		t.Error("expected the turn to be free")
+	}
+}
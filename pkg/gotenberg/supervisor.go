@@ -67,6 +67,8 @@ type ProcessSupervisor interface {
 	//
 	// Run manages the request queue and may restart the process if it is not
 	// healthy or if the number of handled requests exceeds the maximum limit.
+	// Queued tasks are served by priority class (see [PriorityFromContext]),
+	// then in arrival order.
 	//
 	// It returns an error if the task cannot be run or if the process state
 	// cannot be managed properly.
@@ -75,6 +77,10 @@ type ProcessSupervisor interface {
 	// ReqQueueSize returns the current size of the request queue.
 	ReqQueueSize() int64
 
+	// ReqQueueSizeByPriority returns the current size of the request queue
+	// for the given priority class.
+	ReqQueueSizeByPriority(priority Priority) int64
+
 	// RestartsCount returns the current number of restart.
 	RestartsCount() int64
 
@@ -111,6 +117,7 @@ type processSupervisor struct {
 	maxQueueSize   int64
 	maxConcurrency int64
 	semaphore      chan struct{}
+	queue          *priorityQueue
 	firstStart     atomic.Bool
 	// firstStartMu serializes lazy-launch attempts so concurrent callers do
 	// not all spawn Launch() simultaneously. Using a mutex (instead of
@@ -121,6 +128,7 @@ type processSupervisor struct {
 	firstStartMu        sync.Mutex
 	reqCounter          atomic.Int64
 	reqQueueSize        atomic.Int64
+	reqQueueSizes       [PriorityHigh + 1]atomic.Int64
 	restartsCounter     atomic.Int64
 	isRestarting        atomic.Bool
 	activeTasks         atomic.Int64
@@ -154,6 +162,7 @@ func NewProcessSupervisor(logger *slog.Logger, engine string, process Process, m
 		engine:              engine,
 		process:             process,
 		semaphore:           make(chan struct{}, maxConcurrency),
+		queue:               newPriorityQueue(priorityStarvationTimeout),
 		maxReqLimit:         maxReqLimit,
 		maxQueueSize:        maxQueueSize,
 		maxConcurrency:      maxConcurrency,
@@ -285,8 +294,10 @@ func (s *processSupervisor) recentlyHealthy() bool {
 func (s *processSupervisor) Run(ctx context.Context, logger *slog.Logger, task func() error) error {
 	// Time spent before the task body runs: queueing, slot acquisition, lazy
 	// launch, and health checks. Ended once, when the task is about to execute.
+	priority := PriorityFromContext(ctx)
 	_, queueSpan := Tracer().Start(ctx, s.engine+".queue.wait",
 		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attribute.String("gotenberg.queue.priority", priority.String())),
 	)
 	queueWaitDone := false
 	endQueueWait := func() {
@@ -316,6 +327,9 @@ func (s *processSupervisor) Run(ctx context.Context, logger *slog.Logger, task f
 	// See https://github.com/gotenberg/gotenberg/issues/1502.
 	defer s.reqQueueSize.Add(-1)
 
+	s.reqQueueSizes[priority].Add(1)
+	defer s.reqQueueSizes[priority].Add(-1)
+
 	for {
 		err := func() error {
 			if err := s.acquireSlot(ctx, logger); err != nil {
@@ -447,8 +461,17 @@ func (s *processSupervisor) maybeIdleShutdown() {
 }
 
 // acquireSlot attempts to acquire a semaphore slot, yielding it back if a
-// restart drain is in progress.
+// restart drain is in progress. Callers contend for a slot one at a time, by
+// priority class.
 func (s *processSupervisor) acquireSlot(ctx context.Context, logger *slog.Logger) error {
+	err := s.queue.wait(ctx, PriorityFromContext(ctx))
+	if err != nil {
+		logger.DebugContext(ctx, "failed to acquire process lock before deadline")
+
+		return fmt.Errorf("acquire process lock: %w", err)
+	}
+	defer s.queue.next()
+
 	select {
 	case s.semaphore <- struct{}{}:
 		// If a restart drain is in progress, release the slot
@@ -621,6 +644,14 @@ func (s *processSupervisor) ReqQueueSize() int64 {
 	return s.reqQueueSize.Load()
 }
 
+func (s *processSupervisor) ReqQueueSizeByPriority(priority Priority) int64 {
+	if priority < PriorityLow || priority > PriorityHigh {
+		return 0
+	}
+
+	return s.reqQueueSizes[priority].Load()
+}
+
 func (s *processSupervisor) RestartsCount() int64 {
 	return s.restartsCounter.Load()
 }
@@ -1158,3 +1158,66 @@ func TestProcessSupervisor_RunEmitsSubSpans(t *testing.T) {
 		t.Errorf("expected process start reason first_start, got %q", startReason)
 	}
 }
+
+func TestProcessSupervisor_RunByPriority(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	process := &ProcessMock{
+		StartMock: func(logger *slog.Logger) error {
+			return nil
+		},
+		HealthyMock: func(logger *slog.Logger) bool {
+			return true
+		},
+	}
+	ps := NewProcessSupervisor(logger, "test", process, 0, 0, 1, 0).(*processSupervisor)
+
+	// Simulating a lock.
+	ps.semaphore <- struct{}{}
+
+	var mu sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+
+	for _, p := range []Priority{PriorityNormal, PriorityLow, PriorityHigh, PriorityLow, PriorityHigh} {
+		ctx := ContextWithPriority(context.Background(), p)
+		wg.Go(func() {
+			err := ps.Run(ctx, logger, func() error {
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, p)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+
+		// Make sure the requests queue in this order.
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if ps.ReqQueueSizeByPriority(PriorityHigh) != 2 || ps.ReqQueueSizeByPriority(PriorityNormal) != 1 || ps.ReqQueueSizeByPriority(PriorityLow) != 2 {
+		t.Fatalf(
+			"expected queue sizes high=2, normal=1, low=2 but got high=%d, normal=%d, low=%d",
+			ps.ReqQueueSizeByPriority(PriorityHigh), ps.ReqQueueSizeByPriority(PriorityNormal), ps.ReqQueueSizeByPriority(PriorityLow),
+		)
+	}
+
+	// Release the lock.
+	<-ps.semaphore
+	wg.Wait()
+
+	// The first request holds the turn while waiting for the lock.
+	expect := []Priority{PriorityNormal, PriorityHigh, PriorityHigh, PriorityLow, PriorityLow}
+	for i := range expect {
+		if order[i] != expect[i] {
+			t.Fatalf("expected order %v but got %v", expect, order)
+		}
+	}
+
+	for _, p := range Priorities() {
+		if ps.ReqQueueSizeByPriority(p) != 0 {
+			t.Errorf("expected queue size of '%s' to be 0 but got %d", p, ps.ReqQueueSizeByPriority(p))
+		}
+	}
+}
@@ -2,13 +2,17 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"os"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexliesenfeld/health"
@@ -32,29 +36,55 @@ type Api struct {
 	bindIp                           string
 	tlsCertFile                      string
 	tlsKeyFile                       string
+	tlsClientCaFile                  string
+	tlsClientAuth                    string
+	tlsClientAllowList               []*regexp2.Regexp
 	startTimeout                     time.Duration
 	bodyLimit                        int64
 	timeout                          time.Duration
 	rootPath                         string
 	correlationIdHeader              string
-	basicAuthUsername                string
-	basicAuthPassword                string
 	oidcEnabled                      bool
 	oidcIssuer                       string
 	oidcAudience                     string
 	oidcJwksUrl                      string
-	downloadFromCfg                  downloadFromConfig
+	apiKeyAuthEnabled                bool
+	apiKeysFile                      string
+	settings                         atomic.Pointer[reloadableSettings]
 	disableHealthCheckRouteTelemetry bool
 	disableRootRouteTelemetry        bool
 	disableDebugRouteTelemetry       bool
 	disableVersionRouteTelemetry     bool
+	disableOpenApiRouteTelemetry     bool
 	enableDebugRoute                 bool
+	enableAdminRoutes                bool
+	disableOpenApiRoutes             bool
+	enableCache                      bool
+	cacheDir                         string
+	cacheMaxSize                     int64
+	cacheTtl                         time.Duration
+	enableUploads                    bool
+	uploadsMaxCount                  int
+	uploadsTtl                       time.Duration
+	enableIdempotency                bool
+	idempotencyDir                   string
+	idempotencyTtl                   time.Duration
+	auditLog                         string
+	auditLogMaxSize                  int64
+	auditLogMaxBackups               int
 
 	routes              []Route
 	externalMiddlewares []Middleware
 	healthChecks        []health.CheckerOption
 	readyFn             []func() error
 	asyncCounters       []AsynchronousCounter
+	drain               *drainState
+	audit               *auditLog
+	cache               *resultCache
+	uploads             *uploadStore
+	idempotency         *idempotencyStore
+	pdfEngine           gotenberg.PdfEngine
+	debuggables         map[string]gotenberg.Debuggable
 	fs                  *gotenberg.FileSystem
 	logger              *slog.Logger
 	srv                 *echo.Echo
@@ -94,6 +124,27 @@ type Route struct {
 	// Optional.
 	DisableTelemetry bool
 
+	// DisableCache excludes a "multipart/form-data" route from the cache of
+	// conversion results. It is for the routes whose result depends on more
+	// than the request, e.g., remote content or a state kept between
+	// requests, which the cache key does not cover.
+	// Optional.
+	DisableCache bool
+
+	// FormFieldSchemas are the JSON schemas of the custom form fields of a
+	// "multipart/form-data" route (see [FormData.MandatoryCustom]), by name,
+	// for its OpenAPI description. In a "multipart/form-data" body, such a
+	// field is JSON-encoded.
+	// Optional.
+	FormFieldSchemas map[string]json.RawMessage
+
+	// FilesDescription describes the files a "multipart/form-data" route
+	// processes, for its OpenAPI description, when its handler does not read
+	// them all by itself, e.g., the steps of a pipeline. It replaces the
+	// description of the recorded files.
+	// Optional.
+	FilesDescription string
+
 	// Handler is the function that handles the request.
 	// Required.
 	Handler echo.HandlerFunc
@@ -112,6 +163,10 @@ const (
 	DefaultStack MiddlewareStack = iota
 	PreRouterStack
 	MultipartStack
+	// PreMultipartStack is for the middlewares of the "multipart/form-data"
+	// routes which must run after the authentication, but before the
+	// request body is parsed.
+	PreMultipartStack
 )
 
 // MiddlewarePriority is a type that helps to determine the execution order of
@@ -185,6 +240,13 @@ type AsynchronousCounter interface {
 	AsyncCount() int64
 }
 
+// QueueCounter is a module interface that returns the number of queued and
+// active requests of its process supervisor.
+type QueueCounter interface {
+	QueuedCount() int64
+	ActiveCount() int64
+}
+
 // Descriptor returns an [Api]'s module descriptor.
 func (a *Api) Descriptor() gotenberg.ModuleDescriptor {
 	return gotenberg.ModuleDescriptor{
@@ -196,16 +258,23 @@ func (a *Api) Descriptor() gotenberg.ModuleDescriptor {
 			fs.String("api-bind-ip", "", "Set the IP address the API should bind to for incoming connections")
 			fs.String("api-tls-cert-file", "", "Path to the TLS/SSL certificate file - for HTTPS support")
 			fs.String("api-tls-key-file", "", "Path to the TLS/SSL key file - for HTTPS support")
+			fs.String("api-tls-client-ca-file", "", "Path to the CA bundle used to verify client certificates - enables mutual TLS")
+			fs.String("api-tls-client-auth", clientAuthRequire, "Set the client certificate verification mode - either require or optional")
+			fs.StringSlice("api-tls-client-allow-list", []string{}, "Set the allowed client certificate subjects and SANs using regular expressions - supports multiple values; requests without a client certificate, apart from the health and readiness probes, are then rejected")
 			fs.Duration("api-start-timeout", time.Duration(30)*time.Second, "Set the time limit for the API to start")
 			fs.Duration("api-timeout", time.Duration(30)*time.Second, "Set the time limit for requests")
 			fs.String("api-body-limit", "", "Set the body limit for multipart/form-data requests - it accepts values like 5MB, 1GB, etc")
 			fs.String("api-root-path", "/", "Set the root path of the API - for service discovery via URL paths")
 			fs.String("api-correlation-id-header", "Gotenberg-Trace", "Set the header name to use for identifying requests")
-			fs.Bool("api-enable-basic-auth", false, "Enable basic authentication - will look for the GOTENBERG_API_BASIC_AUTH_USERNAME and GOTENBERG_API_BASIC_AUTH_PASSWORD environment variables")
+			fs.Bool("api-enable-basic-auth", false, "Enable basic authentication - will look for the GOTENBERG_API_BASIC_AUTH_USERNAME and GOTENBERG_API_BASIC_AUTH_PASSWORD environment variables, or their _FILE variants pointing to files")
 			fs.Bool("api-enable-oidc-auth", false, "Enable OIDC bearer token authentication - mutually exclusive with basic authentication")
 			fs.String("api-oidc-issuer", "", "Set the OIDC issuer URL, e.g. https://tenant.example.com/ - the token 'iss' claim must match")
 			fs.String("api-oidc-audience", "", "Set the expected OIDC audience - the token 'aud' claim must contain it")
 			fs.String("api-oidc-jwks-url", "", "Set the OIDC JWKS URL - discovered from the issuer's well-known configuration when empty")
+			fs.Bool("api-enable-api-key-auth", false, "Enable API key authentication - mutually exclusive with basic and OIDC authentication")
+			fs.String("api-keys-file", "", "Set the path to the JSON file of API keys, stored as SHA-256 digests with their name, allowed routes and optional expiry - reloaded on change, checked at most once per second")
+			fs.StringSlice("api-high-priority-allow-list", []string{}, "Set the client identities allowed to send high priority requests using regular expressions - supports multiple values")
+			fs.StringSlice("api-high-priority-deny-list", []string{}, "Set the client identities denied to send high priority requests using regular expressions - supports multiple values")
 			fs.StringSlice("api-download-from-allow-list", []string{}, "Set the allowed URLs for the download from feature using regular expressions - supports multiple values")
 			fs.StringSlice("api-download-from-deny-list", []string{}, "Set the denied URLs for the download from feature using regular expressions - supports multiple values")
 			fs.Bool("api-download-from-deny-private-ips", false, "Reject downloadFrom URLs whose host resolves to a non-public IP address (loopback, RFC1918, link-local, unique-local). Enable on deployments that accept untrusted downloadFrom sources to mitigate SSRF against internal services")
@@ -213,11 +282,34 @@ func (a *Api) Descriptor() gotenberg.ModuleDescriptor {
 			fs.Bool("api-download-from-enable-environment-proxy", false, "Route downloadFrom fetches through the proxy defined by the standard HTTP_PROXY, HTTPS_PROXY, and NO_PROXY variables, including credentials")
 			fs.Int("api-download-from-max-retry", 4, "Set the maximum number of retries for the download from feature")
 			fs.Bool("api-disable-download-from", false, "Disable the download from feature")
+			fs.StringSlice("api-upload-to-allow-list", []string{}, "Set the allowed URLs for the upload to feature using regular expressions - supports multiple values")
+			fs.StringSlice("api-upload-to-deny-list", []string{}, "Set the denied URLs for the upload to feature using regular expressions - supports multiple values")
+			fs.Bool("api-upload-to-deny-private-ips", false, "Reject uploadTo URLs whose host resolves to a non-public IP address (loopback, RFC1918, link-local, unique-local). Enable on deployments that accept untrusted uploadTo destinations to mitigate SSRF against internal services")
+			fs.Bool("api-upload-to-deny-public-ips", false, "Reject uploadTo URLs whose host resolves to a public IP address. Enable on air-gapped or data-governed deployments to prevent uploads to the public internet")
+			fs.Bool("api-upload-to-enable-environment-proxy", false, "Route uploadTo requests through the proxy defined by the standard HTTP_PROXY, HTTPS_PROXY, and NO_PROXY variables, including credentials")
+			fs.Int("api-upload-to-max-retry", 4, "Set the maximum number of retries for the upload to feature")
+			fs.Bool("api-disable-upload-to", false, "Disable the upload to feature")
 			fs.Bool("api-disable-health-check-route-telemetry", true, "Disable telemetry for health check route")
 			fs.Bool("api-disable-root-route-telemetry", true, "Disable telemetry for the root route")
 			fs.Bool("api-disable-debug-route-telemetry", true, "Disable telemetry for the debug route")
 			fs.Bool("api-disable-version-route-telemetry", true, "Disable telemetry for the version route")
+			fs.Bool("api-disable-openapi-route-telemetry", true, "Disable telemetry for the OpenAPI specification and documentation routes")
 			fs.Bool("api-enable-debug-route", false, "Enable the debug route")
+			fs.Bool("api-enable-admin-routes", false, "Enable the admin routes to drain the instance before terminating it, and the readiness route, which fails while the instance is draining")
+			fs.Bool("api-disable-openapi-routes", false, "Disable the OpenAPI specification and documentation routes")
+			fs.Bool("api-enable-cache", false, "Enable the cache of conversion results, keyed on the route, the files, the form fields and the engine versions - the routes converting remote content, e.g., URLs, are not cached")
+			fs.String("api-cache-dir", "", "Set the directory in which to create the directory of the cache - default to the system's temporary directory")
+			fs.String("api-cache-max-size", "1GB", "Set the maximum size of the cache - it accepts values like 500MB, 1GB, etc - the least recently used results are evicted first")
+			fs.Duration("api-cache-ttl", time.Duration(1)*time.Hour, "Set the time-to-live of a cached result")
+			fs.Bool("api-enable-uploads", false, "Enable the resumable uploads routes - completed uploads may be referenced by conversion routes with the uploads form field")
+			fs.Int("api-uploads-max-count", 1000, "Set the maximum number of uploads kept at once - 0 means no limit")
+			fs.Duration("api-uploads-ttl", time.Duration(1)*time.Hour, "Set the time after which an upload without activity expires")
+			fs.Bool("api-enable-idempotency", false, "Honor the Idempotency-Key header on conversion routes - duplicates of a successful request receive its stored response instead of running the conversion again")
+			fs.String("api-idempotency-dir", "", "Set the directory in which to create the directory of the stored responses - default to the system's temporary directory")
+			fs.Duration("api-idempotency-ttl", time.Duration(24)*time.Hour, "Set the time during which a stored response is replayed to the duplicates of a request")
+			fs.String("api-audit-log", "", "Write an audit record of each conversion to this JSON Lines file, or to the standard output with 'stdout' - each record holds the hash of the previous one, so that a deleted or altered record is detectable")
+			fs.String("api-audit-log-max-size", "100MB", "Set the size after which the audit log file is rotated - it accepts values like 500MB, 1GB, etc - 0 disables the rotation")
+			fs.Int("api-audit-log-max-backups", 5, "Set the maximum number of rotated audit log files to keep - it must be strictly positive when the rotation is enabled")
 
 			// Deprecated flags.
 			fs.String("api-trace-header", "Gotenberg-Trace", "Set the header name to use for identifying requests")
@@ -243,25 +335,43 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 	a.bindIp = flags.MustString("api-bind-ip")
 	a.tlsCertFile = flags.MustString("api-tls-cert-file")
 	a.tlsKeyFile = flags.MustString("api-tls-key-file")
+	a.tlsClientCaFile = flags.MustString("api-tls-client-ca-file")
+	a.tlsClientAuth = flags.MustString("api-tls-client-auth")
+	a.tlsClientAllowList = flags.MustRegexpSlice("api-tls-client-allow-list")
 	a.startTimeout = flags.MustDuration("api-start-timeout")
 	a.timeout = flags.MustDuration("api-timeout")
 	a.bodyLimit = flags.MustHumanReadableBytes("api-body-limit")
 	a.rootPath = flags.MustString("api-root-path")
 	a.correlationIdHeader = flags.MustDeprecatedString("api-trace-header", "api-correlation-id-header")
-	a.downloadFromCfg = downloadFromConfig{
-		allowList:              flags.MustRegexpSlice("api-download-from-allow-list"),
-		denyList:               flags.MustRegexpSlice("api-download-from-deny-list"),
-		denyPrivateIPs:         flags.MustBool("api-download-from-deny-private-ips"),
-		denyPublicIPs:          flags.MustBool("api-download-from-deny-public-ips"),
-		enableEnvironmentProxy: flags.MustBool("api-download-from-enable-environment-proxy"),
-		maxRetry:               flags.MustInt("api-download-from-max-retry"),
-		disable:                flags.MustBool("api-disable-download-from"),
-	}
 	a.disableHealthCheckRouteTelemetry = flags.MustDeprecatedBool("api-disable-health-check-logging", "api-disable-health-check-route-telemetry")
 	a.disableRootRouteTelemetry = flags.MustBool("api-disable-root-route-telemetry")
 	a.disableDebugRouteTelemetry = flags.MustBool("api-disable-debug-route-telemetry")
 	a.disableVersionRouteTelemetry = flags.MustBool("api-disable-version-route-telemetry")
+	a.disableOpenApiRouteTelemetry = flags.MustBool("api-disable-openapi-route-telemetry")
 	a.enableDebugRoute = flags.MustBool("api-enable-debug-route")
+	a.enableAdminRoutes = flags.MustBool("api-enable-admin-routes")
+	a.disableOpenApiRoutes = flags.MustBool("api-disable-openapi-routes")
+	a.enableCache = flags.MustBool("api-enable-cache")
+	a.cacheDir = flags.MustString("api-cache-dir")
+	a.cacheMaxSize = flags.MustHumanReadableBytes("api-cache-max-size")
+	a.cacheTtl = flags.MustDuration("api-cache-ttl")
+	a.enableUploads = flags.MustBool("api-enable-uploads")
+	a.uploadsMaxCount = flags.MustInt("api-uploads-max-count")
+	a.uploadsTtl = flags.MustDuration("api-uploads-ttl")
+	a.enableIdempotency = flags.MustBool("api-enable-idempotency")
+	a.idempotencyDir = flags.MustString("api-idempotency-dir")
+	a.idempotencyTtl = flags.MustDuration("api-idempotency-ttl")
+	a.auditLog = flags.MustString("api-audit-log")
+	a.auditLogMaxSize = flags.MustHumanReadableBytes("api-audit-log-max-size")
+	a.auditLogMaxBackups = flags.MustInt("api-audit-log-max-backups")
+
+	if a.cacheDir == "" {
+		a.cacheDir = os.TempDir()
+	}
+
+	if a.idempotencyDir == "" {
+		a.idempotencyDir = os.TempDir()
+	}
 
 	// Port from env?
 	portEnvVar := flags.MustString("api-port-from-env")
@@ -273,20 +383,12 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 		a.port = port
 	}
 
-	// Enable basic auth?
-	enableBasicAuth := flags.MustBool("api-enable-basic-auth")
-	if enableBasicAuth {
-		basicAuthUsername, err := gotenberg.StringEnv("GOTENBERG_API_BASIC_AUTH_USERNAME")
-		if err != nil {
-			return fmt.Errorf("get basic auth username from env: %w", err)
-		}
-		basicAuthPassword, err := gotenberg.StringEnv("GOTENBERG_API_BASIC_AUTH_PASSWORD")
-		if err != nil {
-			return fmt.Errorf("get basic auth password from env: %w", err)
-		}
-		a.basicAuthUsername = basicAuthUsername
-		a.basicAuthPassword = basicAuthPassword
+	// Outbound allow/deny lists, high priority lists and basic auth.
+	settings, err := newReloadableSettings(flags)
+	if err != nil {
+		return err
 	}
+	a.settings.Store(settings)
 
 	// Enable OIDC auth? The flags are populated from their API_OIDC_* env vars
 	// by the CLI, so no manual environment lookup is needed here.
@@ -297,6 +399,12 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 		a.oidcJwksUrl = flags.MustString("api-oidc-jwks-url")
 	}
 
+	// Enable API key auth?
+	a.apiKeyAuthEnabled = flags.MustBool("api-enable-api-key-auth")
+	if a.apiKeyAuthEnabled {
+		a.apiKeysFile = flags.MustString("api-keys-file")
+	}
+
 	// Get routes from modules.
 	mods, err := ctx.Modules(new(Router))
 	if err != nil {
@@ -374,6 +482,49 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 		a.asyncCounters[i] = asyncCounter.(AsynchronousCounter)
 	}
 
+	// Get queue counters.
+	mods, err = ctx.Modules(new(QueueCounter))
+	if err != nil {
+		return fmt.Errorf("get queue counters: %w", err)
+	}
+
+	queueCounters := make([]QueueCounter, len(mods))
+	for i, queueCounter := range mods {
+		queueCounters[i] = queueCounter.(QueueCounter)
+	}
+
+	a.drain = &drainState{
+		queueCounters: queueCounters,
+		asyncCounters: a.asyncCounters,
+	}
+
+	// Get the PDF engine, if any, which counts the pages of the PDFs listed
+	// in the manifest of an archive.
+	mods, err = ctx.Modules(new(gotenberg.PdfEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get PDF engine providers: %w", err)
+	}
+
+	if len(mods) == 1 {
+		a.pdfEngine, err = mods[0].(gotenberg.PdfEngineProvider).PdfEngine()
+		if err != nil {
+			return fmt.Errorf("get PDF engine: %w", err)
+		}
+	}
+
+	// Get debuggable modules, as their versions are part of the cache keys.
+	if a.enableCache {
+		mods, err = ctx.Modules(new(gotenberg.Debuggable))
+		if err != nil {
+			return fmt.Errorf("get debuggables: %w", err)
+		}
+
+		a.debuggables = make(map[string]gotenberg.Debuggable, len(mods))
+		for _, debuggable := range mods {
+			a.debuggables[debuggable.(gotenberg.Module).Descriptor().ID] = debuggable.(gotenberg.Debuggable)
+		}
+	}
+
 	// Logger.
 	a.logger = gotenberg.Logger(a)
 
@@ -397,11 +548,10 @@ func (a *Api) Validate() error {
 		err = errors.Join(err, errors.New("IP must be a valid IP address"))
 	}
 
-	if a.downloadFromCfg.enableEnvironmentProxy {
-		proxyErr := gotenberg.ValidateEnvironmentProxyVariables()
-		if proxyErr != nil {
-			err = errors.Join(err, fmt.Errorf("--api-download-from-enable-environment-proxy is set: %w", proxyErr))
-		}
+	settings := a.settings.Load()
+	settingsErr := settings.validate()
+	if settingsErr != nil {
+		err = errors.Join(err, settingsErr)
 	}
 
 	if (a.tlsCertFile != "" && a.tlsKeyFile == "") || (a.tlsCertFile == "" && a.tlsKeyFile != "") {
@@ -410,6 +560,24 @@ func (a *Api) Validate() error {
 		)
 	}
 
+	if a.tlsClientCaFile != "" && a.tlsCertFile == "" {
+		err = errors.Join(err,
+			errors.New("TLS certificate and key files must be set to verify client certificates"),
+		)
+	}
+
+	if a.tlsClientAuth != clientAuthRequire && a.tlsClientAuth != clientAuthOptional {
+		err = errors.Join(err,
+			fmt.Errorf("client certificate verification mode must be either '%s' or '%s'", clientAuthRequire, clientAuthOptional),
+		)
+	}
+
+	if len(a.tlsClientAllowList) > 0 && a.tlsClientCaFile == "" {
+		err = errors.Join(err,
+			errors.New("client CA file must be set to use a client certificate allow list; set --api-tls-client-ca-file"),
+		)
+	}
+
 	if !strings.HasPrefix(a.rootPath, "/") {
 		err = errors.Join(err,
 			errors.New("root path must start with /"),
@@ -428,12 +596,72 @@ func (a *Api) Validate() error {
 		)
 	}
 
-	if a.basicAuthUsername != "" && a.oidcEnabled {
+	if settings.basicAuthUsername != "" && a.oidcEnabled {
 		err = errors.Join(err,
 			errors.New("basic authentication and OIDC authentication cannot both be enabled"),
 		)
 	}
 
+	if a.apiKeyAuthEnabled && (settings.basicAuthUsername != "" || a.oidcEnabled) {
+		err = errors.Join(err,
+			errors.New("API key authentication cannot be enabled with basic or OIDC authentication"),
+		)
+	}
+
+	if a.apiKeyAuthEnabled && a.apiKeysFile == "" {
+		err = errors.Join(err,
+			errors.New("API keys file must not be empty when API key auth is enabled; set --api-keys-file"),
+		)
+	}
+
+	if a.enableCache && a.cacheMaxSize < 0 {
+		err = errors.Join(err,
+			errors.New("cache max size must be positive"),
+		)
+	}
+
+	if a.enableCache && a.cacheTtl < 0 {
+		err = errors.Join(err,
+			errors.New("cache TTL must be positive"),
+		)
+	}
+
+	if a.enableUploads && a.uploadsTtl <= 0 {
+		err = errors.Join(err,
+			errors.New("uploads TTL must be strictly positive"),
+		)
+	}
+
+	if a.enableUploads && a.uploadsMaxCount < 0 {
+		err = errors.Join(err,
+			errors.New("uploads max count must be positive"),
+		)
+	}
+
+	if a.enableIdempotency && a.idempotencyTtl <= 0 {
+		err = errors.Join(err,
+			errors.New("idempotency TTL must be strictly positive"),
+		)
+	}
+
+	if a.auditLog != "" && a.auditLogMaxSize < 0 {
+		err = errors.Join(err,
+			errors.New("audit log max size must be positive"),
+		)
+	}
+
+	if a.auditLog != "" && a.auditLogMaxBackups < 0 {
+		err = errors.Join(err,
+			errors.New("audit log max backups must be positive"),
+		)
+	}
+
+	if a.auditLog != "" && a.auditLog != auditLogStdout && a.auditLogMaxSize > 0 && a.auditLogMaxBackups == 0 {
+		err = errors.Join(err,
+			errors.New("audit log max backups must be strictly positive when the rotation is enabled"),
+		)
+	}
+
 	if a.oidcEnabled {
 		if a.oidcIssuer == "" {
 			err = errors.Join(err,
@@ -451,10 +679,15 @@ func (a *Api) Validate() error {
 		return err
 	}
 
-	routesMap := make(map[string]string, len(a.routes)+3)
+	routesMap := make(map[string]string, len(a.routes)+8)
 	routesMap["/health"] = "/health"
+	routesMap["/ready"] = "/ready"
 	routesMap["/version"] = "/version"
 	routesMap["/debug"] = "/debug"
+	routesMap["/admin/drain"] = "/admin/drain"
+	routesMap["/admin/undrain"] = "/admin/undrain"
+	routesMap["/openapi.json"] = "/openapi.json"
+	routesMap["/docs"] = "/docs"
 
 	for _, route := range a.routes {
 		if route.Path == "" {
@@ -504,6 +737,26 @@ func (a *Api) Start() error {
 	a.srv.Server.WriteTimeout = a.timeout + a.timeout
 	a.srv.HTTPErrorHandler = httpErrorHandler()
 
+	// Describe the modules' routes before altering their paths.
+	var openApiSpec, openApiDocs []byte
+	if !a.disableOpenApiRoutes {
+		doc := openApiBuilder{
+			rootPath:            a.rootPath,
+			correlationIdHeader: a.correlationIdHeader,
+			enableDebugRoute:    a.enableDebugRoute,
+			enableAdminRoutes:   a.enableAdminRoutes,
+			enableUploads:       a.enableUploads,
+			enableIdempotency:   a.enableIdempotency,
+			logger:              a.logger,
+		}.build(a.routes)
+
+		var err error
+		openApiSpec, openApiDocs, err = renderOpenApi(doc)
+		if err != nil {
+			return fmt.Errorf("render OpenAPI: %w", err)
+		}
+	}
+
 	// Let's prepare the modules' routes.
 	var disableTelemetryForPaths []string
 	for i, route := range a.routes {
@@ -516,7 +769,7 @@ func (a *Api) Start() error {
 
 	// Check if the user wishes to disable telemetry for specific routes.
 	if a.disableHealthCheckRouteTelemetry {
-		disableTelemetryForPaths = append(disableTelemetryForPaths, "health")
+		disableTelemetryForPaths = append(disableTelemetryForPaths, "health", "ready")
 	}
 	if a.disableRootRouteTelemetry {
 		disableTelemetryForPaths = append(disableTelemetryForPaths, "")
@@ -527,23 +780,39 @@ func (a *Api) Start() error {
 	if a.disableVersionRouteTelemetry {
 		disableTelemetryForPaths = append(disableTelemetryForPaths, "version")
 	}
+	if a.disableOpenApiRouteTelemetry {
+		disableTelemetryForPaths = append(disableTelemetryForPaths, "openapi.json", "docs")
+	}
 
 	serverName := fmt.Sprintf("%s:%d", a.bindIp, a.port)
 
+	// Audit log?
+	var audit *auditLog
+	if a.auditLog != "" {
+		var err error
+		audit, err = newAuditLog(a.auditLog, a.auditLogMaxSize, a.auditLogMaxBackups)
+		if err != nil {
+			return fmt.Errorf("create audit log: %w", err)
+		}
+		a.audit = audit
+	}
+
 	// Add the API middlewares.
 	a.srv.Pre(
 		latencyMiddleware(),
 		rootPathMiddleware(a.rootPath),
 		outputFilenameMiddleware(),
-		telemetryMiddleware(a.logger, serverName, a.correlationIdHeader, disableTelemetryForPaths),
+		telemetryMiddleware(a.logger, serverName, a.correlationIdHeader, disableTelemetryForPaths, audit),
 	)
 
 	// Add the modules' middlewares in their respective stacks.
-	var externalMultipartMiddlewares []Middleware
+	var externalPreMultipartMiddlewares, externalMultipartMiddlewares []Middleware
 	for _, externalMiddleware := range a.externalMiddlewares {
 		switch externalMiddleware.Stack {
 		case PreRouterStack:
 			a.srv.Pre(externalMiddleware.Handler)
+		case PreMultipartStack:
+			externalPreMultipartMiddlewares = append(externalPreMultipartMiddlewares, externalMiddleware)
 		case MultipartStack:
 			externalMultipartMiddlewares = append(externalMultipartMiddlewares, externalMiddleware)
 		case DefaultStack:
@@ -551,19 +820,41 @@ func (a *Api) Start() error {
 		}
 	}
 
+	// Mutual TLS?
+	var tlsConfig *tls.Config
+	if a.tlsClientCaFile != "" {
+		var err error
+		tlsConfig, err = buildMutualTlsConfig(a.tlsCertFile, a.tlsKeyFile, a.tlsClientCaFile, a.tlsClientAuth)
+		if err != nil {
+			return fmt.Errorf("build mutual TLS config: %w", err)
+		}
+
+		probePaths := []string{
+			fmt.Sprintf("%s%s", a.rootPath, "health"),
+			fmt.Sprintf("%s%s", a.rootPath, "ready"),
+		}
+		a.srv.Use(clientCertMiddleware(a.tlsClientAllowList, a.timeout, probePaths))
+	}
+
 	hardTimeout := a.timeout + (time.Duration(5) * time.Second)
 
 	// Authentication?
 	var securityMiddleware echo.MiddlewareFunc
 	switch {
-	case a.basicAuthUsername != "":
-		securityMiddleware = basicAuthMiddleware(a.basicAuthUsername, a.basicAuthPassword)
+	case a.settings.Load().basicAuthUsername != "":
+		securityMiddleware = basicAuthMiddleware(&a.settings)
 	case a.oidcEnabled:
 		verifier, err := a.buildOidcVerifier()
 		if err != nil {
 			return fmt.Errorf("build OIDC verifier: %w", err)
 		}
 		securityMiddleware = oidcAuthMiddleware(verifier)
+	case a.apiKeyAuthEnabled:
+		store, err := newApiKeyStore(a.apiKeysFile)
+		if err != nil {
+			return fmt.Errorf("load API keys: %w", err)
+		}
+		securityMiddleware = apiKeyAuthMiddleware(store)
 	default:
 		securityMiddleware = func(next echo.HandlerFunc) echo.HandlerFunc {
 			return func(c echo.Context) error {
@@ -572,13 +863,81 @@ func (a *Api) Start() error {
 		}
 	}
 
+	// Result cache?
+	var cache *resultCache
+	if a.enableCache {
+		debug := make(map[string]map[string]any, len(a.debuggables))
+		for id, debuggable := range a.debuggables {
+			debug[id] = debuggable.Debug()
+		}
+
+		versions, err := json.Marshal(debug)
+		if err != nil {
+			return fmt.Errorf("marshal engine versions: %w", err)
+		}
+
+		cache, err = newResultCache(a.cacheDir, a.cacheMaxSize, a.cacheTtl, string(versions))
+		if err != nil {
+			return fmt.Errorf("create result cache: %w", err)
+		}
+		a.cache = cache
+	}
+
+	// Resumable uploads?
+	var uploads *uploadStore
+	if a.enableUploads {
+		uploads = newUploadStore(a.fs, a.bodyLimit, a.uploadsMaxCount, a.uploadsTtl)
+		uploads.start()
+		a.uploads = uploads
+	}
+
+	// Idempotency?
+	var idempotency *idempotencyStore
+	if a.enableIdempotency {
+		var err error
+		idempotency, err = newIdempotencyStore(a.idempotencyDir, a.idempotencyTtl)
+		if err != nil {
+			return fmt.Errorf("create idempotency store: %w", err)
+		}
+		a.idempotency = idempotency
+	}
+
 	// Add the modules' routes and their specific middlewares.
 	for _, route := range a.routes {
 		var middlewares []echo.MiddlewareFunc
 		middlewares = append(middlewares, securityMiddleware)
 
 		if route.IsMultipart {
-			middlewares = append(middlewares, contextMiddleware(a.fs, a.timeout, a.bodyLimit, a.downloadFromCfg))
+			for _, externalPreMultipartMiddleware := range externalPreMultipartMiddlewares {
+				middlewares = append(middlewares, externalPreMultipartMiddleware.Handler)
+			}
+		}
+
+		// Before the drain middleware, so that a draining instance still
+		// replays the stored responses.
+		if route.IsMultipart && idempotency != nil {
+			middlewares = append(middlewares, idempotencyMiddleware(idempotency, a.timeout))
+		}
+
+		if route.IsMultipart && a.enableAdminRoutes {
+			middlewares = append(middlewares, drainMiddleware(a.drain))
+		}
+
+		if route.IsMultipart {
+			middlewares = append(middlewares, priorityMiddleware(&a.settings, a.timeout))
+		}
+
+		handler := route.Handler
+		if route.IsMultipart && !route.DisableCache && cache != nil {
+			handler = cacheHandler(cache, route)
+		}
+
+		if route.IsMultipart {
+			middlewares = append(middlewares, contextMiddleware(a.fs, a.timeout, a.bodyLimit, &a.settings, uploads, a.pdfEngine))
+
+			if idempotency != nil {
+				middlewares = append(middlewares, idempotencyFingerprintMiddleware(idempotency))
+			}
 
 			for _, externalMultipartMiddleware := range externalMultipartMiddlewares {
 				middlewares = append(middlewares, externalMultipartMiddleware.Handler)
@@ -590,7 +949,7 @@ func (a *Api) Start() error {
 		a.srv.Add(
 			route.Method,
 			fmt.Sprintf("%s%s", a.rootPath, route.Path),
-			route.Handler,
+			handler,
 			middlewares...,
 		)
 	}
@@ -635,6 +994,26 @@ func (a *Api) Start() error {
 		hardTimeoutMiddleware(hardTimeout),
 	)
 
+	// ...the readiness routes, which also fail while the instance is
+	// draining...
+	if a.enableAdminRoutes {
+		readinessChecks := make([]health.CheckerOption, len(checks), len(checks)+1)
+		copy(readinessChecks, checks)
+		readinessChecks = append(readinessChecks, a.drain.readinessCheck())
+		readinessCheckHandler := health.NewHandler(health.NewChecker(readinessChecks...))
+
+		a.srv.GET(
+			fmt.Sprintf("%s%s", a.rootPath, "ready"),
+			echo.WrapHandler(readinessCheckHandler),
+			hardTimeoutMiddleware(hardTimeout),
+		)
+		a.srv.HEAD(
+			fmt.Sprintf("%s%s", a.rootPath, "ready"),
+			echo.WrapHandler(readinessCheckHandler),
+			hardTimeoutMiddleware(hardTimeout),
+		)
+	}
+
 	// ...the version route.
 	a.srv.GET(
 		fmt.Sprintf("%s%s", a.rootPath, "version"),
@@ -644,6 +1023,71 @@ func (a *Api) Start() error {
 		securityMiddleware,
 	)
 
+	// ...the resumable uploads routes...
+	if uploads != nil {
+		a.srv.POST(
+			fmt.Sprintf("%s%s", a.rootPath, "uploads"),
+			createUploadHandler(uploads, a.rootPath),
+			securityMiddleware,
+			tusMiddleware(),
+		)
+		a.srv.HEAD(
+			fmt.Sprintf("%s%s", a.rootPath, "uploads/:id"),
+			uploadOffsetHandler(uploads),
+			securityMiddleware,
+			tusMiddleware(),
+		)
+		a.srv.PATCH(
+			fmt.Sprintf("%s%s", a.rootPath, "uploads/:id"),
+			patchUploadHandler(uploads),
+			securityMiddleware,
+			tusMiddleware(),
+		)
+		a.srv.DELETE(
+			fmt.Sprintf("%s%s", a.rootPath, "uploads/:id"),
+			deleteUploadHandler(uploads),
+			securityMiddleware,
+			tusMiddleware(),
+		)
+	}
+
+	// ...the OpenAPI routes...
+	if !a.disableOpenApiRoutes {
+		a.srv.GET(
+			fmt.Sprintf("%s%s", a.rootPath, "openapi.json"),
+			func(c echo.Context) error {
+				return c.JSONBlob(http.StatusOK, openApiSpec)
+			},
+			securityMiddleware,
+		)
+		a.srv.GET(
+			fmt.Sprintf("%s%s", a.rootPath, "docs"),
+			func(c echo.Context) error {
+				return c.HTMLBlob(http.StatusOK, openApiDocs)
+			},
+			securityMiddleware,
+		)
+	}
+
+	// ...the admin routes...
+	if a.enableAdminRoutes {
+		a.srv.GET(
+			fmt.Sprintf("%s%s", a.rootPath, "admin/drain"),
+			drainStatusHandler(a.drain),
+			securityMiddleware,
+		)
+		a.srv.POST(
+			fmt.Sprintf("%s%s", a.rootPath, "admin/drain"),
+			drainHandler(a.drain, true),
+			securityMiddleware,
+		)
+		a.srv.POST(
+			fmt.Sprintf("%s%s", a.rootPath, "admin/undrain"),
+			drainHandler(a.drain, false),
+			securityMiddleware,
+		)
+	}
+
 	// ...and the debug route.
 	if a.enableDebugRoute {
 		a.srv.GET(
@@ -672,7 +1116,13 @@ func (a *Api) Start() error {
 	// As the following code is blocking, run it in a goroutine.
 	go func() {
 		var err error
-		if a.tlsCertFile != "" && a.tlsKeyFile != "" {
+		if tlsConfig != nil {
+			// Start an HTTPS server verifying client certificates (supports
+			// HTTP/2).
+			a.srv.TLSServer.Addr = fmt.Sprintf("%s:%d", a.bindIp, a.port)
+			a.srv.TLSServer.TLSConfig = tlsConfig
+			err = a.srv.StartServer(a.srv.TLSServer)
+		} else if a.tlsCertFile != "" && a.tlsKeyFile != "" {
 			// Start an HTTPS server (supports HTTP/2).
 			err = a.srv.StartTLS(fmt.Sprintf("%s:%d", a.bindIp, a.port), a.tlsCertFile, a.tlsKeyFile)
 		} else {
@@ -699,6 +1149,8 @@ func (a *Api) StartupMessage() string {
 
 // Stop stops the HTTP server.
 func (a *Api) Stop(ctx context.Context) error {
+	defer a.closeStores()
+
 	for {
 		count := int64(0)
 		for _, asyncCounter := range a.asyncCounters {
@@ -723,10 +1175,43 @@ func (a *Api) Stop(ctx context.Context) error {
 	}
 }
 
+// closeStores stops the stores, removes the directories they own and
+// closes the audit log.
+func (a *Api) closeStores() {
+	if a.cache != nil {
+		err := a.cache.close()
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("remove cache directory: %s", err))
+		}
+	}
+
+	if a.uploads != nil {
+		err := a.uploads.close()
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("remove uploads: %s", err))
+		}
+	}
+
+	if a.idempotency != nil {
+		err := a.idempotency.close()
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("remove idempotency directory: %s", err))
+		}
+	}
+
+	if a.audit != nil {
+		err := a.audit.close()
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("close audit log: %s", err))
+		}
+	}
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module      = (*Api)(nil)
 	_ gotenberg.Provisioner = (*Api)(nil)
 	_ gotenberg.Validator   = (*Api)(nil)
 	_ gotenberg.App         = (*Api)(nil)
+	_ gotenberg.Reloader    = (*Api)(nil)
 )
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// apiKey is an entry of the API keys file. Only the hex-encoded SHA-256
+// digest of the key is stored, e.g.:
+//
+//	[
+//	  {
+//	    "name": "billing",
+//	    "hash": "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+//	    "routes": ["/forms/pdfengines/*"],
+//	    "expiresAt": "2027-01-01T00:00:00Z"
+//	  }
+//	]
+//
+// A route ending with "*" is a prefix; otherwise, it must match exactly. No
+// routes means all routes.
+type apiKey struct {
+	Name      string     `json:"name"`
+	Hash      string     `json:"hash"`
+	Routes    []string   `json:"routes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// allows tells if the key may access the given path, relative to the root
+// path.
+func (key apiKey) allows(path string) bool {
+	if len(key.Routes) == 0 {
+		return true
+	}
+
+	for _, route := range key.Routes {
+		prefix, isPrefix := strings.CutSuffix(route, "*")
+		if isPrefix && strings.HasPrefix(path, prefix) {
+			return true
+		}
+
+		if !isPrefix && path == route {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expired tells if the key has expired at the given time.
+func (key apiKey) expired(now time.Time) bool {
+	return key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)
+}
+
+// apiKeysCheckInterval is the minimum interval between two checks of the API
+// keys file for changes.
+const apiKeysCheckInterval = time.Second
+
+// apiKeyStore holds the API keys of a file, indexed by their hash. It reloads
+// the file whenever its modification time or size changes, checking it at most
+// once per [apiKeysCheckInterval].
+type apiKeyStore struct {
+	path string
+	now  func() time.Time
+
+	// checkedAt is the time of the last check, in Unix nanoseconds.
+	checkedAt atomic.Int64
+
+	mu      sync.RWMutex
+	keys    map[string]apiKey
+	modTime time.Time
+	size    int64
+}
+
+// newApiKeyStore returns an [apiKeyStore] loaded from the given file.
+func newApiKeyStore(path string) (*apiKeyStore, error) {
+	store := &apiKeyStore{path: path, now: time.Now}
+
+	err := store.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// reload loads the file again if it has changed since the last check. Within
+// [apiKeysCheckInterval] of this check, it does nothing. On error, the store
+// keeps the previous keys, and does not retry until the file changes again.
+func (store *apiKeyStore) reload() error {
+	now := store.now().UnixNano()
+	checkedAt := store.checkedAt.Load()
+	if checkedAt != 0 && now-checkedAt < int64(apiKeysCheckInterval) {
+		return nil
+	}
+	if !store.checkedAt.CompareAndSwap(checkedAt, now) {
+		// Another request is checking the file.
+		return nil
+	}
+
+	info, err := os.Stat(store.path)
+	if err != nil {
+		return fmt.Errorf("stat API keys file: %w", err)
+	}
+
+	store.mu.RLock()
+	unchanged := store.keys != nil && info.ModTime().Equal(store.modTime) && info.Size() == store.size
+	store.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	keys, loadErr := loadApiKeys(store.path)
+
+	store.modTime = info.ModTime()
+	store.size = info.Size()
+
+	if loadErr != nil {
+		if store.keys == nil {
+			// Never leave the store without keys: nobody gets in.
+			store.keys = make(map[string]apiKey)
+		}
+		return loadErr
+	}
+
+	store.keys = keys
+
+	return nil
+}
+
+// lookup returns the key matching the given raw API key.
+func (store *apiKeyStore) lookup(rawKey string) (apiKey, bool) {
+	sum := sha256.Sum256([]byte(rawKey))
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	key, ok := store.keys[hex.EncodeToString(sum[:])]
+
+	return key, ok
+}
+
+// loadApiKeys reads and validates an API keys file.
+func loadApiKeys(path string) (map[string]apiKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read API keys file: %w", err)
+	}
+
+	var entries []apiKey
+	err = json.Unmarshal(b, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal API keys file: %w", err)
+	}
+
+	keys := make(map[string]apiKey, len(entries))
+	names := make(map[string]bool, len(entries))
+
+	for i, entry := range entries {
+		entry.Hash = strings.ToLower(entry.Hash)
+
+		switch {
+		case entry.Name == "":
+			err = errors.Join(err, fmt.Errorf("API key %d: name must not be empty", i))
+		case names[entry.Name]:
+			err = errors.Join(err, fmt.Errorf("API key '%s': duplicate name", entry.Name))
+		}
+		names[entry.Name] = true
+
+		decoded, decodeErr := hex.DecodeString(entry.Hash)
+		switch {
+		case decodeErr != nil || len(decoded) != sha256.Size:
+			err = errors.Join(err, fmt.Errorf("API key '%s': hash must be a hex-encoded SHA-256 digest", entry.Name))
+		case keys[entry.Hash].Name != "":
+			err = errors.Join(err, fmt.Errorf("API key '%s': same hash as API key '%s'", entry.Name, keys[entry.Hash].Name))
+		}
+
+		for _, route := range entry.Routes {
+			if !strings.HasPrefix(route, "/") {
+				err = errors.Join(err, fmt.Errorf("API key '%s': route '%s' must start with '/'", entry.Name, route))
+			}
+		}
+
+		keys[entry.Hash] = entry
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
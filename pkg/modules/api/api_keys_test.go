@@ -0,0 +1,131 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// SHA-256 of "foo".
+const testApiKeyHash = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func TestLoadApiKeys(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		content  string
+		wantErr  string // substring expected in the error, "" means no error
+	}{
+		{"valid", `[{"name":"foo","hash":"` + testApiKeyHash + `","routes":["/forms/*"]}]`, ""},
+		{"uppercase hash", `[{"name":"foo","hash":"` + strings.ToUpper(testApiKeyHash) + `"}]`, ""},
+		{"malformed JSON", `{`, "unmarshal"},
+		{"missing name", `[{"hash":"` + testApiKeyHash + `"}]`, "name must not be empty"},
+		{"duplicate name", `[{"name":"foo","hash":"` + testApiKeyHash + `"},{"name":"foo","hash":"` + strings.Repeat("0", 64) + `"}]`, "duplicate name"},
+		{"invalid hash", `[{"name":"foo","hash":"foo"}]`, "hex-encoded SHA-256"},
+		{"duplicate hash", `[{"name":"foo","hash":"` + testApiKeyHash + `"},{"name":"bar","hash":"` + testApiKeyHash + `"}]`, "same hash"},
+		{"relative route", `[{"name":"foo","hash":"` + testApiKeyHash + `","routes":["forms/*"]}]`, "must start with '/'"},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "keys.json")
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("write keys file: %v", err)
+			}
+
+			_, err = loadApiKeys(path)
+
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want a substring %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestApiKeyStore_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	write := func(content string, modTime time.Time) {
+		err := os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("write keys file: %v", err)
+		}
+		err = os.Chtimes(path, modTime, modTime)
+		if err != nil {
+			t.Fatalf("change keys file times: %v", err)
+		}
+	}
+
+	now := time.Now()
+	write(`[{"name":"foo","hash":"`+testApiKeyHash+`"}]`, now)
+
+	store, err := newApiKeyStore(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	clock := time.Now()
+	store.now = func() time.Time {
+		clock = clock.Add(apiKeysCheckInterval)
+		return clock
+	}
+
+	if _, ok := store.lookup("foo"); !ok {
+		t.Fatal("expected key 'foo' to be found")
+	}
+
+	// An invalid file keeps the previous keys.
+	write(`{`, now.Add(time.Second))
+	if err = store.reload(); err == nil {
+		t.Fatal("expected an error for an invalid file")
+	}
+	if err = store.reload(); err != nil {
+		t.Fatalf("expected no error for an unchanged file, got %v", err)
+	}
+	if _, ok := store.lookup("foo"); !ok {
+		t.Fatal("expected key 'foo' to be kept")
+	}
+
+	// A valid file replaces the keys.
+	write(`[]`, now.Add(2*time.Second))
+	if err = store.reload(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := store.lookup("foo"); ok {
+		t.Fatal("expected key 'foo' to be removed")
+	}
+
+	// The file is not checked again within the check interval.
+	store.now = func() time.Time {
+		return clock.Add(apiKeysCheckInterval / 2)
+	}
+	write(`{`, now.Add(3*time.Second))
+	if err = store.reload(); err != nil {
+		t.Fatalf("expected no check within the interval, got %v", err)
+	}
+}
+
+func TestApiKey_Allows(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		routes   []string
+		path     string
+		want     bool
+	}{
+		{"no routes", nil, "/forms/chromium/convert/url", true},
+		{"prefix", []string{"/forms/pdfengines/*"}, "/forms/pdfengines/merge", true},
+		{"other prefix", []string{"/forms/pdfengines/*"}, "/forms/chromium/convert/url", false},
+		{"exact", []string{"/health"}, "/health", true},
+		{"exact is not a prefix", []string{"/health"}, "/healthz", false},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			if got := (apiKey{Routes: tc.routes}).allows(tc.path); got != tc.want {
+				t.Fatalf("allows(%q) = %t, want %t", tc.path, got, tc.want)
+			}
+		})
+	}
+}
@@ -1,13 +1,20 @@
 package api
 
 import (
+	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 )
 
 func TestApi_Validate_Auth(t *testing.T) {
 	base := func() *Api {
-		return &Api{port: 3000, rootPath: "/", correlationIdHeader: "Gotenberg-Trace"}
+		a := &Api{port: 3000, rootPath: "/", correlationIdHeader: "Gotenberg-Trace", tlsClientAuth: clientAuthRequire}
+		a.settings.Store(new(reloadableSettings))
+		return a
 	}
 
 	for _, tc := range []struct {
@@ -16,7 +23,7 @@ func TestApi_Validate_Auth(t *testing.T) {
 		wantErr  string // substring expected in the error, "" means no error
 	}{
 		{"no auth", func(*Api) {}, ""},
-		{"basic auth only", func(a *Api) { a.basicAuthUsername = "foo" }, ""},
+		{"basic auth only", func(a *Api) { a.settings.Load().basicAuthUsername = "foo" }, ""},
 		{
 			"oidc auth valid",
 			func(a *Api) {
@@ -29,13 +36,47 @@ func TestApi_Validate_Auth(t *testing.T) {
 		{
 			"basic and oidc are mutually exclusive",
 			func(a *Api) {
-				a.basicAuthUsername = "foo"
+				a.settings.Load().basicAuthUsername = "foo"
 				a.oidcEnabled = true
 				a.oidcIssuer = "https://tenant.example.com/"
 				a.oidcAudience = "gotenberg"
 			},
 			"cannot both be enabled",
 		},
+		{
+			"api key auth valid",
+			func(a *Api) { a.apiKeyAuthEnabled = true; a.apiKeysFile = "/keys.json" },
+			"",
+		},
+		{
+			"api key and basic are mutually exclusive",
+			func(a *Api) {
+				a.settings.Load().basicAuthUsername = "foo"
+				a.apiKeyAuthEnabled = true
+				a.apiKeysFile = "/keys.json"
+			},
+			"cannot be enabled with basic or OIDC",
+		},
+		{
+			"api key missing file",
+			func(a *Api) { a.apiKeyAuthEnabled = true },
+			"API keys file must not be empty",
+		},
+		{
+			"mutual TLS valid",
+			func(a *Api) { a.tlsCertFile = "/cert.pem"; a.tlsKeyFile = "/key.pem"; a.tlsClientCaFile = "/ca.pem" },
+			"",
+		},
+		{
+			"mutual TLS missing server certificate",
+			func(a *Api) { a.tlsClientCaFile = "/ca.pem" },
+			"TLS certificate and key files must be set",
+		},
+		{
+			"mutual TLS invalid verification mode",
+			func(a *Api) { a.tlsClientAuth = "foo" },
+			"either 'require' or 'optional'",
+		},
 		{
 			"oidc missing issuer",
 			func(a *Api) { a.oidcEnabled = true; a.oidcAudience = "gotenberg" },
@@ -65,3 +106,142 @@ func TestApi_Validate_Auth(t *testing.T) {
 		})
 	}
 }
+
+func TestApi_Validate_AuditLog(t *testing.T) {
+	base := func() *Api {
+		a := &Api{port: 3000, rootPath: "/", correlationIdHeader: "Gotenberg-Trace", tlsClientAuth: clientAuthRequire, auditLog: "/audit.jsonl", auditLogMaxSize: 1024, auditLogMaxBackups: 5}
+		a.settings.Store(new(reloadableSettings))
+		return a
+	}
+
+	for _, tc := range []struct {
+		scenario string
+		mutate   func(*Api)
+		wantErr  string // substring expected in the error, "" means no error
+	}{
+		{"rotation with backups", func(*Api) {}, ""},
+		{"no rotation without backups", func(a *Api) { a.auditLogMaxSize = 0; a.auditLogMaxBackups = 0 }, ""},
+		{"stdout without backups", func(a *Api) { a.auditLog = auditLogStdout; a.auditLogMaxBackups = 0 }, ""},
+		{"rotation without backups", func(a *Api) { a.auditLogMaxBackups = 0 }, "must be strictly positive when the rotation is enabled"},
+		{"negative backups", func(a *Api) { a.auditLogMaxBackups = -1 }, "must be positive"},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			a := base()
+			tc.mutate(a)
+
+			err := a.Validate()
+
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want a substring %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestApi_Reload(t *testing.T) {
+	newFlags := func(t *testing.T, args ...string) gotenberg.ParsedFlags {
+		fs := new(Api).Descriptor().FlagSet
+		err := fs.Parse(args)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+		return gotenberg.ParsedFlags{FlagSet: fs}
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		current     *reloadableSettings
+		args        []string
+		secrets     map[string]string
+		expectError string
+		expect      func(t *testing.T, settings *reloadableSettings)
+	}{
+		{
+			scenario: "new outbound lists",
+			current:  new(reloadableSettings),
+			args:     []string{"--api-download-from-deny-list=^https://foo", "--api-upload-to-deny-public-ips", "--api-high-priority-allow-list=^previews$"},
+			expect: func(t *testing.T, settings *reloadableSettings) {
+				if len(settings.downloadFromCfg.denyList) != 1 || settings.downloadFromCfg.denyList[0].String() != "^https://foo" {
+					t.Errorf("expected the new download from deny list, got %v", settings.downloadFromCfg.denyList)
+				}
+				if !settings.uploadToCfg.denyPublicIPs {
+					t.Error("expected public IPs to be denied for upload to")
+				}
+				if len(settings.highPriorityAllowList) != 1 {
+					t.Errorf("expected the new high priority allow list, got %v", settings.highPriorityAllowList)
+				}
+			},
+		},
+		{
+			scenario: "rotated basic auth credentials",
+			current:  &reloadableSettings{basicAuthUsername: "foo", basicAuthPassword: "bar"},
+			args:     []string{"--api-enable-basic-auth"},
+			secrets: map[string]string{
+				"GOTENBERG_API_BASIC_AUTH_USERNAME": "foo\n",
+				"GOTENBERG_API_BASIC_AUTH_PASSWORD": "baz\n",
+			},
+			expect: func(t *testing.T, settings *reloadableSettings) {
+				if settings.basicAuthUsername != "foo" || settings.basicAuthPassword != "baz" {
+					t.Errorf("expected credentials foo:baz, got %s:%s", settings.basicAuthUsername, settings.basicAuthPassword)
+				}
+			},
+		},
+		{
+			scenario:    "basic auth enabled",
+			current:     new(reloadableSettings),
+			args:        []string{"--api-enable-basic-auth"},
+			secrets:     map[string]string{"GOTENBERG_API_BASIC_AUTH_USERNAME": "foo", "GOTENBERG_API_BASIC_AUTH_PASSWORD": "bar"},
+			expectError: "requires a restart",
+		},
+		{
+			scenario:    "basic auth disabled",
+			current:     &reloadableSettings{basicAuthUsername: "foo", basicAuthPassword: "bar"},
+			expectError: "requires a restart",
+		},
+		{
+			scenario:    "missing basic auth credentials",
+			current:     &reloadableSettings{basicAuthUsername: "foo", basicAuthPassword: "bar"},
+			args:        []string{"--api-enable-basic-auth"},
+			expectError: "get basic auth username from env",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Setenv("GOTENBERG_API_BASIC_AUTH_USERNAME", "")
+			t.Setenv("GOTENBERG_API_BASIC_AUTH_PASSWORD", "")
+			for key, secret := range tc.secrets {
+				path := filepath.Join(t.TempDir(), key)
+				err := os.WriteFile(path, []byte(secret), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				t.Setenv(key+"_FILE", path)
+			}
+
+			a := &Api{logger: slog.New(slog.DiscardHandler)}
+			a.settings.Store(tc.current)
+
+			err := a.Reload(newFlags(t, tc.args...))
+
+			if tc.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectError) {
+					t.Fatalf("expected error containing '%s' but got: %v", tc.expectError, err)
+				}
+				if a.settings.Load() != tc.current {
+					t.Fatal("expected the current settings to be kept")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			tc.expect(t, a.settings.Load())
+		})
+	}
+}
@@ -1,4 +1,4 @@
-package webhook
+package api
 
 import (
 	"context"
@@ -6,8 +6,6 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/trace"
-
-	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
 func TestDetachAsyncContext_PreservesTraceContext(t *testing.T) {
@@ -26,8 +24,8 @@ func TestDetachAsyncContext_PreservesTraceContext(t *testing.T) {
 	)
 	defer reqCancel()
 
-	ctx := &api.Context{Context: reqCtx}
-	cancel := detachAsyncContext(ctx, func() {})
+	ctx := &Context{Context: reqCtx}
+	cancel := DetachAsyncContext(ctx, func() {}, "webhook.Async")
 	defer cancel()
 
 	got := trace.SpanContextFromContext(ctx.Context)
@@ -41,8 +39,8 @@ func TestDetachAsyncContext_PreservesDeadline(t *testing.T) {
 	reqCtx, reqCancel := context.WithDeadline(context.Background(), deadline)
 	defer reqCancel()
 
-	ctx := &api.Context{Context: reqCtx}
-	cancel := detachAsyncContext(ctx, func() {})
+	ctx := &Context{Context: reqCtx}
+	cancel := DetachAsyncContext(ctx, func() {}, "webhook.Async")
 	defer cancel()
 
 	got, ok := ctx.Deadline()
@@ -57,8 +55,8 @@ func TestDetachAsyncContext_PreservesDeadline(t *testing.T) {
 func TestDetachAsyncContext_SurvivesRequestCancellation(t *testing.T) {
 	reqCtx, reqCancel := context.WithDeadline(context.Background(), time.Now().Add(2*time.Hour))
 
-	ctx := &api.Context{Context: reqCtx}
-	cancel := detachAsyncContext(ctx, func() {})
+	ctx := &Context{Context: reqCtx}
+	cancel := DetachAsyncContext(ctx, func() {}, "webhook.Async")
 	defer cancel()
 
 	// Cancelling the inbound request must not abort the detached context.
@@ -74,8 +72,8 @@ func TestDetachAsyncContext_CancelInvokesOriginal(t *testing.T) {
 	defer reqCancel()
 
 	called := 0
-	ctx := &api.Context{Context: reqCtx}
-	cancel := detachAsyncContext(ctx, func() { called++ })
+	ctx := &Context{Context: reqCtx}
+	cancel := DetachAsyncContext(ctx, func() { called++ }, "webhook.Async")
 
 	cancel()
 	if called != 1 {
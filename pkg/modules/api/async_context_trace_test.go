@@ -1,4 +1,4 @@
-package webhook
+package api
 
 import (
 	"context"
@@ -11,7 +11,6 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
-	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
 // TestDetachAsyncContext_TraceContinuity asserts that an asynchronous webhook
@@ -36,8 +35,8 @@ func TestDetachAsyncContext_TraceContinuity(t *testing.T) {
 	reqCtx, reqCancel := context.WithDeadline(serverCtx, time.Now().Add(time.Hour))
 	defer reqCancel()
 
-	ctx := &api.Context{Context: reqCtx}
-	cancel := detachAsyncContext(ctx, func() {})
+	ctx := &Context{Context: reqCtx}
+	cancel := DetachAsyncContext(ctx, func() {}, "webhook.Async")
 
 	// Simulate a downstream conversion span using the detached context, as the
 	// chromium/libreoffice engines would.
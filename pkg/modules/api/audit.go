@@ -0,0 +1,420 @@
+package api
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// auditLogStdout is the value of the "api-audit-log" flag for writing the
+// audit records to the standard output.
+const auditLogStdout = "stdout"
+
+const (
+	// auditOutcomeSuccess is the outcome of a successful conversion.
+	auditOutcomeSuccess = "success"
+
+	// auditOutcomeFailure is the outcome of a failed conversion.
+	auditOutcomeFailure = "failure"
+
+	// auditOutcomeAccepted is the outcome of an asynchronous conversion,
+	// which goes on after the response. A second record, chained to the
+	// first, tells its actual outcome once over.
+	auditOutcomeAccepted = "accepted"
+)
+
+// auditFile is an input or output file of an audit record.
+type auditFile struct {
+	Filename string `json:"filename"`
+	Sha256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+}
+
+// auditRecord records who converted what.
+//
+// On disk, a record is a JSON line whose last member is "hash", i.e., the
+// hexadecimal SHA-256 of the line without this member:
+//
+//	{"time":"...",...,"prevHash":"<hash of the previous record>","hash":"..."}
+//
+// As each record holds the hash of the previous one, a deleted or altered
+// record breaks the chain.
+type auditRecord struct {
+	Time          time.Time   `json:"time"`
+	Method        string      `json:"method"`
+	Route         string      `json:"route"`
+	Identity      string      `json:"identity,omitempty"`
+	CorrelationId string      `json:"correlationId"`
+	Inputs        []auditFile `json:"inputs"`
+	Outputs       []auditFile `json:"outputs"`
+	Engines       []string    `json:"engines"`
+	Status        int         `json:"status"`
+	Outcome       string      `json:"outcome"`
+	Error         string      `json:"error,omitempty"`
+	PrevHash      string      `json:"prevHash"`
+}
+
+// auditFiles gathers the input and output files of a request. The
+// [contextMiddleware] hashes them before removing the working directory.
+type auditFiles struct {
+	conversion bool
+	async      bool
+	inputs     []auditFile
+	outputs    []auditFile
+
+	// For the record of an asynchronous conversion, once over.
+	audit   *auditLog
+	logger  *slog.Logger
+	engines *gotenberg.UsedEngines
+}
+
+// auditLog writes the audit records as JSON Lines, either to the standard
+// output or to a file, rotated once it exceeds a maximum size.
+type auditLog struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu       sync.Mutex
+	w        io.Writer
+	f        *os.File
+	size     int64
+	prevHash string
+}
+
+// newAuditLog returns an [auditLog] writing to the given destination, either
+// [auditLogStdout] or a file path. With a file, the chain goes on from its
+// last record, if any.
+func newAuditLog(destination string, maxSize int64, maxBackups int) (*auditLog, error) {
+	if destination == auditLogStdout {
+		return &auditLog{w: os.Stdout}, nil
+	}
+
+	audit := &auditLog{
+		path:       destination,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	// After a rotation, the current file may not have any record yet.
+	for _, path := range []string{destination, fmt.Sprintf("%s.1", destination)} {
+		prevHash, err := lastAuditHash(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if prevHash != "" {
+			audit.prevHash = prevHash
+			break
+		}
+	}
+
+	err := audit.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return audit, nil
+}
+
+// lastAuditHash returns the hash of the last record of an audit log file, or
+// an empty string if the file does not exist or has no record.
+func lastAuditHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("open audit log: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var last []byte
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 1 {
+			last = line
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("read audit log: %w", err)
+		}
+	}
+
+	if last == nil {
+		return "", nil
+	}
+
+	var record struct {
+		Hash string `json:"hash"`
+	}
+	err = json.Unmarshal(last, &record)
+	if err != nil || record.Hash == "" {
+		return "", fmt.Errorf("last record of audit log '%s' is malformed", path)
+	}
+
+	return record.Hash, nil
+}
+
+// open opens the audit log file for appending.
+func (audit *auditLog) open() error {
+	f, err := os.OpenFile(audit.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat audit log: %w", err)
+	}
+
+	audit.f = f
+	audit.w = f
+	audit.size = stat.Size()
+
+	return nil
+}
+
+// rotate moves the audit log file to its first backup, shifting the
+// previous backups, and opens a new file.
+func (audit *auditLog) rotate() error {
+	err := audit.f.Close()
+	if err != nil {
+		return fmt.Errorf("close audit log: %w", err)
+	}
+
+	backup := func(i int) string {
+		return fmt.Sprintf("%s.%d", audit.path, i)
+	}
+
+	for i := audit.maxBackups - 1; i >= 1; i-- {
+		err = os.Rename(backup(i), backup(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotate audit log backup: %w", err)
+		}
+	}
+
+	err = os.Rename(audit.path, backup(1))
+	if err != nil {
+		return fmt.Errorf("rotate audit log: %w", err)
+	}
+
+	return audit.open()
+}
+
+// close closes the audit log file, if any. Later records are discarded.
+func (audit *auditLog) close() error {
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+
+	if audit.f == nil {
+		return nil
+	}
+
+	err := audit.f.Close()
+	audit.f = nil
+	audit.w = io.Discard
+	if err != nil {
+		return fmt.Errorf("close audit log: %w", err)
+	}
+
+	return nil
+}
+
+// write chains a record to the previous one and writes it.
+func (audit *auditLog) write(record auditRecord) error {
+	audit.mu.Lock()
+	defer audit.mu.Unlock()
+
+	record.PrevHash = audit.prevHash
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal audit record: %w", err)
+	}
+
+	sum := sha256.Sum256(b)
+	hash := hex.EncodeToString(sum[:])
+
+	line := slices.Concat(b[:len(b)-1], []byte(fmt.Sprintf(`,"hash":"%s"}`, hash)), []byte("\n"))
+
+	if audit.f != nil && audit.maxSize > 0 && audit.size > 0 && audit.size+int64(len(line)) > audit.maxSize {
+		err = audit.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	n, err := audit.w.Write(line)
+	audit.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("write audit record: %w", err)
+	}
+
+	audit.prevHash = hash
+
+	return nil
+}
+
+// digestFile returns the SHA-256 and the size of a file.
+func digestFile(filename, path string) (auditFile, error) {
+	size, sums, err := digestFileWith(path, []string{"sha-256"})
+	if err != nil {
+		return auditFile{}, err
+	}
+
+	return auditFile{
+		Filename: filename,
+		Sha256:   hex.EncodeToString(sums["sha-256"]),
+		Size:     size,
+	}, nil
+}
+
+// digestInputs hashes the input files of a request.
+func (files *auditFiles) digestInputs(ctx *Context) {
+	files.conversion = true
+
+	filenames := make([]string, 0, len(ctx.files))
+	for filename := range ctx.files {
+		filenames = append(filenames, filename)
+	}
+	slices.Sort(filenames)
+
+	for _, filename := range filenames {
+		file, err := digestFile(filename, ctx.files[filename])
+		if err != nil {
+			ctx.Log().ErrorContext(ctx, fmt.Sprintf("audit input file '%s': %s", filename, err))
+			continue
+		}
+
+		files.inputs = append(files.inputs, file)
+	}
+}
+
+// digestOutputs hashes the output files of a request.
+func (files *auditFiles) digestOutputs(ctx *Context) {
+	files.outputs = append(files.outputs, auditOutputs(ctx)...)
+}
+
+// auditOutputs hashes the output files of the context.
+func auditOutputs(ctx *Context) []auditFile {
+	outputs := make([]auditFile, 0, len(ctx.outputPaths))
+	for _, path := range ctx.outputPaths {
+		filename := ctx.OriginalFilename(path)
+
+		file, err := digestFile(filename, path)
+		if err != nil {
+			ctx.Log().ErrorContext(ctx, fmt.Sprintf("audit output file '%s': %s", filename, err))
+			continue
+		}
+
+		outputs = append(outputs, file)
+	}
+
+	return outputs
+}
+
+// asyncDone returns the function writing the record of an asynchronous
+// conversion once over; see [Context.AsyncDone]. The fields of the request
+// are read right away, as the echo.Context goes back to its pool as soon as
+// the request is acknowledged.
+func (files *auditFiles) asyncDone(c echo.Context, ctx *Context) func(err error) {
+	record := newAuditRecord(c, files, nil, 0, nil)
+
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			record.Time = time.Now().UTC()
+			record.Outputs = auditOutputs(ctx)
+			record.Engines = files.engines.Names()
+			record.Status = http.StatusOK
+			record.Outcome = auditOutcomeSuccess
+
+			if err != nil {
+				record.Status, _ = ParseError(err)
+				record.Outcome = auditOutcomeFailure
+				record.Error = err.Error()
+			}
+
+			if record.Engines == nil {
+				record.Engines = []string{}
+			}
+
+			writeErr := files.audit.write(record)
+			if writeErr != nil {
+				files.logger.ErrorContext(ctx, writeErr.Error())
+			}
+		})
+	}
+}
+
+// newAuditRecord returns the audit record of a handled request.
+func newAuditRecord(c echo.Context, files *auditFiles, engines []string, status int, err error) auditRecord {
+	record := auditRecord{
+		Time:    time.Now().UTC(),
+		Method:  c.Request().Method,
+		Route:   c.Request().URL.Path,
+		Inputs:  files.inputs,
+		Outputs: files.outputs,
+		Engines: engines,
+		Status:  status,
+	}
+
+	record.Identity = CallerIdentity(c)
+	record.CorrelationId, _ = c.Get("correlationId").(string)
+
+	switch {
+	case err != nil:
+		record.Outcome = auditOutcomeFailure
+		record.Error = err.Error()
+	case files.async:
+		record.Outcome = auditOutcomeAccepted
+	default:
+		record.Outcome = auditOutcomeSuccess
+	}
+
+	if record.Inputs == nil {
+		record.Inputs = []auditFile{}
+	}
+	if record.Outputs == nil {
+		record.Outputs = []auditFile{}
+	}
+	if record.Engines == nil {
+		record.Engines = []string{}
+	}
+
+	return record
+}
+
+// writeAuditRecord writes the audit record of a handled request, if it was a
+// conversion.
+func writeAuditRecord(c echo.Context, audit *auditLog, logger *slog.Logger, files *auditFiles, engines []string, status int, err error) {
+	if !files.conversion {
+		return
+	}
+
+	writeErr := audit.write(newAuditRecord(c, files, engines, status, err))
+	if writeErr != nil {
+		logger.ErrorContext(c.Request().Context(), writeErr.Error())
+	}
+}
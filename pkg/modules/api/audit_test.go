@@ -0,0 +1,356 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+var auditHashRegexp = regexp.MustCompile(`,"hash":"([0-9a-f]{64})"}$`)
+
+// verifyAuditChain checks the hash chain of the given audit log files, from
+// the oldest to the newest, and returns their routes. The first record may
+// be chained to a record which has been rotated out.
+func verifyAuditChain(paths ...string) ([]string, error) {
+	var routes []string
+	var prevHash string
+
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(strings.NewReader(string(b)))
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			matches := auditHashRegexp.FindStringSubmatch(line)
+			if matches == nil {
+				return nil, fmt.Errorf("no hash in record '%s'", line)
+			}
+
+			payload := strings.TrimSuffix(line, matches[0]) + "}"
+			sum := sha256.Sum256([]byte(payload))
+			if hex.EncodeToString(sum[:]) != matches[1] {
+				return nil, fmt.Errorf("hash of record %d does not match its content", len(routes))
+			}
+
+			var record auditRecord
+			err = json.Unmarshal([]byte(payload), &record)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(routes) > 0 && record.PrevHash != prevHash {
+				return nil, fmt.Errorf("record %d is not chained to the previous one", len(routes))
+			}
+
+			prevHash = matches[1]
+			routes = append(routes, record.Route)
+		}
+	}
+
+	return routes, nil
+}
+
+func writeAuditRecords(t *testing.T, audit *auditLog, from, to int) {
+	t.Helper()
+
+	for i := from; i < to; i++ {
+		err := audit.write(auditRecord{Route: fmt.Sprintf("/forms/%d", i), Outcome: auditOutcomeSuccess})
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	audit, err := newAuditLog(path, 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	writeAuditRecords(t, audit, 0, 3)
+
+	// The chain goes on after a restart.
+	audit, err = newAuditLog(path, 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	writeAuditRecords(t, audit, 3, 4)
+
+	routes, err := verifyAuditChain(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(routes) != 4 {
+		t.Errorf("expected 4 records but got %d", len(routes))
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+
+	for _, tc := range []struct {
+		scenario string
+		content  string
+	}{
+		{scenario: "deleted record", content: lines[0] + lines[2] + lines[3]},
+		{scenario: "altered record", content: lines[0] + strings.Replace(lines[1], "/forms/1", "/forms/9", 1) + lines[2]},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tampered := filepath.Join(t.TempDir(), "audit.jsonl")
+			err := os.WriteFile(tampered, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			_, err = verifyAuditChain(tampered)
+			if err == nil {
+				t.Error("expected a broken chain but got none")
+			}
+		})
+	}
+}
+
+func TestAuditLog_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	backup := func(i int) string {
+		return fmt.Sprintf("%s.%d", path, i)
+	}
+
+	audit, err := newAuditLog(path, 512, 2)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	writeAuditRecords(t, audit, 0, 10)
+
+	_, err = os.Stat(backup(3))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected no third backup but got: %v", err)
+	}
+
+	for _, p := range []string{path, backup(1), backup(2)} {
+		stat, err := os.Stat(p)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if stat.Size() > 512 {
+			t.Errorf("expected '%s' to be at most 512 bytes but got %d", p, stat.Size())
+		}
+	}
+
+	// The chain spans the backups.
+	routes, err := verifyAuditChain(backup(2), backup(1), path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if routes[len(routes)-1] != "/forms/9" {
+		t.Errorf("expected the last record to be '/forms/9' but got '%s'", routes[len(routes)-1])
+	}
+
+	// The chain goes on after a restart, even if the current file has no
+	// record yet.
+	err = os.Rename(backup(1), backup(2))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	err = os.Rename(path, backup(1))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	audit, err = newAuditLog(path, 512, 2)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	writeAuditRecords(t, audit, 10, 11)
+
+	_, err = verifyAuditChain(backup(1), path)
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+}
+
+func TestAuditLog_Close(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	audit, err := newAuditLog(path, 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	writeAuditRecords(t, audit, 0, 1)
+
+	err = audit.close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	// Records after the close are discarded.
+	writeAuditRecords(t, audit, 1, 2)
+
+	routes, err := verifyAuditChain(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(routes) != 1 {
+		t.Errorf("expected 1 record but got %d", len(routes))
+	}
+
+	err = audit.close()
+	if err != nil {
+		t.Errorf("expected no error on a second close but got: %v", err)
+	}
+}
+
+func TestNewAuditLog_MalformedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	err := os.WriteFile(path, []byte("foo\n"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = newAuditLog(path, 0, 0)
+	if err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestNewAuditRecord(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		async         bool
+		err           error
+		expectOutcome string
+	}{
+		{scenario: "success", expectOutcome: auditOutcomeSuccess},
+		{scenario: "failure", err: errors.New("foo"), expectOutcome: auditOutcomeFailure},
+		{scenario: "accepted", async: true, expectOutcome: auditOutcomeAccepted},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/forms/chromium/convert/html", nil), httptest.NewRecorder())
+			c.Set("identity", "alice")
+			c.Set("correlationId", "bar")
+
+			files := &auditFiles{
+				conversion: true,
+				async:      tc.async,
+				inputs:     []auditFile{{Filename: "index.html", Sha256: "foo", Size: 3}},
+			}
+			record := newAuditRecord(c, files, []string{"chromium"}, http.StatusOK, tc.err)
+
+			if record.Outcome != tc.expectOutcome {
+				t.Errorf("expected outcome '%s' but got '%s'", tc.expectOutcome, record.Outcome)
+			}
+			if record.Identity != "alice" {
+				t.Errorf("expected identity 'alice' but got '%s'", record.Identity)
+			}
+			if record.CorrelationId != "bar" {
+				t.Errorf("expected correlation ID 'bar' but got '%s'", record.CorrelationId)
+			}
+			if record.Route != "/forms/chromium/convert/html" {
+				t.Errorf("expected route '/forms/chromium/convert/html' but got '%s'", record.Route)
+			}
+			if record.Outputs == nil {
+				t.Error("expected outputs to be an empty list")
+			}
+		})
+	}
+}
+
+func TestAuditFiles_AsyncDone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	audit, err := newAuditLog(path, 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	ctx, c := newCacheTestContext(t, nil, nil)
+	c.Set("correlationId", "bar")
+
+	engineCtx, engines := gotenberg.ContextWithUsedEngines(context.Background())
+	gotenberg.RecordEngine(engineCtx, "chromium")
+
+	files := &auditFiles{
+		conversion: true,
+		async:      true,
+		audit:      audit,
+		logger:     slog.New(slog.DiscardHandler),
+		engines:    engines,
+	}
+	writeAuditRecord(c, audit, files.logger, files, nil, http.StatusNoContent, nil)
+	done := files.asyncDone(c, ctx)
+
+	outputPath := ctx.GeneratePathFromFilename("foo.pdf")
+	err = os.WriteFile(outputPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	ctx.outputPaths = []string{outputPath}
+
+	done(nil)
+	// Only the first call counts.
+	done(errors.New("foo"))
+
+	routes, err := verifyAuditChain(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 records but got %d", len(routes))
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+
+	var record auditRecord
+	err = json.Unmarshal([]byte(lines[1]), &record)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if record.Outcome != auditOutcomeSuccess {
+		t.Errorf("expected outcome '%s' but got '%s'", auditOutcomeSuccess, record.Outcome)
+	}
+	if record.Status != http.StatusOK {
+		t.Errorf("expected status %d but got %d", http.StatusOK, record.Status)
+	}
+	if record.CorrelationId != "bar" {
+		t.Errorf("expected correlation ID 'bar' but got '%s'", record.CorrelationId)
+	}
+	if len(record.Outputs) != 1 || record.Outputs[0].Filename != "foo.pdf" || record.Outputs[0].Size != 3 {
+		t.Errorf("expected the output 'foo.pdf' of 3 bytes but got %+v", record.Outputs)
+	}
+	if len(record.Engines) != 1 || record.Engines[0] != "chromium" {
+		t.Errorf("expected engines [chromium] but got %v", record.Engines)
+	}
+}
@@ -0,0 +1,404 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// cacheHeader is the response header telling whether the result comes from
+// the cache.
+const cacheHeader = "Gotenberg-Cache"
+
+// resultCache is a content-addressed cache of conversion results. An entry is
+// keyed on the route, the caller identity, the engine versions, the form
+// values, the content of the files (uploaded or downloaded) and the output
+// filename.
+//
+// Entries live in a directory of its own, created within the configured one,
+// one subdirectory per key. Nothing else in the configured directory is ever
+// touched.
+type resultCache struct {
+	dirPath  string
+	maxSize  int64
+	ttl      time.Duration
+	versions string
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	size    int64
+	now     func() time.Time
+}
+
+// cacheEntry is a cached result.
+type cacheEntry struct {
+	filenames []string
+	size      int64
+	createdAt time.Time
+	lastUsed  time.Time
+}
+
+// cacheKeyData is the data hashed to compute a cache key. The JSON encoding
+// sorts the map keys, so that the key does not depend on the fields order.
+//
+// Results are not shared between callers, as a form field may refer to
+// state kept on their behalf, e.g., a Chromium session.
+type cacheKeyData struct {
+	Route          string              `json:"route"`
+	Identity       string              `json:"identity,omitempty"`
+	Versions       string              `json:"versions"`
+	OutputFilename string              `json:"outputFilename"`
+	Values         map[string][]string `json:"values"`
+	Files          map[string]string   `json:"files"`
+	FilesByField   map[string][]string `json:"filesByField"`
+}
+
+// newResultCache returns a [resultCache] using a new directory within the
+// given one. A zero maxSize or ttl means no limit.
+func newResultCache(parentDirPath string, maxSize int64, ttl time.Duration, versions string) (*resultCache, error) {
+	err := os.MkdirAll(parentDirPath, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create cache parent directory: %w", err)
+	}
+
+	dirPath, err := os.MkdirTemp(parentDirPath, "gotenberg-cache-")
+	if err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+
+	return &resultCache{
+		dirPath:  dirPath,
+		maxSize:  maxSize,
+		ttl:      ttl,
+		versions: versions,
+		entries:  make(map[string]*cacheEntry),
+		now:      time.Now,
+	}, nil
+}
+
+// close removes the directory of the cache.
+func (cache *resultCache) close() error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.entries = make(map[string]*cacheEntry)
+	cache.size = 0
+
+	return os.RemoveAll(cache.dirPath)
+}
+
+// key computes the cache key of the request.
+func (cache *resultCache) key(route Route, ctx *Context, outputFilename string) (string, error) {
+	files, filesByField, err := fileSums(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	data := cacheKeyData{
+		Route:          fmt.Sprintf("%s %s", route.Method, route.Path),
+		Identity:       ctx.Identity(),
+		Versions:       cache.versions,
+		OutputFilename: outputFilename,
+		Values:         ctx.values,
+		Files:          files,
+		FilesByField:   filesByField,
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("marshal cache key data: %w", err)
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// restore copies the cached result of the given key into the context's
+// working directory, and returns the paths of the copies. It returns false if
+// there is no valid entry.
+func (cache *resultCache) restore(ctx *Context, key string) ([]string, bool, error) {
+	cache.mu.Lock()
+	entry, ok := cache.entries[key]
+	if ok && cache.expired(entry) {
+		cache.remove(key)
+		ok = false
+	}
+	if !ok {
+		cache.mu.Unlock()
+		return nil, false, nil
+	}
+	entry.lastUsed = cache.now()
+	filenames := entry.filenames
+	cache.mu.Unlock()
+
+	outputPaths := make([]string, len(filenames))
+	for i, filename := range filenames {
+		outputPaths[i] = ctx.GeneratePathFromFilename(filename)
+
+		err := copyFile(filepath.Join(cache.dirPath, key, strconv.Itoa(i)), outputPaths[i])
+		if err != nil {
+			return nil, false, fmt.Errorf("copy cached file '%s': %w", filename, err)
+		}
+	}
+
+	return outputPaths, true, nil
+}
+
+// store copies the output files of the context into the cache under the given
+// key, then evicts the expired and least recently used entries while the
+// cache exceeds its maximum size.
+func (cache *resultCache) store(ctx *Context, key string) error {
+	cache.mu.Lock()
+	_, ok := cache.entries[key]
+	cache.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	// Write into a temporary directory first, so that a concurrent request
+	// never reads a partial entry.
+	tmpPath, err := os.MkdirTemp(cache.dirPath, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("create temporary cache directory: %w", err)
+	}
+
+	entry := &cacheEntry{
+		filenames: make([]string, len(ctx.outputPaths)),
+	}
+
+	for i, outputPath := range ctx.outputPaths {
+		entry.filenames[i] = ctx.OriginalFilename(outputPath)
+
+		err = copyFile(outputPath, filepath.Join(tmpPath, strconv.Itoa(i)))
+		if err != nil {
+			return errors.Join(fmt.Errorf("copy output file: %w", err), os.RemoveAll(tmpPath))
+		}
+
+		info, err := os.Stat(outputPath)
+		if err != nil {
+			return errors.Join(fmt.Errorf("stat output file: %w", err), os.RemoveAll(tmpPath))
+		}
+
+		entry.size += info.Size()
+	}
+
+	if cache.maxSize > 0 && entry.size > cache.maxSize {
+		return errors.Join(
+			fmt.Errorf("result of %d bytes exceeds the cache maximum size", entry.size),
+			os.RemoveAll(tmpPath),
+		)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if _, ok = cache.entries[key]; ok {
+		return os.RemoveAll(tmpPath)
+	}
+
+	err = os.Rename(tmpPath, filepath.Join(cache.dirPath, key))
+	if err != nil {
+		return errors.Join(fmt.Errorf("move cache entry: %w", err), os.RemoveAll(tmpPath))
+	}
+
+	entry.createdAt = cache.now()
+	entry.lastUsed = entry.createdAt
+	cache.entries[key] = entry
+	cache.size += entry.size
+
+	cache.evict()
+
+	return nil
+}
+
+// evict removes the expired entries, then the least recently used ones while
+// the cache exceeds its maximum size. The caller must hold the lock.
+func (cache *resultCache) evict() {
+	keys := make([]string, 0, len(cache.entries))
+	for key, entry := range cache.entries {
+		if cache.expired(entry) {
+			cache.remove(key)
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	if cache.maxSize <= 0 || cache.size <= cache.maxSize {
+		return
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return cache.entries[keys[i]].lastUsed.Before(cache.entries[keys[j]].lastUsed)
+	})
+
+	for _, key := range keys {
+		if cache.size <= cache.maxSize {
+			return
+		}
+
+		cache.remove(key)
+	}
+}
+
+// expired tells if the entry has outlived the TTL.
+func (cache *resultCache) expired(entry *cacheEntry) bool {
+	return cache.ttl > 0 && cache.now().Sub(entry.createdAt) > cache.ttl
+}
+
+// remove deletes an entry. The caller must hold the lock.
+func (cache *resultCache) remove(key string) {
+	entry, ok := cache.entries[key]
+	if !ok {
+		return
+	}
+
+	delete(cache.entries, key)
+	cache.size -= entry.size
+
+	// Best effort: a leftover directory is overwritten by a later store of
+	// the same key, and removed on the next start.
+	_ = os.RemoveAll(filepath.Join(cache.dirPath, key))
+}
+
+// cacheHandler wraps the handler of a "multipart/form-data" route so that its
+// result is served from the cache when possible. It sets the
+// "Gotenberg-Cache" header to either "hit" or "miss".
+func cacheHandler(cache *resultCache, route Route) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Get("context").(*Context)
+		outputFilename, _ := c.Get("outputFilename").(string)
+
+		// An asynchronous process (webhook, jobs) outlives the response, which
+		// may already belong to another request.
+		_, async := c.(*PoolSafeContext)
+		setCacheHeader := func(value string) {
+			if !async {
+				c.Response().Header().Set(cacheHeader, value)
+			}
+		}
+
+		key, err := cache.key(route, ctx, outputFilename)
+		if err != nil {
+			return fmt.Errorf("compute cache key: %w", err)
+		}
+
+		outputPaths, ok, err := cache.restore(ctx, key)
+		if err != nil {
+			ctx.Log().WarnContext(ctx, fmt.Sprintf("restore cached result, fallback to conversion: %s", err))
+		}
+
+		if ok && err == nil {
+			ctx.Log().DebugContext(ctx, fmt.Sprintf("cache hit for key '%s'", key))
+			setCacheHeader("hit")
+
+			return ctx.AddOutputPaths(outputPaths...)
+		}
+
+		setCacheHeader("miss")
+
+		err = route.Handler(c)
+		if err != nil {
+			return err
+		}
+
+		if len(ctx.outputPaths) == 0 {
+			// The handler sent its own response.
+			return nil
+		}
+
+		err = cache.store(ctx, key)
+		if err != nil {
+			ctx.Log().WarnContext(ctx, fmt.Sprintf("store result in cache: %s", err))
+		}
+
+		return nil
+	}
+}
+
+// fileSums returns the checksums of the files of the context, by filename
+// and by form field. The latter are sorted and prefixed with the filenames.
+func fileSums(ctx *Context) (map[string]string, map[string][]string, error) {
+	files := make(map[string]string, len(ctx.files))
+	for filename, path := range ctx.files {
+		sum, err := fileSum(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("hash file '%s': %w", filename, err)
+		}
+
+		files[filename] = sum
+	}
+
+	filesByField := make(map[string][]string, len(ctx.filesByField))
+	for field, paths := range ctx.filesByField {
+		sums := make([]string, len(paths))
+		for i, path := range paths {
+			sum, err := fileSum(path)
+			if err != nil {
+				return nil, nil, fmt.Errorf("hash file '%s': %w", ctx.OriginalFilename(path), err)
+			}
+
+			// The filename matters, e.g., for matching embeds.
+			sums[i] = ctx.OriginalFilename(path) + ":" + sum
+		}
+
+		sort.Strings(sums)
+		filesByField[field] = sums
+	}
+
+	return files, filesByField, nil
+}
+
+// fileSum returns the hex-encoded SHA-256 checksum of a file.
+func fileSum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyFile copies a file.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open source: %w", err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create destination: %w", err)
+	}
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return errors.Join(fmt.Errorf("copy content: %w", err), out.Close())
+	}
+
+	return out.Close()
+}
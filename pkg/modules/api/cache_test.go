@@ -0,0 +1,400 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newCacheTestContext(t *testing.T, values map[string][]string, files map[string]string) (*Context, echo.Context) {
+	t.Helper()
+
+	dirPath := t.TempDir()
+	ctx := &Context{
+		dirPath:        dirPath,
+		values:         values,
+		files:          make(map[string]string),
+		filesByField:   make(map[string][]string),
+		diskToOriginal: make(map[string]string),
+		logger:         slog.New(slog.DiscardHandler),
+		Context:        context.Background(),
+	}
+
+	for filename, content := range files {
+		path := filepath.Join(dirPath, filename)
+		err := os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+		ctx.files[filename] = path
+	}
+
+	c := echo.New().NewContext(
+		httptest.NewRequest(http.MethodPost, "/forms/foo", nil),
+		httptest.NewRecorder(),
+	)
+	c.Set("context", ctx)
+	c.Set("outputFilename", "")
+	ctx.echoCtx = c
+
+	return ctx, c
+}
+
+func TestNewResultCache(t *testing.T) {
+	parentDirPath := t.TempDir()
+
+	otherPath := filepath.Join(parentDirPath, "foo.txt")
+	err := os.WriteFile(otherPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	cache, err := newResultCache(parentDirPath, 0, 0, "")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if filepath.Dir(cache.dirPath) != parentDirPath {
+		t.Errorf("expected the cache directory within '%s' but got '%s'", parentDirPath, cache.dirPath)
+	}
+
+	err = cache.close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(cache.dirPath)
+	if !os.IsNotExist(err) {
+		t.Errorf("expected the cache directory to be removed, got: %v", err)
+	}
+
+	_, err = os.Stat(otherPath)
+	if err != nil {
+		t.Errorf("expected the other files of the parent directory to remain, got: %v", err)
+	}
+}
+
+func TestResultCache_Key(t *testing.T) {
+	cache, err := newResultCache(t.TempDir(), 0, 0, "versions")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	route := Route{Method: http.MethodPost, Path: "/forms/foo"}
+	key := func(values map[string][]string, files map[string]string, outputFilename string) string {
+		ctx, _ := newCacheTestContext(t, values, files)
+		k, err := cache.key(route, ctx, outputFilename)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+		return k
+	}
+
+	ref := key(map[string][]string{"foo": {"bar"}, "baz": {"qux"}}, map[string]string{"index.html": "<h1>Foo</h1>"}, "")
+
+	for _, tc := range []struct {
+		scenario     string
+		values       map[string][]string
+		files        map[string]string
+		filename     string
+		expectSameAs bool
+	}{
+		{
+			scenario:     "same values in another order and same files",
+			values:       map[string][]string{"baz": {"qux"}, "foo": {"bar"}},
+			files:        map[string]string{"index.html": "<h1>Foo</h1>"},
+			expectSameAs: true,
+		},
+		{
+			scenario: "other values",
+			values:   map[string][]string{"foo": {"bar"}, "baz": {"quux"}},
+			files:    map[string]string{"index.html": "<h1>Foo</h1>"},
+		},
+		{
+			scenario: "other file content",
+			values:   map[string][]string{"foo": {"bar"}, "baz": {"qux"}},
+			files:    map[string]string{"index.html": "<h1>Bar</h1>"},
+		},
+		{
+			scenario: "other filename",
+			values:   map[string][]string{"foo": {"bar"}, "baz": {"qux"}},
+			files:    map[string]string{"page.html": "<h1>Foo</h1>"},
+		},
+		{
+			scenario: "other output filename",
+			values:   map[string][]string{"foo": {"bar"}, "baz": {"qux"}},
+			files:    map[string]string{"index.html": "<h1>Foo</h1>"},
+			filename: "foo",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			k := key(tc.values, tc.files, tc.filename)
+
+			if tc.expectSameAs && k != ref {
+				t.Errorf("expected key '%s', but got '%s'", ref, k)
+			}
+
+			if !tc.expectSameAs && k == ref {
+				t.Errorf("expected a key other than '%s'", ref)
+			}
+		})
+	}
+
+	other, err := newResultCache(t.TempDir(), 0, 0, "other versions")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	ctx, _ := newCacheTestContext(t, map[string][]string{"foo": {"bar"}, "baz": {"qux"}}, map[string]string{"index.html": "<h1>Foo</h1>"})
+	k, err := other.key(route, ctx, "")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if k == ref {
+		t.Error("expected engine versions to change the key")
+	}
+
+	ctx, _ = newCacheTestContext(t, map[string][]string{"foo": {"bar"}, "baz": {"qux"}}, map[string]string{"index.html": "<h1>Foo</h1>"})
+	ctx.identity = "alice"
+	k, err = cache.key(route, ctx, "")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if k == ref {
+		t.Error("expected the caller identity to change the key")
+	}
+}
+
+func TestResultCache_StoreRestore(t *testing.T) {
+	now := time.Now()
+	cache, err := newResultCache(t.TempDir(), 10, time.Minute, "")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	cache.now = func() time.Time { return now }
+
+	store := func(key, content string) {
+		ctx, _ := newCacheTestContext(t, nil, nil)
+		path := ctx.GeneratePathFromFilename(key + ".pdf")
+		err := os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+		ctx.outputPaths = []string{path}
+
+		err = cache.store(ctx, key)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	restore := func(key string) (string, bool) {
+		ctx, _ := newCacheTestContext(t, nil, nil)
+		paths, ok, err := cache.restore(ctx, key)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+		if !ok {
+			return "", false
+		}
+		if ctx.OriginalFilename(paths[0]) != key+".pdf" {
+			t.Errorf("expected filename '%s.pdf', but got '%s'", key, ctx.OriginalFilename(paths[0]))
+		}
+		b, err := os.ReadFile(paths[0])
+		if err != nil {
+			t.Fatalf("read %s: %v", paths[0], err)
+		}
+		return string(b), true
+	}
+
+	store("foo", "1234")
+	content, ok := restore("foo")
+	if !ok || content != "1234" {
+		t.Fatalf("expected hit with '1234', but got %t with '%s'", ok, content)
+	}
+
+	// "foo" is now more recently used than "bar": storing "baz" must evict
+	// "bar" to fit in 10 bytes.
+	now = now.Add(time.Second)
+	store("bar", "5678")
+	now = now.Add(time.Second)
+	_, _ = restore("foo")
+	now = now.Add(time.Second)
+	store("baz", "901")
+
+	if _, ok = restore("bar"); ok {
+		t.Error("expected 'bar' to be evicted")
+	}
+	if _, ok = restore("foo"); !ok {
+		t.Error("expected 'foo' to be kept")
+	}
+
+	// Too large for the cache.
+	ctx, _ := newCacheTestContext(t, nil, nil)
+	path := ctx.GeneratePath(".pdf")
+	err = os.WriteFile(path, []byte("12345678901"), 0o600)
+	if err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	ctx.outputPaths = []string{path}
+	err = cache.store(ctx, "qux")
+	if err == nil {
+		t.Error("expected an error for a result larger than the cache")
+	}
+
+	// Expired.
+	now = now.Add(2 * time.Minute)
+	if _, ok = restore("foo"); ok {
+		t.Error("expected 'foo' to be expired")
+	}
+}
+
+func TestCacheHandler(t *testing.T) {
+	cache, err := newResultCache(t.TempDir(), 0, 0, "")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	calls := 0
+	route := Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/foo",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			calls++
+			ctx := c.Get("context").(*Context)
+
+			if ctx.values["fail"] != nil {
+				return errors.New("foo")
+			}
+
+			path := ctx.GeneratePathFromFilename("foo.pdf")
+			err := os.WriteFile(path, []byte("foo"), 0o600)
+			if err != nil {
+				return err
+			}
+
+			return ctx.AddOutputPaths(path)
+		},
+	}
+
+	handler := cacheHandler(cache, route)
+
+	for _, tc := range []struct {
+		scenario     string
+		values       map[string][]string
+		expectHeader string
+		expectCalls  int
+		expectErr    bool
+	}{
+		{
+			scenario:     "miss",
+			expectHeader: "miss",
+			expectCalls:  1,
+		},
+		{
+			scenario:     "hit",
+			expectHeader: "hit",
+			expectCalls:  1,
+		},
+		{
+			scenario:     "failure is not cached",
+			values:       map[string][]string{"fail": {"true"}},
+			expectHeader: "miss",
+			expectCalls:  2,
+			expectErr:    true,
+		},
+		{
+			scenario:     "failure is still not cached",
+			values:       map[string][]string{"fail": {"true"}},
+			expectHeader: "miss",
+			expectCalls:  3,
+			expectErr:    true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx, c := newCacheTestContext(t, tc.values, map[string]string{"index.html": "<h1>Foo</h1>"})
+
+			err := handler(c)
+
+			if tc.expectErr && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectErr && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if header := c.Response().Header().Get(cacheHeader); header != tc.expectHeader {
+				t.Errorf("expected header '%s', but got '%s'", tc.expectHeader, header)
+			}
+
+			if calls != tc.expectCalls {
+				t.Errorf("expected %d handler call(s), but got %d", tc.expectCalls, calls)
+			}
+
+			if !tc.expectErr && len(ctx.outputPaths) != 1 {
+				t.Errorf("expected 1 output path, but got %d", len(ctx.outputPaths))
+			}
+		})
+	}
+}
+
+func TestCacheHandler_Async(t *testing.T) {
+	cache, err := newResultCache(t.TempDir(), 0, 0, "")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var outputFilenames []string
+	handler := cacheHandler(cache, Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/foo",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*Context)
+			outputFilenames = append(outputFilenames, c.Get("outputFilename").(string))
+
+			path := ctx.GeneratePathFromFilename("foo.pdf")
+			err := os.WriteFile(path, []byte("foo"), 0o600)
+			if err != nil {
+				return err
+			}
+
+			return ctx.AddOutputPaths(path)
+		},
+	})
+
+	for _, outputFilename := range []string{"foo", "bar"} {
+		_, c := newCacheTestContext(t, nil, map[string]string{"index.html": "<h1>Foo</h1>"})
+		c.Set("outputFilename", outputFilename)
+		safeCtx := NewPoolSafeContext(c, "context", "outputFilename")
+
+		// The pooled context now belongs to another request.
+		c.Set("outputFilename", "")
+
+		err = handler(safeCtx)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if header := c.Response().Header().Get(cacheHeader); header != "" {
+			t.Errorf("expected no header, but got '%s'", header)
+		}
+	}
+
+	// Distinct output filenames must not share a cache entry.
+	if len(outputFilenames) != 2 {
+		t.Errorf("expected 2 handler calls, but got %d", len(outputFilenames))
+	}
+}
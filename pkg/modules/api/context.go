@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -12,6 +13,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -49,7 +51,17 @@ type Context struct {
 	filesByField   map[string][]string
 	diskToOriginal map[string]string
 	outputPaths    []string
+	outputFormat   outputFormat
+	archivePath    string
+	archiveType    string
+	uploadTo       []uploadTo
 	cancelled      bool
+	recorder       *formRecorder
+	identity       string
+	asyncDone      func(err error)
+
+	digestAlgorithms []string
+	pdfEngine        gotenberg.PdfEngine
 
 	logger     *slog.Logger
 	echoCtx    echo.Context
@@ -92,13 +104,15 @@ type downloadFrom struct {
 	Embedded bool `json:"embedded"`
 
 	// Field routes the downloaded file to a specific form field bucket.
-	// Supported values: "watermark", "stamp". For embeds, prefer the
-	// Embedded flag or set Field to "embedded".
+	// Supported values: "watermark", "stamp", "facturxXml", "preloadScripts",
+	// "extraStylesheets". For embeds, prefer the Embedded flag or set Field to
+	// "embedded".
 	Field string `json:"field"`
 }
 
 // newContext returns a [Context] by parsing a "multipart/form-data" request.
-func newContext(echoCtx echo.Context, logger *slog.Logger, fs *gotenberg.FileSystem, timeout time.Duration, bodyLimit int64, downloadFromCfg downloadFromConfig) (*Context, context.CancelFunc, error) {
+// The uploads store is nil if resumable uploads are disabled.
+func newContext(echoCtx echo.Context, logger *slog.Logger, fs *gotenberg.FileSystem, timeout time.Duration, bodyLimit int64, downloadFromCfg downloadFromConfig, uploads *uploadStore) (*Context, context.CancelFunc, error) {
 	processCtx, processCancel := context.WithTimeout(echoCtx.Request().Context(), timeout)
 
 	// We want to make sure the multipart/form-data does not exceed a given
@@ -125,6 +139,11 @@ func newContext(echoCtx echo.Context, logger *slog.Logger, fs *gotenberg.FileSys
 		mkdirAll:    new(gotenberg.OsMkdirAll),
 		pathRename:  new(gotenberg.OsPathRename),
 		Context:     processCtx,
+
+		digestAlgorithms: parseWantContentDigest(echoCtx.Request().Header.Get(wantContentDigestHeader)),
+		// The identity is captured upfront, as an asynchronous process
+		// outlives the echo.Context, which goes back to its pool.
+		identity: CallerIdentity(echoCtx),
 	}
 
 	// A custom cancel function which removes the context's working directory
@@ -153,49 +172,80 @@ func newContext(echoCtx echo.Context, logger *slog.Logger, fs *gotenberg.FileSys
 		}
 	}()
 
-	form, err := echoCtx.MultipartForm()
-	if err != nil {
-		if errors.Is(err, http.ErrNotMultipart) {
-			return nil, cancel, WrapError(
-				fmt.Errorf("get multipart form: %w", err),
-				NewSentinelHttpError(http.StatusUnsupportedMediaType, "Invalid 'Content-Type' header value: want 'multipart/form-data'"),
-			)
+	var err error
+	var values map[string][]string
+	var formFiles map[string][]*multipart.FileHeader
+	var inlineFiles []inlineFile
+	var jsonDls []downloadFrom
+
+	if isJsonRequest(echoCtx.Request()) {
+		// This will ensure we do not exceed the body limit.
+		var body []byte
+		body, err = io.ReadAll(&trackingReader{R: echoCtx.Request().Body, AddReadBytes: addReadBytes})
+		if err != nil {
+			return nil, cancel, fmt.Errorf("read JSON body: %w", err)
 		}
 
-		if errors.Is(err, http.ErrMissingBoundary) {
-			return nil, cancel, WrapError(
-				fmt.Errorf("get multipart form: %w", err),
-				NewSentinelHttpError(http.StatusUnsupportedMediaType, "Invalid 'Content-Type' header value: no boundary"),
-			)
+		values, inlineFiles, jsonDls, err = parseJsonBody(body)
+		if err != nil {
+			return nil, cancel, fmt.Errorf("parse JSON body: %w", err)
 		}
 
-		if strings.Contains(err.Error(), io.EOF.Error()) {
+		if downloadFromCfg.disable && len(jsonDls) > 0 {
 			return nil, cancel, WrapError(
-				fmt.Errorf("get multipart form: %w", err),
-				NewSentinelHttpError(http.StatusBadRequest, "Malformed body: it does not match the 'Content-Type' header boundaries"),
+				errors.New("download from feature is disabled"),
+				NewSentinelHttpError(http.StatusBadRequest, "Invalid 'files' JSON body value: the download from feature is disabled, use 'content' instead of 'url'"),
 			)
 		}
-
-		return nil, cancel, fmt.Errorf("get multipart form: %w", err)
-	}
-	defer func() {
-		err := form.RemoveAll()
+	} else {
+		var form *multipart.Form
+		form, err = echoCtx.MultipartForm()
 		if err != nil {
-			logger.ErrorContext(context.Background(), fmt.Sprintf("remove multipart temporary files: %s", err))
+			if errors.Is(err, http.ErrNotMultipart) {
+				return nil, cancel, WrapError(
+					fmt.Errorf("get multipart form: %w", err),
+					NewSentinelHttpError(http.StatusUnsupportedMediaType, "Invalid 'Content-Type' header value: want 'multipart/form-data' or 'application/json'"),
+				)
+			}
+
+			if errors.Is(err, http.ErrMissingBoundary) {
+				return nil, cancel, WrapError(
+					fmt.Errorf("get multipart form: %w", err),
+					NewSentinelHttpError(http.StatusUnsupportedMediaType, "Invalid 'Content-Type' header value: no boundary"),
+				)
+			}
+
+			if strings.Contains(err.Error(), io.EOF.Error()) {
+				return nil, cancel, WrapError(
+					fmt.Errorf("get multipart form: %w", err),
+					NewSentinelHttpError(http.StatusBadRequest, "Malformed body: it does not match the 'Content-Type' header boundaries"),
+				)
+			}
+
+			return nil, cancel, fmt.Errorf("get multipart form: %w", err)
 		}
-	}()
+		defer func() {
+			err := form.RemoveAll()
+			if err != nil {
+				logger.ErrorContext(context.Background(), fmt.Sprintf("remove multipart temporary files: %s", err))
+			}
+		}()
 
-	// This will ensure we do not exceed the body limit.
-	var formValuesSize int64
-	for key, valArray := range form.Value {
-		formValuesSize += int64(len(key))
-		for _, val := range valArray {
-			formValuesSize += int64(len(val))
+		// This will ensure we do not exceed the body limit.
+		var formValuesSize int64
+		for key, valArray := range form.Value {
+			formValuesSize += int64(len(key))
+			for _, val := range valArray {
+				formValuesSize += int64(len(val))
+			}
 		}
-	}
-	err = addReadBytes(formValuesSize)
-	if err != nil {
-		return nil, cancel, fmt.Errorf("add read bytes: %w", err)
+		err = addReadBytes(formValuesSize)
+		if err != nil {
+			return nil, cancel, fmt.Errorf("add read bytes: %w", err)
+		}
+
+		values = form.Value
+		formFiles = form.File
 	}
 
 	dirPath, err := fs.MkdirAll()
@@ -204,16 +254,25 @@ func newContext(echoCtx echo.Context, logger *slog.Logger, fs *gotenberg.FileSys
 	}
 
 	ctx.dirPath = dirPath
-	ctx.values = form.Value
+	ctx.values = values
+
+	var formOutputFormat string
+	if v, ok := values[OutputFormatFormField]; ok && len(v) > 0 {
+		formOutputFormat = v[0]
+	}
+	ctx.outputFormat, err = parseOutputFormat(formOutputFormat, echoCtx.Request().Header.Get(echo.HeaderAccept))
+	if err != nil {
+		return nil, cancel, err
+	}
 	ctx.files = make(map[string]string)
 	ctx.filesByField = make(map[string][]string)
 	ctx.diskToOriginal = make(map[string]string)
 
-	// First, try to download files listed in the "downloadFrom" form field, if
-	// any.
+	// First, try to download files listed in the "downloadFrom" form field or
+	// given as URLs in a JSON body, if any.
+	var dls []downloadFrom
 	raw, ok := ctx.values["downloadFrom"]
 	if !downloadFromCfg.disable && ok {
-		var dls []downloadFrom
 		err = json.Unmarshal([]byte(raw[0]), &dls)
 		if err != nil {
 			return nil, cancel, WrapError(
@@ -221,7 +280,10 @@ func newContext(echoCtx echo.Context, logger *slog.Logger, fs *gotenberg.FileSys
 				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'downloadFrom' form field value: %s", err)),
 			)
 		}
+	}
+	dls = append(dls, jsonDls...)
 
+	if len(dls) > 0 {
 		// Each goroutine writes to its own results slot. The main
 		// goroutine merges into ctx.files, ctx.diskToOriginal, and
 		// ctx.filesByField after eg.Wait() to avoid concurrent map
@@ -415,6 +477,12 @@ func newContext(echoCtx echo.Context, logger *slog.Logger, fs *gotenberg.FileSys
 					formField = WatermarkFormField
 				case dl.Field == "stamp":
 					formField = StampFormField
+				case dl.Field == "facturxXml":
+					formField = FacturXXmlFormField
+				case dl.Field == "preloadScripts":
+					formField = PreloadScriptsFormField
+				case dl.Field == "extraStylesheets":
+					formField = ExtraStylesheetsFormField
 				}
 				results[i] = downloadFromResult{filename: filename, path: path, formField: formField}
 
@@ -436,28 +504,50 @@ func newContext(echoCtx echo.Context, logger *slog.Logger, fs *gotenberg.FileSys
 		}
 	}
 
-	copyToDisk := func(fh *multipart.FileHeader) error {
-		in, err := fh.Open()
+	// Then, link the completed resumable uploads listed in the "uploads" form
+	// field, if any.
+	raw, ok = ctx.values[UploadsFormField]
+	if ok {
+		if uploads == nil {
+			return ctx, cancel, WrapError(
+				errors.New("resumable uploads are disabled"),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' form field value: resumable uploads are disabled", UploadsFormField)),
+			)
+		}
+
+		var ids []string
+		err = json.Unmarshal([]byte(raw[0]), &ids)
 		if err != nil {
-			return fmt.Errorf("open multipart file: %w", err)
+			return ctx, cancel, WrapError(
+				fmt.Errorf("unmarshal json: %w", err),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' form field value: %s", UploadsFormField, err)),
+			)
 		}
 
-		defer func() {
-			err := in.Close()
+		for _, id := range ids {
+			u, path, err := uploads.link(id, ctx.dirPath)
 			if err != nil {
-				logger.ErrorContext(context.Background(), fmt.Sprintf("close file header: %s", err))
+				return ctx, cancel, fmt.Errorf("link upload '%s': %w", id, err)
 			}
-		}()
 
-		// This will ensure we do not exceed the body limit.
-		reader := &trackingReader{R: in, AddReadBytes: addReadBytes}
+			// This will ensure we do not exceed the body limit.
+			err = addReadBytes(u.length)
+			if err != nil {
+				return ctx, cancel, fmt.Errorf("add read bytes: %w", err)
+			}
+
+			ctx.files[u.filename] = path
+			ctx.diskToOriginal[path] = u.filename
+		}
+	}
 
+	writeToDisk := func(originalFilename string, reader io.Reader) error {
 		// Strip path separators (including backslashes) and control
 		// characters, then NFC-normalize. Defends against directory
 		// traversal in the on-disk name and Windows-side Zip Slip when the
 		// original filename is later embedded in an output zip entry.
 		// See: https://github.com/gotenberg/gotenberg/issues/662.
-		filename := sanitizeFilename(fh.Filename)
+		filename := sanitizeFilename(originalFilename)
 
 		// Use a UUID-based name on disk to avoid filesystem
 		// NAME_MAX limits with long filenames.
@@ -478,7 +568,7 @@ func newContext(echoCtx echo.Context, logger *slog.Logger, fs *gotenberg.FileSys
 
 		_, err = io.Copy(out, reader)
 		if err != nil {
-			return fmt.Errorf("copy multipart file to local file: %w", err)
+			return fmt.Errorf("copy file to local file: %w", err)
 		}
 
 		ctx.files[filename] = path
@@ -487,8 +577,36 @@ func newContext(echoCtx echo.Context, logger *slog.Logger, fs *gotenberg.FileSys
 		return nil
 	}
 
+	copyToDisk := func(fh *multipart.FileHeader) error {
+		in, err := fh.Open()
+		if err != nil {
+			return fmt.Errorf("open multipart file: %w", err)
+		}
+
+		defer func() {
+			err := in.Close()
+			if err != nil {
+				logger.ErrorContext(context.Background(), fmt.Sprintf("close file header: %s", err))
+			}
+		}()
+
+		// This will ensure we do not exceed the body limit.
+		return writeToDisk(fh.Filename, &trackingReader{R: in, AddReadBytes: addReadBytes})
+	}
+
+	// Then, write the files from a JSON body, if any. The body limit already
+	// accounts for them.
+	for _, f := range inlineFiles {
+		err = writeToDisk(f.filename, bytes.NewReader(f.content))
+		if err != nil {
+			return ctx, cancel, fmt.Errorf("write to disk: %w", err)
+		}
+		filePath := ctx.files[sanitizeFilename(f.filename)]
+		ctx.filesByField[f.field] = append(ctx.filesByField[f.field], filePath)
+	}
+
 	// Then, copy the form files, if any.
-	for fieldName, files := range form.File {
+	for fieldName, files := range formFiles {
 		for _, fh := range files {
 			err = copyToDisk(fh)
 			if err != nil {
@@ -539,9 +657,22 @@ func (ctx *Context) FormData() *FormData {
 		filesByField:   ctx.filesByField,
 		diskToOriginal: ctx.diskToOriginal,
 		errors:         nil,
+		recorder:       ctx.recorder,
 	}
 }
 
+// WithFormValues returns a copy of the context whose [Context.FormData] reads
+// the given values instead of the request's form fields. Files, working
+// directory and lifecycle are shared with the original context, but not the
+// output paths: the caller has to add them to the original context.
+func (ctx *Context) WithFormValues(values map[string][]string) *Context {
+	c := *ctx
+	c.values = values
+	c.outputPaths = nil
+
+	return &c
+}
+
 // FileCount returns the number of files received in the request.
 func (ctx *Context) FileCount() int {
 	return len(ctx.files)
@@ -629,6 +760,33 @@ func (ctx *Context) Log() *slog.Logger {
 	return ctx.logger
 }
 
+// CallerIdentity returns the identity of the caller, as set by the
+// authentication middlewares, falling back to the subject of its client
+// certificate. It returns an empty string for an anonymous caller.
+func CallerIdentity(c echo.Context) string {
+	identity, _ := c.Get("identity").(string)
+	if identity == "" {
+		identity, _ = c.Get("clientCertSubject").(string)
+	}
+
+	return identity
+}
+
+// AsyncDone reports the end of an asynchronous process, with its error if
+// any, e.g., to the audit log. Only the first call counts, and it must happen
+// while the output files are still in the working directory.
+func (ctx *Context) AsyncDone(err error) {
+	if ctx.asyncDone != nil {
+		ctx.asyncDone(err)
+	}
+}
+
+// Identity returns the identity of the caller, as of the creation of the
+// context. See [CallerIdentity].
+func (ctx *Context) Identity() string {
+	return ctx.identity
+}
+
 // BuildOutputFile builds the output file according to the output paths
 // registered in the context. If many output paths, an archive is created.
 func (ctx *Context) BuildOutputFile() (string, error) {
@@ -645,37 +803,53 @@ func (ctx *Context) BuildOutputFile() (string, error) {
 		return ctx.outputPaths[0], nil
 	}
 
-	filesInfo, err := archives.FilesFromDisk(ctx.Context, nil, func() map[string]string {
-		f := make(map[string]string)
-		for _, outputPath := range ctx.outputPaths {
-			f[outputPath] = ctx.OriginalFilename(outputPath)
-		}
-		return f
-	}())
+	// The manifest lists the digests of the output files, so that a client
+	// may check them once extracted.
+	paths := ctx.outputPaths
+	manifestPath, err := ctx.writeManifest()
 	if err != nil {
-		return "", fmt.Errorf("create files info: %w", err)
+		return "", fmt.Errorf("build output file: %w", err)
 	}
-
-	archivePath := ctx.GeneratePath(".zip")
-	out, err := os.Create(archivePath)
-	if err != nil {
-		return "", fmt.Errorf("create zip file: %w", err)
+	if manifestPath != "" {
+		paths = append(slices.Clone(paths), manifestPath)
 	}
-	defer func(out *os.File) {
-		err := out.Close()
-		if err != nil {
-			ctx.logger.ErrorContext(ctx, fmt.Sprintf("close zip file: %s", err))
-		}
-	}(out)
 
-	err = archives.Zip{}.Archive(ctx.Context, out, filesInfo)
+	switch ctx.outputFormat {
+	case outputFormatTar:
+		ctx.archivePath = ctx.GeneratePath(".tar")
+		ctx.archiveType = "application/x-tar"
+		err = ctx.archiveOutputFiles(archives.Tar{}, ctx.archivePath, paths)
+	case outputFormatTarGz:
+		ctx.archivePath = ctx.GeneratePath(".tar.gz")
+		ctx.archiveType = "application/gzip"
+		err = ctx.archiveOutputFiles(archives.CompressedArchive{Archival: archives.Tar{}, Compression: archives.Gz{}}, ctx.archivePath, paths)
+	case outputFormatMultipart:
+		ctx.archivePath = ctx.GeneratePath(".multipart")
+		ctx.archiveType, err = ctx.writeMultipartOutputFiles(ctx.archivePath, paths)
+	default:
+		ctx.archivePath = ctx.GeneratePath(".zip")
+		ctx.archiveType = "application/zip"
+		err = ctx.archiveOutputFiles(archives.Zip{}, ctx.archivePath, paths)
+	}
 	if err != nil {
-		return "", fmt.Errorf("archive output files: %w", err)
+		return "", fmt.Errorf("build output file: %w", err)
 	}
 
-	ctx.logger.DebugContext(ctx, fmt.Sprintf("archive '%s' created", archivePath))
+	ctx.logger.DebugContext(ctx, fmt.Sprintf("archive '%s' created", ctx.archivePath))
+
+	return ctx.archivePath, nil
+}
+
+// OutputContentType returns the content type of the output file built by
+// [Context.BuildOutputFile] from many output files, e.g., "application/zip"
+// or "multipart/mixed; boundary=...". It returns an empty string for a single
+// output file, which content type depends on the file itself.
+func (ctx *Context) OutputContentType(outputPath string) string {
+	if outputPath == "" || outputPath != ctx.archivePath {
+		return ""
+	}
 
-	return archivePath, nil
+	return ctx.archiveType
 }
 
 // OutputFilename returns the filename based on the given output path or the
@@ -687,7 +861,7 @@ func (ctx *Context) OutputFilename(outputPath string) string {
 		return ctx.OriginalFilename(outputPath)
 	}
 
-	return fmt.Sprintf("%s%s", filename, filepath.Ext(outputPath))
+	return fmt.Sprintf("%s%s", filename, OutputExt(outputPath))
 }
 
 // sanitizeFilename strips path separators (including backslashes, which
@@ -46,7 +46,7 @@ func TestNewContext_Cancellation(t *testing.T) {
 		disable: true,
 	}
 
-	ctx, cancel, err := newContext(c, logger, fs, timeout, 0, downloadFromCfg)
+	ctx, cancel, err := newContext(c, logger, fs, timeout, 0, downloadFromCfg, nil)
 	if err != nil {
 		t.Fatalf("expected no error from newContext, got: %v", err)
 	}
@@ -120,7 +120,7 @@ func TestNewContext_RemovesMultipartTemporaryFiles(t *testing.T) {
 	fs := gotenberg.NewFileSystem(new(gotenberg.OsMkdirAll))
 	downloadFromCfg := downloadFromConfig{disable: true}
 
-	_, cancel, err := newContext(echoCtx, logger, fs, 10*time.Second, 0, downloadFromCfg)
+	_, cancel, err := newContext(echoCtx, logger, fs, 10*time.Second, 0, downloadFromCfg, nil)
 	if err != nil {
 		t.Fatalf("newContext returned error: %v", err)
 	}
@@ -132,6 +132,41 @@ func TestNewContext_RemovesMultipartTemporaryFiles(t *testing.T) {
 	}
 }
 
+// The identity must survive the echo.Context going back to its pool, as
+// asynchronous processes (webhook, jobs) outlive the request.
+func TestNewContext_IdentityOutlivesEchoContext(t *testing.T) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	err := writer.Close()
+	if err != nil {
+		t.Fatalf("failed to close multipart writer: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	echoCtx := echo.New().NewContext(req, httptest.NewRecorder())
+	echoCtx.Set("identity", "alice")
+
+	logger := slog.New(slog.DiscardHandler)
+	fs := gotenberg.NewFileSystem(new(gotenberg.OsMkdirAll))
+	downloadFromCfg := downloadFromConfig{disable: true}
+
+	ctx, cancel, err := newContext(echoCtx, logger, fs, 10*time.Second, 0, downloadFromCfg, nil)
+	if err != nil {
+		t.Fatalf("newContext returned error: %v", err)
+	}
+	defer cancel()
+
+	// Another request claims the pooled context.
+	echoCtx.Reset(req, httptest.NewRecorder())
+	echoCtx.Set("identity", "bob")
+
+	if ctx.Identity() != "alice" {
+		t.Errorf("expected identity 'alice' but got '%s'", ctx.Identity())
+	}
+}
+
 // Concurrent downloadFrom entries must not race on the shared maps
 // (ctx.files, ctx.diskToOriginal, ctx.filesByField). Run under -race
 // to catch the data race; without -race a sufficient number of entries
@@ -192,7 +227,7 @@ func TestNewContext_DownloadFromConcurrentMapWrites(t *testing.T) {
 		maxRetry: 0,
 	}
 
-	ctx, cancel, err := newContext(echoCtx, logger, fs, 10*time.Second, 0, downloadFromCfg)
+	ctx, cancel, err := newContext(echoCtx, logger, fs, 10*time.Second, 0, downloadFromCfg, nil)
 	if err != nil {
 		t.Fatalf("newContext returned error: %v", err)
 	}
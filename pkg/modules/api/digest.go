@@ -0,0 +1,207 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ContentDigestHeader is the header which carries the digests of an
+	// output file, as described in RFC 9530.
+	ContentDigestHeader = "Content-Digest"
+
+	// wantContentDigestHeader lists the digest algorithms the client prefers
+	// for the "Content-Digest" header, with their weights, as described in
+	// RFC 9530, e.g., "sha-512=10, sha-256=1".
+	wantContentDigestHeader = "Want-Content-Digest"
+
+	// defaultDigestAlgorithm is the digest algorithm of the "Content-Digest"
+	// header if the client does not state its preferences.
+	defaultDigestAlgorithm = "sha-256"
+
+	// manifestFilename is the name of the manifest added to the output files
+	// of an archive or a "multipart/mixed" body.
+	manifestFilename = "manifest.json"
+)
+
+// digestAlgorithms are the supported digest algorithms, by their name in the
+// IANA "Hash Algorithms for HTTP Digest Fields" registry.
+var digestAlgorithms = map[string]func() hash.Hash{
+	"sha-256": sha256.New,
+	"sha-512": sha512.New,
+}
+
+// parseWantContentDigest returns the supported digest algorithms of a
+// "Want-Content-Digest" header value, by decreasing weight. A weight of 0
+// means "not acceptable". It returns the default algorithm if the value lists
+// none.
+func parseWantContentDigest(value string) []string {
+	type preference struct {
+		algorithm string
+		weight    int
+	}
+
+	var preferences []preference
+	for _, member := range strings.Split(value, ",") {
+		key, weight, ok := strings.Cut(member, "=")
+		if !ok {
+			continue
+		}
+
+		algorithm := strings.ToLower(strings.TrimSpace(key))
+		if _, supported := digestAlgorithms[algorithm]; !supported {
+			continue
+		}
+
+		w, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil || w <= 0 {
+			continue
+		}
+
+		preferences = append(preferences, preference{algorithm: algorithm, weight: w})
+	}
+
+	if len(preferences) == 0 {
+		return []string{defaultDigestAlgorithm}
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].weight > preferences[j].weight
+	})
+
+	algorithms := make([]string, 0, len(preferences))
+	for _, p := range preferences {
+		algorithms = append(algorithms, p.algorithm)
+	}
+
+	return algorithms
+}
+
+// digestFileWith returns the size of a file and its digests with the given
+// algorithms.
+func digestFileWith(path string, algorithms []string) (int64, map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, nil, fmt.Errorf("open file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hashes := make(map[string]hash.Hash, len(algorithms))
+	writers := make([]io.Writer, 0, len(algorithms))
+	for _, algorithm := range algorithms {
+		h := digestAlgorithms[algorithm]()
+		hashes[algorithm] = h
+		writers = append(writers, h)
+	}
+
+	size, err := io.Copy(io.MultiWriter(writers...), f)
+	if err != nil {
+		return 0, nil, fmt.Errorf("hash file: %w", err)
+	}
+
+	sums := make(map[string][]byte, len(hashes))
+	for algorithm, h := range hashes {
+		sums[algorithm] = h.Sum(nil)
+	}
+
+	return size, sums, nil
+}
+
+// OutputDigest returns the "Content-Digest" header value of an output file,
+// e.g., "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:", with the
+// digest algorithms preferred by the client.
+func (ctx *Context) OutputDigest(outputPath string) (string, error) {
+	algorithms := ctx.digestAlgorithms
+	if len(algorithms) == 0 {
+		algorithms = []string{defaultDigestAlgorithm}
+	}
+
+	_, sums, err := digestFileWith(outputPath, algorithms)
+	if err != nil {
+		return "", fmt.Errorf("digest output file: %w", err)
+	}
+
+	members := make([]string, 0, len(algorithms))
+	for _, algorithm := range algorithms {
+		members = append(members, fmt.Sprintf("%s=:%s:", algorithm, base64.StdEncoding.EncodeToString(sums[algorithm])))
+	}
+
+	return strings.Join(members, ", "), nil
+}
+
+// manifestEntry describes an output file in the manifest.
+type manifestEntry struct {
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	Sha256   string `json:"sha256"`
+	Sha512   string `json:"sha512"`
+	Pages    *int   `json:"pages,omitempty"`
+}
+
+// writeManifest writes the manifest of the output files, i.e., their
+// digests and, for PDFs, their page counts, and returns its path. It returns
+// an empty path if an output file already has the name of the manifest.
+func (ctx *Context) writeManifest() (string, error) {
+	entries := make([]manifestEntry, 0, len(ctx.outputPaths))
+
+	for _, outputPath := range ctx.outputPaths {
+		filename := ctx.OriginalFilename(outputPath)
+		if filename == manifestFilename {
+			ctx.logger.DebugContext(ctx, fmt.Sprintf("an output file is named '%s', skip manifest", manifestFilename))
+			return "", nil
+		}
+
+		size, sums, err := digestFileWith(outputPath, []string{"sha-256", "sha-512"})
+		if err != nil {
+			return "", fmt.Errorf("digest output file '%s': %w", filename, err)
+		}
+
+		entry := manifestEntry{
+			Filename: filename,
+			Size:     size,
+			Sha256:   hex.EncodeToString(sums["sha-256"]),
+			Sha512:   hex.EncodeToString(sums["sha-512"]),
+		}
+
+		if ctx.pdfEngine != nil && strings.EqualFold(filepath.Ext(outputPath), ".pdf") {
+			pages, err := ctx.pdfEngine.PageCount(ctx, ctx.logger, outputPath)
+			if err != nil {
+				// The page count is informative; the digests matter.
+				ctx.logger.DebugContext(ctx, fmt.Sprintf("count pages of '%s': %s", filename, err))
+			} else {
+				entry.Pages = &pages
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	b, err := json.MarshalIndent(struct {
+		Files []manifestEntry `json:"files"`
+	}{Files: entries}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	path := ctx.GeneratePath(".json")
+	err = os.WriteFile(path, b, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("write manifest: %w", err)
+	}
+	ctx.RegisterDiskPath(path, manifestFilename)
+
+	return path, nil
+}
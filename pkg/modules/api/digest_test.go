@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestParseWantContentDigest(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		value    string
+		expect   []string
+	}{
+		{scenario: "no preferences", value: "", expect: []string{"sha-256"}},
+		{scenario: "by decreasing weight", value: "sha-256=1, sha-512=10", expect: []string{"sha-512", "sha-256"}},
+		{scenario: "not acceptable", value: "sha-256=0, sha-512=3", expect: []string{"sha-512"}},
+		{scenario: "unsupported algorithms", value: "md5=10, unixsum=3", expect: []string{"sha-256"}},
+		{scenario: "malformed members", value: "sha-512, sha-256=foo", expect: []string{"sha-256"}},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			algorithms := parseWantContentDigest(tc.value)
+			if !reflect.DeepEqual(algorithms, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, algorithms)
+			}
+		})
+	}
+}
+
+func TestContext_OutputDigest(t *testing.T) {
+	path := t.TempDir() + "/foo.txt"
+	err := os.WriteFile(path, []byte("hello"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario   string
+		algorithms []string
+		expect     string
+	}{
+		{
+			scenario: "default",
+			expect:   "sha-256=:LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=:",
+		},
+		{
+			scenario:   "many algorithms",
+			algorithms: []string{"sha-512", "sha-256"},
+			expect:     "sha-512=:m3HSJL1i83hdltRq0+o9czGb+8KJDKra4t/3JRlnPKcjI8PZm6XBHXx6zG4UuMXaDEZjR1wuXDre9G9zvN7AQw==:, sha-256=:LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=:",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &Context{digestAlgorithms: tc.algorithms}
+
+			digest, err := ctx.OutputDigest(path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if digest != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, digest)
+			}
+		})
+	}
+}
+
+func TestContext_writeManifest(t *testing.T) {
+	newManifestContext := func(t *testing.T, filenames ...string) *Context {
+		t.Helper()
+
+		ctx := &Context{
+			dirPath:        t.TempDir(),
+			diskToOriginal: make(map[string]string),
+			logger:         slog.New(slog.DiscardHandler),
+			Context:        context.Background(),
+			pdfEngine: &gotenberg.PdfEngineMock{
+				PageCountMock: func(ctx context.Context, logger *slog.Logger, inputPath string) (int, error) {
+					return 3, nil
+				},
+			},
+		}
+
+		for _, filename := range filenames {
+			path := ctx.GeneratePathFromFilename(filename)
+			err := os.WriteFile(path, []byte("hello"), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+			ctx.outputPaths = append(ctx.outputPaths, path)
+		}
+
+		return ctx
+	}
+
+	ctx := newManifestContext(t, "a.pdf", "b.png")
+
+	path, err := ctx.writeManifest()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if ctx.OriginalFilename(path) != manifestFilename {
+		t.Errorf("expected the manifest to be named '%s' but got '%s'", manifestFilename, ctx.OriginalFilename(path))
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var manifest struct {
+		Files []manifestEntry `json:"files"`
+	}
+	err = json.Unmarshal(b, &manifest)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	pages := 3
+	expect := []manifestEntry{
+		{
+			Filename: "a.pdf",
+			Size:     5,
+			Sha256:   "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			Sha512:   "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043",
+			Pages:    &pages,
+		},
+		{
+			Filename: "b.png",
+			Size:     5,
+			Sha256:   "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			Sha512:   "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043",
+		},
+	}
+	if !reflect.DeepEqual(manifest.Files, expect) {
+		t.Errorf("expected manifest %+v but got %+v", expect, manifest.Files)
+	}
+
+	// An output file with the name of the manifest takes precedence.
+	ctx = newManifestContext(t, "a.pdf", manifestFilename)
+
+	path, err = ctx.writeManifest()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if path != "" {
+		t.Errorf("expected no manifest but got '%s'", path)
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/alexliesenfeld/health"
+	"github.com/labstack/echo/v4"
+)
+
+// drainState tells whether the instance is draining, i.e., finishing its
+// queued, active and asynchronous conversions without accepting new ones.
+type drainState struct {
+	draining      atomic.Bool
+	queueCounters []QueueCounter
+	asyncCounters []AsynchronousCounter
+}
+
+// drainStatus is the JSON body of the drain routes. An orchestrator may wait
+// for all the counts to reach zero before terminating the instance.
+type drainStatus struct {
+	Draining bool  `json:"draining"`
+	Queued   int64 `json:"queued"`
+	Active   int64 `json:"active"`
+	Async    int64 `json:"async"`
+}
+
+func (d *drainState) status() drainStatus {
+	status := drainStatus{Draining: d.draining.Load()}
+
+	for _, queueCounter := range d.queueCounters {
+		status.Queued += queueCounter.QueuedCount()
+		status.Active += queueCounter.ActiveCount()
+	}
+
+	for _, asyncCounter := range d.asyncCounters {
+		status.Async += asyncCounter.AsyncCount()
+	}
+
+	return status
+}
+
+// readinessCheck fails while the instance is draining, so that a load
+// balancer or an orchestrator stops routing new requests to it. It is not a
+// health check: a failing liveness probe would restart the instance in the
+// middle of the conversions it is finishing.
+func (d *drainState) readinessCheck() health.CheckerOption {
+	return health.WithCheck(health.Check{
+		Name: "api",
+		Check: func(_ context.Context) error {
+			if d.draining.Load() {
+				return errors.New("the instance is draining")
+			}
+
+			return nil
+		},
+	})
+}
+
+// drainMiddleware rejects new conversions with a 503 while the instance is
+// draining.
+func drainMiddleware(d *drainState) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !d.draining.Load() {
+				return next(c)
+			}
+
+			// A duplicate of a stored response does not run a conversion.
+			if req, ok := c.Get("idempotency").(*idempotencyRequest); ok && req.replay {
+				return next(c)
+			}
+
+			return WrapError(
+				errors.New("instance is draining"),
+				NewSentinelHttpError(http.StatusServiceUnavailable, "The instance is draining and does not accept new conversions."),
+			)
+		}
+	}
+}
+
+// drainHandler sets whether the instance is draining and returns the
+// remaining queued, active and asynchronous conversions.
+func drainHandler(d *drainState, draining bool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if d.draining.Swap(draining) != draining {
+			logger, _ := c.Get("logger").(*slog.Logger)
+			if logger != nil {
+				logger.InfoContext(c.Request().Context(), fmt.Sprintf("draining set to %t", draining))
+			}
+		}
+
+		return c.JSON(http.StatusOK, d.status())
+	}
+}
+
+// drainStatusHandler returns whether the instance is draining and the
+// remaining queued, active and asynchronous conversions.
+func drainStatusHandler(d *drainState) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, d.status())
+	}
+}
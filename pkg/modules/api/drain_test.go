@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexliesenfeld/health"
+	"github.com/labstack/echo/v4"
+)
+
+type asyncCounterMock int64
+
+func (count asyncCounterMock) AsyncCount() int64 {
+	return int64(count)
+}
+
+func TestDrain(t *testing.T) {
+	d := &drainState{
+		queueCounters: []QueueCounter{
+			&QueueCounterMock{
+				QueuedCountMock: func() int64 { return 2 },
+				ActiveCountMock: func() int64 { return 1 },
+			},
+			&QueueCounterMock{
+				QueuedCountMock: func() int64 { return 0 },
+				ActiveCountMock: func() int64 { return 3 },
+			},
+		},
+		asyncCounters: []AsynchronousCounter{asyncCounterMock(4)},
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = httpErrorHandler()
+	e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("logger", slog.New(slog.DiscardHandler))
+			return next(c)
+		}
+	})
+	e.GET("/admin/drain", drainStatusHandler(d))
+	e.POST("/admin/drain", drainHandler(d, true))
+	e.POST("/admin/undrain", drainHandler(d, false))
+	e.POST("/forms/foo", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, drainMiddleware(d))
+
+	checker := health.NewChecker(d.readinessCheck(), health.WithDisabledCache())
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	expectStatus := func(rec *httptest.ResponseRecorder, expect drainStatus) {
+		t.Helper()
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		var status drainStatus
+		err := json.Unmarshal(rec.Body.Bytes(), &status)
+		if err != nil {
+			t.Fatalf("unmarshal drain status: %v", err)
+		}
+
+		if status != expect {
+			t.Errorf("expected drain status %+v, got %+v", expect, status)
+		}
+	}
+
+	expectConversion := func(expect int) {
+		t.Helper()
+
+		rec := do(http.MethodPost, "/forms/foo")
+		if rec.Code != expect {
+			t.Errorf("expected conversion status %d, got %d", expect, rec.Code)
+		}
+	}
+
+	expectReadiness := func(expect health.AvailabilityStatus) {
+		t.Helper()
+
+		result := checker.Check(context.Background())
+		if result.Status != expect {
+			t.Errorf("expected readiness status %s, got %s", expect, result.Status)
+		}
+	}
+
+	expectStatus(do(http.MethodGet, "/admin/drain"), drainStatus{Draining: false, Queued: 2, Active: 4, Async: 4})
+	expectConversion(http.StatusOK)
+	expectReadiness(health.StatusUp)
+
+	expectStatus(do(http.MethodPost, "/admin/drain"), drainStatus{Draining: true, Queued: 2, Active: 4, Async: 4})
+	expectConversion(http.StatusServiceUnavailable)
+	expectReadiness(health.StatusDown)
+
+	// Draining twice is a no-op.
+	expectStatus(do(http.MethodPost, "/admin/drain"), drainStatus{Draining: true, Queued: 2, Active: 4, Async: 4})
+
+	expectStatus(do(http.MethodPost, "/admin/undrain"), drainStatus{Draining: false, Queued: 2, Active: 4, Async: 4})
+	expectConversion(http.StatusOK)
+	expectReadiness(health.StatusUp)
+}
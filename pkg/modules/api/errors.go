@@ -1,5 +1,7 @@
 package api
 
+import "errors"
+
 // Credits: https://www.joeshaw.org/error-handling-in-go-http-applications.
 
 // HttpError is an interface allowing to retrieve the HTTP details of an error.
@@ -61,11 +63,74 @@ func (w sentinelWrappedError) HttpError() (int, string) {
 //	    "Hey, you did something wrong!"
 //	  ),
 //	)
+//
+// A screenshot attached to the given error, if any, remains attached to the
+// wrapped error. See [WithScreenshot].
 func WrapError(err error, sentinel SentinelHttpError) error {
-	return sentinelWrappedError{
+	wrapped := sentinelWrappedError{
 		error:    err,
 		sentinel: sentinel,
 	}
+
+	screenshot := ErrorScreenshot(err)
+	if screenshot != nil {
+		return WithScreenshot(wrapped, screenshot)
+	}
+
+	return wrapped
+}
+
+// screenshotError contains both an error and a screenshot of the page in which
+// it happened.
+type screenshotError struct {
+	error
+	screenshot []byte
+}
+
+func (err screenshotError) Unwrap() error {
+	return err.error
+}
+
+// WithScreenshot attaches a PNG screenshot to the given error, e.g., the page
+// that Chromium was converting when the error happened. The screenshot comes
+// with the error in the response, or in the request to the webhook error URL.
+// See [ErrorBody].
+func WithScreenshot(err error, screenshot []byte) error {
+	return screenshotError{
+		error:      err,
+		screenshot: screenshot,
+	}
+}
+
+// ErrorScreenshot returns the screenshot attached to the given error, if any.
+func ErrorScreenshot(err error) []byte {
+	var screenshotErr screenshotError
+	if errors.As(err, &screenshotErr) {
+		return screenshotErr.screenshot
+	}
+
+	return nil
+}
+
+// ErrorBody is the JSON representation of an error.
+type ErrorBody struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+
+	// Screenshot is the screenshot attached to the error, if any. It is
+	// base64-encoded in the JSON representation.
+	Screenshot []byte `json:"screenshot,omitempty"`
+}
+
+// NewErrorBody parses an error and returns its JSON representation.
+func NewErrorBody(err error) ErrorBody {
+	status, message := ParseError(err)
+
+	return ErrorBody{
+		Status:     status,
+		Message:    message,
+		Screenshot: ErrorScreenshot(err),
+	}
 }
 
 // Interface guards.
@@ -74,4 +139,5 @@ var (
 	_ HttpError = (*SentinelHttpError)(nil)
 	_ error     = (*sentinelWrappedError)(nil)
 	_ HttpError = (*sentinelWrappedError)(nil)
+	_ error     = (*screenshotError)(nil)
 )
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
@@ -106,3 +109,51 @@ func TestWrapError(t *testing.T) {
 		t.Errorf("expected %v but got %v", expect, actual)
 	}
 }
+
+func TestWrapError_WithScreenshot(t *testing.T) {
+	screenshot := []byte("foo")
+
+	err := WrapError(
+		fmt.Errorf("convert: %w", WithScreenshot(errors.New("foo"), screenshot)),
+		NewSentinelHttpError(http.StatusConflict, "foo"),
+	)
+
+	if !bytes.Equal(ErrorScreenshot(err), screenshot) {
+		t.Errorf("expected screenshot '%s' but got '%s'", screenshot, ErrorScreenshot(err))
+	}
+
+	status, message := ParseError(err)
+	if status != http.StatusConflict || message != "foo" {
+		t.Errorf("expected %d and 'foo' but got %d and '%s'", http.StatusConflict, status, message)
+	}
+}
+
+func TestNewErrorBody(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		err      error
+		expect   string
+	}{
+		{
+			scenario: "without screenshot",
+			err:      NewSentinelHttpError(http.StatusBadRequest, "foo"),
+			expect:   `{"status":400,"message":"foo"}`,
+		},
+		{
+			scenario: "with screenshot",
+			err:      WithScreenshot(NewSentinelHttpError(http.StatusBadRequest, "foo"), []byte("foo")),
+			expect:   `{"status":400,"message":"foo","screenshot":"Zm9v"}`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			b, err := json.Marshal(NewErrorBody(tc.err))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if string(b) != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, string(b))
+			}
+		})
+	}
+}
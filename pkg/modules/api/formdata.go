@@ -30,6 +30,14 @@ const (
 	// FacturXXmlFormField represents the form field name for the Factur-X CII
 	// invoice XML file.
 	FacturXXmlFormField string = "facturxXml"
+
+	// PreloadScriptsFormField represents the form field name for the scripts
+	// to run before the scripts of a page, either inline or as files.
+	PreloadScriptsFormField string = "preloadScripts"
+
+	// ExtraStylesheetsFormField represents the form field name for the
+	// stylesheets to add to a page, either inline or as files.
+	ExtraStylesheetsFormField string = "extraStylesheets"
 )
 
 // FormData is a helper for validating and hydrating values from a
@@ -42,6 +50,9 @@ type FormData struct {
 	filesByField   map[string][]string
 	diskToOriginal map[string]string
 	errors         error
+
+	// recorder is only set while describing a route, see [describeRoute].
+	recorder *formRecorder
 }
 
 // Validate returns nil or an error related to the [FormData] values, with a
@@ -54,6 +65,11 @@ type FormData struct {
 //	   MandatoryString("foo", &foo, "bar").
 //	   Validate()
 func (form *FormData) Validate() error {
+	if form.recorder != nil {
+		// Stop the route handler before it does any actual work.
+		return errFormDataRecorded
+	}
+
 	if form.errors == nil {
 		return nil
 	}
@@ -70,6 +86,8 @@ func (form *FormData) Validate() error {
 //
 //	ctx.FormData().String("foo", &foo, "bar")
 func (form *FormData) String(key string, target *string, defaultValue string) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: "string", Default: defaultValue})
+
 	return form.mustValue(key, target, defaultValue)
 }
 
@@ -80,6 +98,8 @@ func (form *FormData) String(key string, target *string, defaultValue string) *F
 //
 //	ctx.FormData().MandatoryString("foo", &foo)
 func (form *FormData) MandatoryString(key string, target *string) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: "string", Required: true})
+
 	return form.mustMandatoryField(key, target)
 }
 
@@ -90,6 +110,8 @@ func (form *FormData) MandatoryString(key string, target *string) *FormData {
 //
 //	ctx.FormData().Bool("foo", &foo, true)
 func (form *FormData) Bool(key string, target *bool, defaultValue bool) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: "boolean", Default: defaultValue})
+
 	return form.mustValue(key, target, defaultValue)
 }
 
@@ -100,6 +122,8 @@ func (form *FormData) Bool(key string, target *bool, defaultValue bool) *FormDat
 //
 //	ctx.FormData().MandatoryBool("foo", &foo)
 func (form *FormData) MandatoryBool(key string, target *bool) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: "boolean", Required: true})
+
 	return form.mustMandatoryField(key, target)
 }
 
@@ -110,6 +134,8 @@ func (form *FormData) MandatoryBool(key string, target *bool) *FormData {
 //
 //	ctx.FormData().Int("foo", &foo, 2)
 func (form *FormData) Int(key string, target *int, defaultValue int) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: "integer", Default: defaultValue})
+
 	return form.mustValue(key, target, defaultValue)
 }
 
@@ -120,6 +146,8 @@ func (form *FormData) Int(key string, target *int, defaultValue int) *FormData {
 //
 //	ctx.FormData().MandatoryInt("foo", &foo)
 func (form *FormData) MandatoryInt(key string, target *int) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: "integer", Required: true})
+
 	return form.mustMandatoryField(key, target)
 }
 
@@ -130,6 +158,8 @@ func (form *FormData) MandatoryInt(key string, target *int) *FormData {
 //
 //	ctx.FormData().Float64("foo", &foo, 2.0)
 func (form *FormData) Float64(key string, target *float64, defaultValue float64) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: "number", Default: defaultValue})
+
 	return form.mustValue(key, target, defaultValue)
 }
 
@@ -140,6 +170,8 @@ func (form *FormData) Float64(key string, target *float64, defaultValue float64)
 //
 //	ctx.FormData().MandatoryFloat64("foo", &foo)
 func (form *FormData) MandatoryFloat64(key string, target *float64) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: "number", Required: true})
+
 	return form.mustMandatoryField(key, target)
 }
 
@@ -150,6 +182,8 @@ func (form *FormData) MandatoryFloat64(key string, target *float64) *FormData {
 //
 //	ctx.FormData().Duration("foo", &foo, time.Duration(2) * time.Second)
 func (form *FormData) Duration(key string, target *time.Duration, defaultValue time.Duration) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: durationFieldType, Default: defaultValue.String()})
+
 	return form.mustValue(key, target, defaultValue)
 }
 
@@ -161,6 +195,8 @@ func (form *FormData) Duration(key string, target *time.Duration, defaultValue t
 //
 //	ctx.FormData().MandatoryDuration("foo", &foo)
 func (form *FormData) MandatoryDuration(key string, target *time.Duration) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: durationFieldType, Required: true})
+
 	return form.mustMandatoryField(key, target)
 }
 
@@ -171,6 +207,8 @@ func (form *FormData) MandatoryDuration(key string, target *time.Duration) *Form
 //
 //	ctx.FormData().Inches("foo", &foo, 2.0)
 func (form *FormData) Inches(key string, target *float64, defaultValue float64) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: inchesFieldType, Default: defaultValue})
+
 	form.inches(key, target)
 	if *target == -math.MaxFloat64 {
 		*target = defaultValue
@@ -186,6 +224,8 @@ func (form *FormData) Inches(key string, target *float64, defaultValue float64)
 //
 //	ctx.FormData().MandatoryInches("foo", &foo)
 func (form *FormData) MandatoryInches(key string, target *float64) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: inchesFieldType, Required: true})
+
 	val, ok := form.values[key]
 	if !ok || val[0] == "" {
 		form.append(
@@ -268,6 +308,8 @@ func (form *FormData) inches(key string, target *float64) *FormData {
 //	  return nil
 //	})
 func (form *FormData) Custom(key string, assign func(value string) error) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: customFieldType})
+
 	var value string
 	form.mustValue(key, &value, "")
 
@@ -295,6 +337,8 @@ func (form *FormData) Custom(key string, assign func(value string) error) *FormD
 //	  return nil
 //	})
 func (form *FormData) MandatoryCustom(key string, assign func(value string) error) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: customFieldType, Required: true})
+
 	var value string
 	form.mustMandatoryField(key, &value)
 
@@ -318,6 +362,8 @@ func (form *FormData) MandatoryCustom(key string, assign func(value string) erro
 //
 //	ctx.FormData().Path("foo.txt", &path)
 func (form *FormData) Path(filename string, target *string) *FormData {
+	form.record(formField{Kind: fileField, Name: filename})
+
 	return form.path(filename, target)
 }
 
@@ -328,6 +374,8 @@ func (form *FormData) Path(filename string, target *string) *FormData {
 //
 //	ctx.FormData().MandatoryPath("foo.txt", &path)
 func (form *FormData) MandatoryPath(filename string, target *string) *FormData {
+	form.record(formField{Kind: fileField, Name: filename, Required: true})
+
 	return form.mandatoryPath(filename, target)
 }
 
@@ -337,6 +385,8 @@ func (form *FormData) MandatoryPath(filename string, target *string) *FormData {
 //
 //	ctx.FormData().Content("foo.txt", &content, "bar")
 func (form *FormData) Content(filename string, target *string, defaultValue string) *FormData {
+	form.record(formField{Kind: fileField, Name: filename})
+
 	var path string
 	form.path(filename, &path)
 
@@ -356,6 +406,8 @@ func (form *FormData) Content(filename string, target *string, defaultValue stri
 //
 //	ctx.FormData().MandatoryContent("foo.txt", &content)
 func (form *FormData) MandatoryContent(filename string, target *string) *FormData {
+	form.record(formField{Kind: fileField, Name: filename, Required: true})
+
 	var path string
 	form.mandatoryPath(filename, &path)
 
@@ -373,6 +425,8 @@ func (form *FormData) MandatoryContent(filename string, target *string) *FormDat
 //
 //	ctx.FormData().Paths([]string{".txt"}, &paths)
 func (form *FormData) Paths(extensions []string, target *[]string) *FormData {
+	form.record(formField{Kind: filesField, Extensions: extensions})
+
 	return form.paths(extensions, target)
 }
 
@@ -384,6 +438,8 @@ func (form *FormData) Paths(extensions []string, target *[]string) *FormData {
 //
 //	ctx.FormData().Embeds(&embeds)
 func (form *FormData) Embeds(target *[]string) *FormData {
+	form.record(formField{Kind: namedFilesField, Name: EmbedsFormField, Multiple: true})
+
 	if form.errors != nil {
 		return form
 	}
@@ -404,6 +460,8 @@ func (form *FormData) Embeds(target *[]string) *FormData {
 //
 //	ctx.FormData().EmbedsMetadata(&metadata)
 func (form *FormData) EmbedsMetadata(target *map[string]map[string]string) *FormData {
+	form.record(formField{Kind: valueField, Name: "embedsMetadata", Type: customFieldType})
+
 	if form.errors != nil {
 		return form
 	}
@@ -436,6 +494,8 @@ func (form *FormData) EmbedsMetadata(target *map[string]map[string]string) *Form
 //
 //	ctx.FormData().MandatoryPaths([]string{".txt"}, &paths)
 func (form *FormData) MandatoryPaths(extensions []string, target *[]string) *FormData {
+	form.record(formField{Kind: filesField, Extensions: extensions, Required: true})
+
 	form.paths(extensions, target)
 
 	if len(*target) > 0 {
@@ -453,6 +513,8 @@ func (form *FormData) MandatoryPaths(extensions []string, target *[]string) *For
 // used as a watermark source. Only a file uploaded with the "watermark"
 // field name will be included.
 func (form *FormData) Watermark(target *string) *FormData {
+	form.record(formField{Kind: namedFilesField, Name: WatermarkFormField})
+
 	if form.errors != nil {
 		return form
 	}
@@ -468,6 +530,8 @@ func (form *FormData) Watermark(target *string) *FormData {
 // used as a stamp source. Only a file uploaded with the "stamp"
 // field name will be included.
 func (form *FormData) Stamp(target *string) *FormData {
+	form.record(formField{Kind: namedFilesField, Name: StampFormField})
+
 	if form.errors != nil {
 		return form
 	}
@@ -483,6 +547,8 @@ func (form *FormData) Stamp(target *string) *FormData {
 // field name, in submission order. Unlike [FormData.Stamp], it keeps all of
 // them so a route can apply several stamps in a single request.
 func (form *FormData) Stamps(target *[]string) *FormData {
+	form.record(formField{Kind: namedFilesField, Name: StampFormField, Multiple: true})
+
 	if form.errors != nil {
 		return form
 	}
@@ -498,6 +564,8 @@ func (form *FormData) Stamps(target *[]string) *FormData {
 // "watermark" field name, in submission order. Unlike [FormData.Watermark], it
 // keeps all of them so a route can apply several watermarks in a single request.
 func (form *FormData) Watermarks(target *[]string) *FormData {
+	form.record(formField{Kind: namedFilesField, Name: WatermarkFormField, Multiple: true})
+
 	if form.errors != nil {
 		return form
 	}
@@ -513,6 +581,8 @@ func (form *FormData) Watermarks(target *[]string) *FormData {
 // repeated in the multipart body (e.g. multiple "stampSource") contributes one
 // entry per occurrence, which lets a route read parallel field arrays.
 func (form *FormData) Strings(key string, target *[]string) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: stringsFieldType})
+
 	if form.errors != nil {
 		return form
 	}
@@ -527,6 +597,8 @@ func (form *FormData) Strings(key string, target *[]string) *FormData {
 // FacturXXml binds the absolute path of the uploaded Factur-X CII invoice
 // XML. Only a file uploaded with the "facturxXml" field name is included.
 func (form *FormData) FacturXXml(target *string) *FormData {
+	form.record(formField{Kind: namedFilesField, Name: FacturXXmlFormField})
+
 	if form.errors != nil {
 		return form
 	}
@@ -538,14 +610,62 @@ func (form *FormData) FacturXXml(target *string) *FormData {
 	return form
 }
 
+// PreloadScripts binds every value of the "preloadScripts" form field, then
+// the content of every file uploaded with this field name, in submission
+// order.
+func (form *FormData) PreloadScripts(target *[]string) *FormData {
+	return form.texts(PreloadScriptsFormField, target)
+}
+
+// ExtraStylesheets binds every value of the "extraStylesheets" form field,
+// then the content of every file uploaded with this field name, in
+// submission order.
+func (form *FormData) ExtraStylesheets(target *[]string) *FormData {
+	return form.texts(ExtraStylesheetsFormField, target)
+}
+
+// texts binds every value of a form field, then the content of every file
+// uploaded with this field name.
+func (form *FormData) texts(key string, target *[]string) *FormData {
+	form.record(formField{Kind: valueField, Name: key, Type: stringsFieldType})
+
+	if form.errors != nil {
+		return form
+	}
+
+	if values, ok := form.values[key]; ok {
+		*target = append(*target, values...)
+	}
+
+	for _, path := range form.filesByField[key] {
+		filename, ok := form.diskToOriginal[path]
+		if !ok {
+			filename = filepath.Base(path)
+		}
+
+		var content string
+		form.readFile(path, filename, &content)
+		if form.errors != nil {
+			return form
+		}
+
+		*target = append(*target, content)
+	}
+
+	return form
+}
+
 // paths bind the absolute paths of form data files, according to a list of
 // file extensions, to a string slice variable.
-// embeds, watermark, stamp, and facturxXml files are excluded.
+// embeds, watermark, stamp, facturxXml, preloadScripts, and extraStylesheets
+// files are excluded.
 func (form *FormData) paths(extensions []string, target *[]string) *FormData {
 	embeds, ok := form.filesByField[EmbedsFormField]
 	watermarks, wmOk := form.filesByField[WatermarkFormField]
 	stamps, stOk := form.filesByField[StampFormField]
 	facturxXmls, fxOk := form.filesByField[FacturXXmlFormField]
+	preloadScripts, psOk := form.filesByField[PreloadScriptsFormField]
+	extraStylesheets, esOk := form.filesByField[ExtraStylesheetsFormField]
 
 	// Collect (originalFilename, diskPath) pairs so that we can sort by
 	// original filename rather than by UUID-based disk name.
@@ -573,6 +693,14 @@ func (form *FormData) paths(extensions []string, target *[]string) *FormData {
 			continue
 		}
 
+		if psOk && slices.Contains(preloadScripts, path) {
+			continue
+		}
+
+		if esOk && slices.Contains(extraStylesheets, path) {
+			continue
+		}
+
 		for _, ext := range extensions {
 			// See https://github.com/gotenberg/gotenberg/issues/228.
 			if strings.ToLower(filepath.Ext(filename)) == ext {
@@ -600,6 +728,16 @@ func (form *FormData) paths(extensions []string, target *[]string) *FormData {
 	return form
 }
 
+// record registers a form field read by a route, if the [FormData] is
+// describing one.
+func (form *FormData) record(field formField) {
+	if form.recorder == nil {
+		return
+	}
+
+	form.recorder.add(field)
+}
+
 // append adds an error to the list of errors.
 func (form *FormData) append(err error) {
 	form.errors = errors.Join(form.errors, err)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"reflect"
 	"testing"
 	"time"
@@ -1895,3 +1896,83 @@ func TestFormData_Watermarks(t *testing.T) {
 		t.Errorf("expected %+v, got %+v", want, got)
 	}
 }
+
+func TestFormData_PreloadScripts(t *testing.T) {
+	path := t.TempDir() + "/script.js"
+	err := os.WriteFile(path, []byte("window.foo = true;"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	form := &FormData{
+		values: map[string][]string{
+			PreloadScriptsFormField: {"Date.now = () => 0;"},
+		},
+		files: map[string]string{
+			"script.js": path,
+		},
+		filesByField: map[string][]string{
+			PreloadScriptsFormField: {path},
+		},
+	}
+
+	var got []string
+	form.PreloadScripts(&got)
+
+	if want := []string{"Date.now = () => 0;", "window.foo = true;"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	var paths []string
+	form.paths([]string{".js"}, &paths)
+
+	if len(paths) != 0 {
+		t.Errorf("expected no .js paths, got %+v", paths)
+	}
+
+	form = &FormData{
+		filesByField: map[string][]string{
+			PreloadScriptsFormField: {"/foo/script.js"},
+		},
+	}
+
+	got = nil
+	err = form.PreloadScripts(&got).Validate()
+	if err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestFormData_ExtraStylesheets(t *testing.T) {
+	path := t.TempDir() + "/style.css"
+	err := os.WriteFile(path, []byte("nav { display: none; }"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	form := &FormData{
+		values: map[string][]string{
+			ExtraStylesheetsFormField: {"body { margin: 0; }"},
+		},
+		files: map[string]string{
+			"style.css": path,
+		},
+		filesByField: map[string][]string{
+			ExtraStylesheetsFormField: {path},
+		},
+	}
+
+	var got []string
+	form.ExtraStylesheets(&got)
+
+	if want := []string{"body { margin: 0; }", "nav { display: none; }"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	var paths []string
+	form.paths([]string{".css"}, &paths)
+
+	if len(paths) != 0 {
+		t.Errorf("expected no .css paths, got %+v", paths)
+	}
+}
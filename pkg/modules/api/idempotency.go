@@ -0,0 +1,437 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// idempotencyKeyHeader is the request header identifying a request, so
+	// that its retries do not run the conversion again.
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotentReplayedHeader is the response header telling that the
+	// response is the stored response of a previous request with the same
+	// idempotency key.
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength is the maximum length of an idempotency key.
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotencyStore stores the responses of the requests with an idempotency
+// key, so that their duplicates receive the same response instead of running
+// the conversion again.
+//
+// A stored response is only replayed to a request with the same form values
+// and files; a request reusing the key otherwise is rejected.
+//
+// Response bodies live in a directory of its own, created within the
+// configured one, one file per key. Nothing else in the configured directory
+// is ever touched.
+type idempotencyStore struct {
+	dirPath string
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+	now     func() time.Time
+}
+
+// idempotencyEntry is a request with an idempotency key, either in progress
+// or completed.
+type idempotencyEntry struct {
+	// done is closed once the first request completes.
+	done chan struct{}
+
+	// stored tells if the response has been stored. If not, the first
+	// request failed, and its duplicates may run the conversion themselves.
+	stored    bool
+	status    int
+	header    http.Header
+	createdAt time.Time
+
+	// fingerprint identifies the form values and files of the first
+	// request.
+	fingerprint string
+}
+
+// idempotencyRequest is the state of a request with an idempotency key,
+// shared between [idempotencyMiddleware] and
+// [idempotencyFingerprintMiddleware].
+type idempotencyRequest struct {
+	key   string
+	entry *idempotencyEntry
+
+	// replay tells if the request is a duplicate of a stored response.
+	replay bool
+}
+
+// idempotencyFingerprintData is the data hashed to compute the fingerprint
+// of a request.
+type idempotencyFingerprintData struct {
+	Values       map[string][]string `json:"values"`
+	Files        map[string]string   `json:"files"`
+	FilesByField map[string][]string `json:"filesByField"`
+}
+
+// newIdempotencyStore returns an [idempotencyStore] using a new directory
+// within the given one.
+func newIdempotencyStore(parentDirPath string, ttl time.Duration) (*idempotencyStore, error) {
+	err := os.MkdirAll(parentDirPath, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create idempotency parent directory: %w", err)
+	}
+
+	dirPath, err := os.MkdirTemp(parentDirPath, "gotenberg-idempotency-")
+	if err != nil {
+		return nil, fmt.Errorf("create idempotency directory: %w", err)
+	}
+
+	return &idempotencyStore{
+		dirPath: dirPath,
+		ttl:     ttl,
+		entries: make(map[string]*idempotencyEntry),
+		now:     time.Now,
+	}, nil
+}
+
+// close removes the directory of the store.
+func (store *idempotencyStore) close() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.entries = make(map[string]*idempotencyEntry)
+
+	return os.RemoveAll(store.dirPath)
+}
+
+// key scopes an idempotency key to the client identity and the route, so
+// that two clients, or two routes, never share a response.
+func (store *idempotencyStore) key(c echo.Context, idempotencyKey string) string {
+	identity := CallerIdentity(c)
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s %s\n%s", identity, c.Request().Method, c.Request().URL.Path, idempotencyKey)))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// acquire returns the entry of the given key. It creates the entry if there
+// is none, in which case the caller owns it and must either complete or
+// release it.
+func (store *idempotencyStore) acquire(key string) (*idempotencyEntry, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.evict()
+
+	entry, ok := store.entries[key]
+	if ok {
+		return entry, false
+	}
+
+	entry = &idempotencyEntry{done: make(chan struct{})}
+	store.entries[key] = entry
+
+	return entry, true
+}
+
+// complete stores the response of an owned entry, whose body is the file at
+// bodyPath, and wakes up the duplicates.
+func (store *idempotencyStore) complete(key string, entry *idempotencyEntry, status int, header http.Header, bodyPath string) error {
+	err := os.Rename(bodyPath, filepath.Join(store.dirPath, key))
+	if err != nil {
+		return errors.Join(fmt.Errorf("move response body: %w", err), store.release(key, entry, bodyPath))
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	entry.stored = true
+	entry.status = status
+	entry.header = header
+	entry.createdAt = store.now()
+	close(entry.done)
+
+	return nil
+}
+
+// release removes an owned entry without storing its response, and wakes up
+// the duplicates.
+func (store *idempotencyStore) release(key string, entry *idempotencyEntry, bodyPath string) error {
+	store.mu.Lock()
+	if store.entries[key] == entry {
+		delete(store.entries, key)
+	}
+	close(entry.done)
+	store.mu.Unlock()
+
+	err := os.Remove(bodyPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove response body: %w", err)
+	}
+
+	return nil
+}
+
+// evict removes the stored entries which have outlived the TTL. The caller
+// must hold the lock.
+func (store *idempotencyStore) evict() {
+	for key, entry := range store.entries {
+		if !entry.stored || store.now().Sub(entry.createdAt) <= store.ttl {
+			continue
+		}
+
+		delete(store.entries, key)
+
+		// Best effort: a leftover file is overwritten by a later request
+		// with the same key, and removed on stop.
+		_ = os.Remove(filepath.Join(store.dirPath, key))
+	}
+}
+
+// idempotencyRecorder forwards a response to the client while copying its
+// status, headers and body.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	body     io.Writer
+	status   int
+	header   http.Header
+	writeErr error
+}
+
+func (r *idempotencyRecorder) WriteHeader(code int) {
+	r.status = code
+	r.header = r.ResponseWriter.Header().Clone()
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	n, err := r.ResponseWriter.Write(b)
+	if err != nil {
+		// The client did not receive the whole response.
+		r.writeErr = errors.Join(r.writeErr, err)
+	}
+
+	if n > 0 && r.writeErr == nil {
+		_, err = r.body.Write(b[:n])
+		r.writeErr = errors.Join(r.writeErr, err)
+	}
+
+	return n, err
+}
+
+func (r *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// idempotencyMiddleware, middleware for "multipart/form-data" requests,
+// honors the "Idempotency-Key" header. The first request with a given key
+// runs; its concurrent duplicates wait for it, and its later duplicates,
+// within the retention window, receive its stored response, e.g., the output
+// file, or the acknowledgement of an asynchronous request. Only successful
+// responses are stored: after a failure, a duplicate runs the conversion
+// again.
+//
+// A duplicate goes on until [idempotencyFingerprintMiddleware], which
+// compares its form values and files with those of the first request before
+// replaying the stored response.
+func idempotencyMiddleware(store *idempotencyStore, timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			idempotencyKey := c.Request().Header.Get(idempotencyKeyHeader)
+			if idempotencyKey == "" {
+				return next(c)
+			}
+
+			if len(idempotencyKey) > maxIdempotencyKeyLength {
+				return WrapError(
+					fmt.Errorf("idempotency key of %d characters", len(idempotencyKey)),
+					NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' header value: must not exceed %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength)),
+				)
+			}
+
+			key := store.key(c, idempotencyKey)
+
+			wait := time.NewTimer(timeout)
+			defer wait.Stop()
+
+			for {
+				entry, owner := store.acquire(key)
+				if owner {
+					return store.run(c, key, entry, next)
+				}
+
+				select {
+				case <-entry.done:
+				case <-c.Request().Context().Done():
+					return fmt.Errorf("wait for request with the same idempotency key: %w", c.Request().Context().Err())
+				case <-wait.C:
+					return WrapError(
+						errors.New("request with the same idempotency key still in progress"),
+						NewSentinelHttpError(http.StatusConflict, fmt.Sprintf("A request with the same '%s' header value is still in progress", idempotencyKeyHeader)),
+					)
+				}
+
+				if entry.stored {
+					c.Set("idempotency", &idempotencyRequest{key: key, entry: entry, replay: true})
+
+					return next(c)
+				}
+
+				// The first request failed; let's try again, possibly as the
+				// new owner.
+			}
+		}
+	}
+}
+
+// run runs the request of an owned entry and stores its response if
+// successful.
+func (store *idempotencyStore) run(c echo.Context, key string, entry *idempotencyEntry, next echo.HandlerFunc) error {
+	logger, _ := c.Get("logger").(*slog.Logger)
+
+	body, err := os.CreateTemp(store.dirPath, ".tmp-")
+	if err != nil {
+		return errors.Join(fmt.Errorf("create response body file: %w", err), store.release(key, entry, ""))
+	}
+
+	recorder := &idempotencyRecorder{ResponseWriter: c.Response().Writer, body: body}
+	c.Response().Writer = recorder
+	c.Set("idempotency", &idempotencyRequest{key: key, entry: entry})
+
+	err = next(c)
+
+	c.Response().Writer = recorder.ResponseWriter
+	closeErr := body.Close()
+
+	// Partial content depends on the "Range" header of the request, so it
+	// is not a response to replay.
+	stored := err == nil && closeErr == nil && recorder.writeErr == nil &&
+		recorder.status >= http.StatusOK && recorder.status < http.StatusMultipleChoices &&
+		recorder.status != http.StatusPartialContent
+
+	if !stored {
+		releaseErr := store.release(key, entry, body.Name())
+		if releaseErr != nil && logger != nil {
+			logger.ErrorContext(c.Request().Context(), fmt.Sprintf("release idempotency key: %s", releaseErr))
+		}
+
+		return err
+	}
+
+	// These headers belong to the request, not to the response.
+	recorder.header.Del("Date")
+	if correlationIdHeader, ok := c.Get("correlationIdHeader").(string); ok {
+		recorder.header.Del(correlationIdHeader)
+	}
+
+	err = store.complete(key, entry, recorder.status, recorder.header, body.Name())
+	if err != nil && logger != nil {
+		logger.ErrorContext(c.Request().Context(), fmt.Sprintf("store response for idempotency key: %s", err))
+	}
+
+	return nil
+}
+
+// idempotencyFingerprintMiddleware, middleware for "multipart/form-data"
+// requests, runs after the context middleware. It records the fingerprint of
+// a request with an idempotency key, or, for a duplicate, replays the stored
+// response if the fingerprints match.
+func idempotencyFingerprintMiddleware(store *idempotencyStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req, ok := c.Get("idempotency").(*idempotencyRequest)
+			if !ok {
+				return next(c)
+			}
+
+			ctx := c.Get("context").(*Context)
+			fingerprint, err := requestFingerprint(ctx)
+			if err != nil {
+				return fmt.Errorf("compute request fingerprint: %w", err)
+			}
+
+			if !req.replay {
+				// Read by the duplicates once the entry is done.
+				req.entry.fingerprint = fingerprint
+
+				return next(c)
+			}
+
+			if fingerprint != req.entry.fingerprint {
+				return WrapError(
+					errors.New("idempotency key reused with different form values or files"),
+					NewSentinelHttpError(http.StatusUnprocessableEntity, fmt.Sprintf("The '%s' header value has already been used with different form values or files", idempotencyKeyHeader)),
+				)
+			}
+
+			err = store.replay(c, req.key, req.entry)
+			if err != nil {
+				return err
+			}
+
+			return ErrNoOutputFile
+		}
+	}
+}
+
+// requestFingerprint returns the hex-encoded SHA-256 checksum of the form
+// values and files of the context.
+func requestFingerprint(ctx *Context) (string, error) {
+	files, filesByField, err := fileSums(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(idempotencyFingerprintData{
+		Values:       ctx.values,
+		Files:        files,
+		FilesByField: filesByField,
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal fingerprint data: %w", err)
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// replay sends the stored response of an entry.
+func (store *idempotencyStore) replay(c echo.Context, key string, entry *idempotencyEntry) error {
+	f, err := os.Open(filepath.Join(store.dirPath, key))
+	if err != nil {
+		return fmt.Errorf("open stored response body: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if logger, ok := c.Get("logger").(*slog.Logger); ok {
+		logger.DebugContext(c.Request().Context(), "replay stored response for idempotency key")
+	}
+
+	for name, values := range entry.header {
+		c.Response().Header()[name] = values
+	}
+	c.Response().Header().Set(idempotentReplayedHeader, "true")
+
+	return c.Stream(entry.status, entry.header.Get(echo.HeaderContentType), f)
+}
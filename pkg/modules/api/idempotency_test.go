@@ -0,0 +1,255 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestNewIdempotencyStore(t *testing.T) {
+	parentDirPath := t.TempDir()
+
+	otherPath := filepath.Join(parentDirPath, "foo.txt")
+	err := os.WriteFile(otherPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	store, err := newIdempotencyStore(parentDirPath, time.Duration(1)*time.Hour)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if filepath.Dir(store.dirPath) != parentDirPath {
+		t.Errorf("expected the store directory within '%s' but got '%s'", parentDirPath, store.dirPath)
+	}
+
+	err = store.close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(store.dirPath)
+	if !os.IsNotExist(err) {
+		t.Errorf("expected the store directory to be removed, got: %v", err)
+	}
+
+	_, err = os.Stat(otherPath)
+	if err != nil {
+		t.Errorf("expected the other files of the parent directory to remain, got: %v", err)
+	}
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	store, err := newIdempotencyStore(t.TempDir(), time.Duration(1)*time.Hour)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	var runs atomic.Int64
+	var fail atomic.Bool
+	release := make(chan struct{})
+	close(release)
+	var releaseMu sync.Mutex
+
+	e := echo.New()
+	e.HTTPErrorHandler = httpErrorHandler()
+	e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("logger", slog.New(slog.DiscardHandler))
+			c.Set("correlationIdHeader", "Gotenberg-Trace")
+			if identity := c.Request().Header.Get("X-Identity"); identity != "" {
+				c.Set("identity", identity)
+			}
+			return next(c)
+		}
+	})
+	handler := func(c echo.Context) error {
+		n := runs.Add(1)
+
+		releaseMu.Lock()
+		wait := release
+		releaseMu.Unlock()
+		<-wait
+
+		if fail.Load() {
+			return errors.New("conversion failed")
+		}
+
+		c.Response().Header().Set("Gotenberg-Trace", c.Request().Header.Get("Gotenberg-Trace"))
+		c.Response().Header().Set("X-Run", string(rune('0'+n)))
+		return c.Blob(http.StatusOK, "application/pdf", []byte("%PDF-1.7 foo"))
+	}
+	// Stands for the context middleware.
+	contextMiddleware := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("context", &Context{values: map[string][]string{"foo": {c.Request().Header.Get("X-Foo")}}})
+
+			err := next(c)
+			if errors.Is(err, ErrNoOutputFile) {
+				return nil
+			}
+			return err
+		}
+	}
+	middlewares := []echo.MiddlewareFunc{
+		idempotencyMiddleware(store, time.Duration(200)*time.Millisecond),
+		contextMiddleware,
+		idempotencyFingerprintMiddleware(store),
+	}
+	e.POST("/forms/foo", handler, middlewares...)
+	e.POST("/forms/bar", handler, middlewares...)
+
+	doWithFoo := func(path, key, identity, foo string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("X-Foo", foo)
+		if key != "" {
+			req.Header.Set(idempotencyKeyHeader, key)
+		}
+		if identity != "" {
+			req.Header.Set("X-Identity", identity)
+		}
+		req.Header.Set("Gotenberg-Trace", "trace")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	do := func(path, key, identity string) *httptest.ResponseRecorder {
+		return doWithFoo(path, key, identity, "")
+	}
+
+	expectRuns := func(expect int64) {
+		t.Helper()
+
+		if runs.Load() != expect {
+			t.Errorf("expected %d runs, got %d", expect, runs.Load())
+		}
+	}
+
+	// Without a key, every request runs.
+	do("/forms/foo", "", "")
+	do("/forms/foo", "", "")
+	expectRuns(2)
+
+	// A duplicate receives the stored response.
+	first := do("/forms/foo", "a", "")
+	replay := do("/forms/foo", "a", "")
+	expectRuns(3)
+
+	if replay.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, replay.Code)
+	}
+	if replay.Body.String() != first.Body.String() {
+		t.Errorf("expected body '%s', got '%s'", first.Body.String(), replay.Body.String())
+	}
+	if replay.Header().Get("X-Run") != "3" {
+		t.Errorf("expected header 'X-Run' to be '3', got '%s'", replay.Header().Get("X-Run"))
+	}
+	if replay.Header().Get(echo.HeaderContentType) != "application/pdf" {
+		t.Errorf("expected content type 'application/pdf', got '%s'", replay.Header().Get(echo.HeaderContentType))
+	}
+	if replay.Header().Get(idempotentReplayedHeader) != "true" {
+		t.Errorf("expected header '%s' to be 'true', got '%s'", idempotentReplayedHeader, replay.Header().Get(idempotentReplayedHeader))
+	}
+	if first.Header().Get(idempotentReplayedHeader) != "" {
+		t.Errorf("expected no header '%s' on the first response", idempotentReplayedHeader)
+	}
+	if replay.Header().Get("Gotenberg-Trace") != "" {
+		t.Errorf("expected no correlation ID header on the replayed response, got '%s'", replay.Header().Get("Gotenberg-Trace"))
+	}
+
+	// A duplicate must have the same form values and files.
+	rec := doWithFoo("/forms/foo", "a", "", "bar")
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, rec.Code)
+	}
+	expectRuns(3)
+
+	// Keys are scoped to the route and the identity.
+	do("/forms/bar", "a", "")
+	do("/forms/foo", "a", "alice")
+	do("/forms/foo", "a", "alice")
+	expectRuns(5)
+
+	// A failure is not stored.
+	fail.Store(true)
+	rec = do("/forms/foo", "b", "")
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	fail.Store(false)
+	do("/forms/foo", "b", "")
+	do("/forms/foo", "b", "")
+	expectRuns(7)
+
+	// Concurrent duplicates wait for the first request.
+	releaseMu.Lock()
+	release = make(chan struct{})
+	releaseMu.Unlock()
+
+	var wg sync.WaitGroup
+	codes := make(chan int, 4)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- do("/forms/foo", "c", "").Code
+		}()
+	}
+	time.Sleep(time.Duration(50) * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(codes)
+
+	for code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, code)
+		}
+	}
+	expectRuns(8)
+
+	// Duplicates give up while the first request is still in progress.
+	releaseMu.Lock()
+	release = make(chan struct{})
+	releaseMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		do("/forms/foo", "d", "")
+	}()
+	time.Sleep(time.Duration(50) * time.Millisecond)
+	rec = do("/forms/foo", "d", "")
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, rec.Code)
+	}
+	close(release)
+	<-done
+	expectRuns(9)
+
+	// A stored response expires.
+	now = now.Add(time.Duration(2) * time.Hour)
+	do("/forms/foo", "a", "")
+	expectRuns(10)
+
+	// A key must not be too long.
+	rec = do("/forms/foo", strings.Repeat("a", maxIdempotencyKeyLength+1), "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	expectRuns(10)
+}
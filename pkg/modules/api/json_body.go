@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// jsonFilesKey is the key of a JSON request body listing the files. Every
+// other key is an option, as a multipart/form-data field would be.
+const jsonFilesKey = "files"
+
+// jsonFile is a file given in a JSON request body. Either Content or Url must
+// be set.
+type jsonFile struct {
+	// Filename is the name of the file, extension included. Required with
+	// Content.
+	Filename string `json:"filename"`
+
+	// Content is the base64 encoded content of the file.
+	Content string `json:"content"`
+
+	// Url is the URL to download the file from, like an entry of the
+	// "downloadFrom" form field.
+	Url string `json:"url"`
+
+	// ExtraHttpHeaders are the HTTP headers to send alongside Url.
+	ExtraHttpHeaders map[string]string `json:"extraHttpHeaders"`
+
+	// Field is the multipart/form-data field the file would have been
+	// uploaded with, i.e., "files" (default), "embeds", "watermark",
+	// "stamp" or "facturxXml".
+	Field string `json:"field"`
+}
+
+// inlineFile is a decoded [jsonFile] with a base64 content.
+type inlineFile struct {
+	field    string
+	filename string
+	content  []byte
+}
+
+// JsonFormValues converts a JSON object to form values, following the same
+// rules as a JSON request body. See also [Context.WithFormValues].
+func JsonFormValues(b []byte) (map[string][]string, error) {
+	var body map[string]json.RawMessage
+
+	err := json.Unmarshal(b, &body)
+	if err != nil {
+		return nil, WrapError(
+			fmt.Errorf("unmarshal JSON form values: %w", err),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Malformed JSON object: %s", err)),
+		)
+	}
+
+	return jsonValues(body)
+}
+
+// isJsonRequest tells if the request has an "application/json" body.
+func isJsonRequest(req *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json"
+}
+
+// parseJsonBody parses a JSON request body and returns the equivalent of
+// multipart/form-data values (see [jsonValues]), the inline files, and the
+// files to download.
+func parseJsonBody(b []byte) (map[string][]string, []inlineFile, []downloadFrom, error) {
+	var body map[string]json.RawMessage
+
+	err := json.Unmarshal(b, &body)
+	if err != nil {
+		return nil, nil, nil, WrapError(
+			fmt.Errorf("unmarshal JSON body: %w", err),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Malformed JSON body: %s", err)),
+		)
+	}
+
+	values, err := jsonValues(body)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	raw, ok := body[jsonFilesKey]
+	if !ok {
+		return values, nil, nil, nil
+	}
+
+	var files []jsonFile
+	err = json.Unmarshal(raw, &files)
+	if err != nil {
+		return nil, nil, nil, WrapError(
+			fmt.Errorf("unmarshal JSON body files: %w", err),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'files' JSON body value: %s", err)),
+		)
+	}
+
+	var inlines []inlineFile
+	var dls []downloadFrom
+	for i, file := range files {
+		field := file.Field
+		if field == "" {
+			field = jsonFilesKey
+		}
+
+		switch field {
+		case jsonFilesKey, EmbedsFormField, WatermarkFormField, StampFormField, FacturXXmlFormField, PreloadScriptsFormField, ExtraStylesheetsFormField:
+		default:
+			return nil, nil, nil, WrapError(
+				fmt.Errorf("unsupported field '%s' for JSON body file %d", field, i),
+				NewSentinelHttpError(
+					http.StatusBadRequest,
+					fmt.Sprintf("Invalid 'files' JSON body entry %d: field must be '%s', '%s', '%s', '%s', '%s', '%s' or '%s', but got '%s'", i, jsonFilesKey, EmbedsFormField, WatermarkFormField, StampFormField, FacturXXmlFormField, PreloadScriptsFormField, ExtraStylesheetsFormField, field),
+				),
+			)
+		}
+
+		hasUrl := strings.TrimSpace(file.Url) != ""
+		if hasUrl == (file.Content != "") {
+			return nil, nil, nil, WrapError(
+				fmt.Errorf("JSON body file %d must have either a content or a URL", i),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'files' JSON body entry %d: set either 'content' or 'url'", i)),
+			)
+		}
+
+		if hasUrl {
+			dl := downloadFrom{
+				Url:              file.Url,
+				ExtraHttpHeaders: file.ExtraHttpHeaders,
+			}
+			switch field {
+			case EmbedsFormField:
+				dl.Field = "embedded"
+			case jsonFilesKey:
+			default:
+				dl.Field = field
+			}
+			dls = append(dls, dl)
+			continue
+		}
+
+		if strings.TrimSpace(file.Filename) == "" {
+			return nil, nil, nil, WrapError(
+				fmt.Errorf("empty filename for JSON body file %d", i),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'files' JSON body entry %d: 'filename' must be set with 'content'", i)),
+			)
+		}
+
+		content, err := base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			return nil, nil, nil, WrapError(
+				fmt.Errorf("decode base64 content of JSON body file %d: %w", i, err),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'files' JSON body entry %d: 'content' is not valid base64", i)),
+			)
+		}
+
+		inlines = append(inlines, inlineFile{
+			field:    field,
+			filename: file.Filename,
+			content:  content,
+		})
+	}
+
+	return values, inlines, dls, nil
+}
+
+// jsonValues converts the keys of a JSON object to form values. Strings are
+// used as is, while numbers and booleans keep their JSON representation
+// (e.g., 8.5 or true). Objects and arrays are passed as JSON text, like the
+// structured form fields expect (e.g., "metadata" or "cookies"). Null values
+// are ignored, as is the "files" key.
+func jsonValues(body map[string]json.RawMessage) (map[string][]string, error) {
+	values := make(map[string][]string, len(body))
+	for key, raw := range body {
+		if key == jsonFilesKey {
+			continue
+		}
+
+		raw = bytes.TrimSpace(raw)
+
+		switch {
+		case bytes.Equal(raw, []byte("null")):
+			continue
+		case len(raw) > 0 && raw[0] == '"':
+			var value string
+			err := json.Unmarshal(raw, &value)
+			if err != nil {
+				return nil, fmt.Errorf("unmarshal JSON key '%s': %w", key, err)
+			}
+			values[key] = []string{value}
+		default:
+			values[key] = []string{string(raw)}
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestParseJsonBody(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		body            string
+		expectValues    map[string][]string
+		expectInlines   []inlineFile
+		expectDownloads []downloadFrom
+		expectErr       bool
+		expectHttpErr   bool
+	}{
+		{
+			scenario:      "malformed JSON",
+			body:          `{"foo":`,
+			expectErr:     true,
+			expectHttpErr: true,
+		},
+		{
+			scenario:      "not a JSON object",
+			body:          `["foo"]`,
+			expectErr:     true,
+			expectHttpErr: true,
+		},
+		{
+			scenario: "typed values",
+			body:     `{"url":"https://example.com","landscape":true,"scale":1.5,"metadata":{"Author":"Foo"},"cookies":[{"name":"foo"}],"nope":null}`,
+			expectValues: map[string][]string{
+				"url":       {"https://example.com"},
+				"landscape": {"true"},
+				"scale":     {"1.5"},
+				"metadata":  {`{"Author":"Foo"}`},
+				"cookies":   {`[{"name":"foo"}]`},
+			},
+		},
+		{
+			scenario:     "inline and remote files",
+			body:         `{"files":[{"filename":"index.html","content":"PGgxPkZvbzwvaDE+"},{"filename":"logo.png","content":"Zm9v","field":"watermark"},{"url":"https://example.com/foo.pdf","extraHttpHeaders":{"X-Foo":"bar"}},{"url":"https://example.com/bar.xml","field":"embeds"}]}`,
+			expectValues: map[string][]string{},
+			expectInlines: []inlineFile{
+				{field: "files", filename: "index.html", content: []byte("<h1>Foo</h1>")},
+				{field: WatermarkFormField, filename: "logo.png", content: []byte("foo")},
+			},
+			expectDownloads: []downloadFrom{
+				{Url: "https://example.com/foo.pdf", ExtraHttpHeaders: map[string]string{"X-Foo": "bar"}},
+				{Url: "https://example.com/bar.xml", Field: "embedded"},
+			},
+		},
+		{
+			scenario:      "invalid files value",
+			body:          `{"files":"foo"}`,
+			expectErr:     true,
+			expectHttpErr: true,
+		},
+		{
+			scenario:      "file without content nor URL",
+			body:          `{"files":[{"filename":"foo.pdf"}]}`,
+			expectErr:     true,
+			expectHttpErr: true,
+		},
+		{
+			scenario:      "file with both content and URL",
+			body:          `{"files":[{"filename":"foo.pdf","content":"Zm9v","url":"https://example.com/foo.pdf"}]}`,
+			expectErr:     true,
+			expectHttpErr: true,
+		},
+		{
+			scenario:      "file content without filename",
+			body:          `{"files":[{"content":"Zm9v"}]}`,
+			expectErr:     true,
+			expectHttpErr: true,
+		},
+		{
+			scenario:      "invalid base64 content",
+			body:          `{"files":[{"filename":"foo.pdf","content":"%%%"}]}`,
+			expectErr:     true,
+			expectHttpErr: true,
+		},
+		{
+			scenario:      "unsupported field",
+			body:          `{"files":[{"filename":"foo.pdf","content":"Zm9v","field":"foo"}]}`,
+			expectErr:     true,
+			expectHttpErr: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			values, inlines, dls, err := parseJsonBody([]byte(tc.body))
+
+			if tc.expectErr && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectErr && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr HttpError
+			isHttpErr := errors.As(err, &httpErr)
+			if tc.expectHttpErr && !isHttpErr {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if tc.expectErr {
+				return
+			}
+
+			if !reflect.DeepEqual(values, tc.expectValues) {
+				t.Errorf("expected values %+v, but got %+v", tc.expectValues, values)
+			}
+
+			if !reflect.DeepEqual(inlines, tc.expectInlines) {
+				t.Errorf("expected inline files %+v, but got %+v", tc.expectInlines, inlines)
+			}
+
+			if !reflect.DeepEqual(dls, tc.expectDownloads) {
+				t.Errorf("expected downloads %+v, but got %+v", tc.expectDownloads, dls)
+			}
+		})
+	}
+}
+
+func TestNewContext_JsonBody(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		body            string
+		bodyLimit       int64
+		downloadFromCfg downloadFromConfig
+		expectStatus    int
+	}{
+		{
+			scenario:        "body limit exceeded",
+			body:            `{"files":[{"filename":"index.html","content":"PGgxPkZvbzwvaDE+"}]}`,
+			bodyLimit:       10,
+			downloadFromCfg: downloadFromConfig{disable: true},
+			expectStatus:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario:        "download from disabled",
+			body:            `{"files":[{"url":"https://example.com/foo.pdf"}]}`,
+			downloadFromCfg: downloadFromConfig{disable: true},
+			expectStatus:    http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/forms/foo", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			fs := gotenberg.NewFileSystem(new(gotenberg.OsMkdirAll))
+			_, cancel, err := newContext(c, slog.New(slog.DiscardHandler), fs, 10*time.Second, tc.bodyLimit, tc.downloadFromCfg, nil)
+			defer cancel()
+
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			status, _ := ParseError(err)
+			if status != tc.expectStatus {
+				t.Errorf("expected status %d, but got %d: %v", tc.expectStatus, status, err)
+			}
+		})
+	}
+}
+
+func TestNewContext_JsonBodyFormData(t *testing.T) {
+	body := `{"landscape":true,"files":[{"filename":"index.html","content":"PGgxPkZvbzwvaDE+"},{"filename":"logo.pdf","content":"Zm9v","field":"watermark"}]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/forms/foo", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, "application/json; charset=UTF-8")
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	fs := gotenberg.NewFileSystem(new(gotenberg.OsMkdirAll))
+	ctx, cancel, err := newContext(c, slog.New(slog.DiscardHandler), fs, 10*time.Second, 0, downloadFromConfig{disable: true}, nil)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	defer cancel()
+
+	var landscape bool
+	var indexPath, watermarkPath string
+	err = ctx.FormData().
+		Bool("landscape", &landscape, false).
+		MandatoryPath("index.html", &indexPath).
+		Watermark(&watermarkPath).
+		Validate()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !landscape {
+		t.Error("expected landscape to be true")
+	}
+
+	b, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatalf("read index.html: %v", err)
+	}
+	if string(b) != "<h1>Foo</h1>" {
+		t.Errorf("expected index.html content '<h1>Foo</h1>', but got '%s'", string(b))
+	}
+
+	if ctx.OriginalFilename(watermarkPath) != "logo.pdf" {
+		t.Errorf("expected watermark 'logo.pdf', but got '%s'", ctx.OriginalFilename(watermarkPath))
+	}
+}
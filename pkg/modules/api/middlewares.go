@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/dlclark/regexp2"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -105,7 +108,8 @@ func requestCanceled(c echo.Context, err error) bool {
 }
 
 // httpErrorHandler is the centralized HTTP error handler. It parses the error,
-// returns a response as "text/plain; charset=UTF-8".
+// returns a response as "text/plain; charset=UTF-8", or as an [ErrorBody] if
+// the error has a screenshot.
 func httpErrorHandler() echo.HTTPErrorHandler {
 	return func(err error, c echo.Context) {
 		logger := c.Get("logger").(*slog.Logger)
@@ -118,10 +122,14 @@ func httpErrorHandler() echo.HTTPErrorHandler {
 			return
 		}
 
-		status, message := ParseError(err)
-		c.Response().Header().Add(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
+		body := NewErrorBody(err)
+		if body.Screenshot != nil {
+			err = c.JSON(body.Status, body)
+		} else {
+			c.Response().Header().Add(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
+			err = c.String(body.Status, body.Message)
+		}
 
-		err = c.String(status, message)
 		if err != nil {
 			logger.ErrorContext(c.Request().Context(), fmt.Sprintf("send error response: %s", err.Error()))
 		}
@@ -198,7 +206,10 @@ func outputFilenameMiddleware() echo.MiddlewareFunc {
 //
 //	correlationIdHeader := c.Get("correlationIdHeader").(string)
 //	correlationId := c.Get("correlationId").(string)
-func telemetryMiddleware(logger *slog.Logger, serverName, correlationIdHeader string, disableTelemetryForPaths []string) echo.MiddlewareFunc {
+//
+// If the audit log is not nil, it also writes an audit record of each
+// conversion.
+func telemetryMiddleware(logger *slog.Logger, serverName, correlationIdHeader string, disableTelemetryForPaths []string, audit *auditLog) echo.MiddlewareFunc {
 	meter := gotenberg.Meter()
 	semconvSrv := semconvutil.NewHTTPServer(meter)
 
@@ -269,6 +280,16 @@ func telemetryMiddleware(logger *slog.Logger, serverName, correlationIdHeader st
 
 			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(c.Response().Header()))
 
+			// The context middleware hashes the input and output files, and
+			// the engines record themselves in the request context.
+			var files *auditFiles
+			var engines *gotenberg.UsedEngines
+			if audit != nil {
+				files = new(auditFiles)
+				c.Set("auditFiles", files)
+				ctx, engines = gotenberg.ContextWithUsedEngines(ctx)
+			}
+
 			c.Response().Header().Set(correlationIdHeader, correlationId)
 			c.SetRequest(c.Request().WithContext(ctx))
 
@@ -284,6 +305,12 @@ func telemetryMiddleware(logger *slog.Logger, serverName, correlationIdHeader st
 
 			c.Set("logger", appLogger.With(slog.String("logger", loggerName)))
 
+			if files != nil {
+				files.audit = audit
+				files.logger = appLogger
+				files.engines = engines
+			}
+
 			// Call the next middleware in the chain.
 			err := next(c)
 			finishTime := time.Now()
@@ -325,6 +352,14 @@ func telemetryMiddleware(logger *slog.Logger, serverName, correlationIdHeader st
 				With(slog.Int64("bytes_in", c.Request().ContentLength)).
 				With(slog.Int64("bytes_out", c.Response().Size))
 
+			if identity, ok := c.Get("identity").(string); ok {
+				accessLogger = accessLogger.With(slog.String("identity", identity))
+			}
+
+			if clientCertSubject, ok := c.Get("clientCertSubject").(string); ok {
+				accessLogger = accessLogger.With(slog.String("client_cert_subject", clientCertSubject))
+			}
+
 			switch {
 			case err == nil:
 				accessLogger.InfoContext(ctx, "request handled")
@@ -336,6 +371,10 @@ func telemetryMiddleware(logger *slog.Logger, serverName, correlationIdHeader st
 				accessLogger.ErrorContext(ctx, err.Error())
 			}
 
+			if audit != nil {
+				writeAuditRecord(c, audit, appLogger, files, engines.Names(), status, err)
+			}
+
 			additionalAttributes := []attribute.KeyValue{
 				semconvSrv.Route(routePath),
 			}
@@ -359,11 +398,17 @@ func telemetryMiddleware(logger *slog.Logger, serverName, correlationIdHeader st
 	}
 }
 
-// basicAuthMiddleware manages basic authentication.
-func basicAuthMiddleware(username, password string) echo.MiddlewareFunc {
+// basicAuthMiddleware manages basic authentication. The username, i.e., the
+// caller identity, is set in the [echo.Context] under "identity". The
+// credentials are loaded on each request, as they may change on reload.
+//
+//	identity := c.Get("identity").(string)
+func basicAuthMiddleware(settings *atomic.Pointer[reloadableSettings]) echo.MiddlewareFunc {
 	return middleware.BasicAuth(func(u string, p string, e echo.Context) (bool, error) {
-		if subtle.ConstantTimeCompare([]byte(u), []byte(username)) == 1 &&
-			subtle.ConstantTimeCompare([]byte(p), []byte(password)) == 1 {
+		current := settings.Load()
+		if subtle.ConstantTimeCompare([]byte(u), []byte(current.basicAuthUsername)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(p), []byte(current.basicAuthPassword)) == 1 {
+			e.Set("identity", u)
 			return true, nil
 		}
 		return false, nil
@@ -404,7 +449,10 @@ func (a *Api) buildOidcVerifier() (*oidc.IDTokenVerifier, error) {
 // the OIDC verifier, which checks the signature against the provider's rotating
 // JWKS and the issuer, audience and expiry claims. It answers 401 for a missing
 // or invalid token, logging the underlying reason at debug level without leaking
-// it to the client.
+// it to the client. The token subject, i.e., the caller identity, is set in the
+// [echo.Context] under "identity".
+//
+//	identity := c.Get("identity").(string)
 func oidcAuthMiddleware(verifier *oidc.IDTokenVerifier) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -413,7 +461,7 @@ func oidcAuthMiddleware(verifier *oidc.IDTokenVerifier) echo.MiddlewareFunc {
 				return echo.NewHTTPError(http.StatusUnauthorized, "a Bearer token is required in the Authorization header")
 			}
 
-			_, err := verifier.Verify(c.Request().Context(), rawToken)
+			token, err := verifier.Verify(c.Request().Context(), rawToken)
 			if err != nil {
 				if logger, ok := c.Get("logger").(*slog.Logger); ok && logger != nil {
 					logger.DebugContext(c.Request().Context(), "OIDC token verification failed", slog.Any("error", err))
@@ -422,6 +470,179 @@ func oidcAuthMiddleware(verifier *oidc.IDTokenVerifier) echo.MiddlewareFunc {
 				return echo.NewHTTPError(http.StatusUnauthorized, "the Bearer token is invalid")
 			}
 
+			c.Set("identity", token.Subject)
+
+			return next(c)
+		}
+	}
+}
+
+// apiKeyAuthMiddleware validates the API key in the Authorization header
+// against the store, which reloads its file on change. It answers 401 for a
+// missing, unknown or expired key, and 403 when the key's routes do not cover
+// the requested path. The key name, i.e., the caller identity, is set in the
+// [echo.Context] under "identity", and attached to the logger and the span.
+//
+//	identity := c.Get("identity").(string)
+func apiKeyAuthMiddleware(store *apiKeyStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			logger, _ := c.Get("logger").(*slog.Logger)
+
+			err := store.reload()
+			if err != nil && logger != nil {
+				logger.ErrorContext(c.Request().Context(), "reload API keys, keep the previous ones", slog.Any("error", err))
+			}
+
+			rawKey, ok := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+			if !ok || rawKey == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "an API key is required in the Authorization header")
+			}
+
+			key, ok := store.lookup(rawKey)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "the API key is invalid")
+			}
+
+			if key.expired(time.Now()) {
+				return echo.NewHTTPError(http.StatusUnauthorized, "the API key has expired")
+			}
+
+			rootPath, _ := c.Get("rootPath").(string)
+			path := strings.TrimPrefix(c.Request().URL.Path, strings.TrimSuffix(rootPath, "/"))
+			if !key.allows(path) {
+				return echo.NewHTTPError(http.StatusForbidden, "the API key is not allowed to access this route")
+			}
+
+			c.Set("identity", key.Name)
+			if logger != nil {
+				c.Set("logger", logger.With(slog.String("identity", key.Name)))
+			}
+			trace.SpanFromContext(c.Request().Context()).SetAttributes(attribute.String("enduser.id", key.Name))
+
+			return next(c)
+		}
+	}
+}
+
+// clientCertMiddleware handles the client certificate verified during the
+// TLS handshake. If there is one, its subject must match, like one of its
+// SANs, the allow list (if any); otherwise, it answers 403. The subject is set
+// in the [echo.Context] under "clientCertSubject", and attached to the logger
+// and the span. Requests without a certificate, only possible with the
+// optional verification mode, pass through, unless there is an allow list:
+// they answer 403 too.
+//
+// The probe paths, i.e., the health and readiness routes, are not checked, so
+// that an orchestrator without a client certificate may still probe the
+// instance.
+//
+//	clientCertSubject := c.Get("clientCertSubject").(string)
+func clientCertMiddleware(allowList []*regexp2.Regexp, timeout time.Duration, probePaths []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if slices.Contains(probePaths, c.Request().URL.Path) {
+				return next(c)
+			}
+
+			state := c.Request().TLS
+			if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+				if len(allowList) > 0 {
+					return echo.NewHTTPError(http.StatusForbidden, "a client certificate is required")
+				}
+
+				return next(c)
+			}
+
+			cert := state.VerifiedChains[0][0]
+			subject := cert.Subject.String()
+			logger, _ := c.Get("logger").(*slog.Logger)
+
+			if len(allowList) > 0 {
+				allowed := false
+				deadline := time.Now().Add(timeout)
+
+				for _, identity := range clientCertIdentities(cert) {
+					err := gotenberg.FilterDeadline(allowList, nil, identity, deadline)
+					if err == nil {
+						allowed = true
+						break
+					}
+
+					if !errors.Is(err, gotenberg.ErrFiltered) && logger != nil {
+						logger.DebugContext(c.Request().Context(), "client certificate allow list check failed", slog.Any("error", err))
+					}
+				}
+
+				if !allowed {
+					return echo.NewHTTPError(http.StatusForbidden, "the client certificate is not allowed")
+				}
+			}
+
+			c.Set("clientCertSubject", subject)
+			if logger != nil {
+				c.Set("logger", logger.With(slog.String("client_cert_subject", subject)))
+			}
+			trace.SpanFromContext(c.Request().Context()).SetAttributes(attribute.String("tls.client.subject", subject))
+
+			return next(c)
+		}
+	}
+}
+
+// priorityHeader is the header which sets the priority class of a request.
+const priorityHeader = "Gotenberg-Priority"
+
+// priorityMiddleware reads the priority class of a request from the
+// "Gotenberg-Priority" header and sets it in the request context, so that the
+// process supervisors serve the queued conversions accordingly. Only the
+// clients whose identity passes the allow and deny lists may ask for the high
+// priority class.
+func priorityMiddleware(settings *atomic.Pointer[reloadableSettings], timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			value := strings.ToLower(strings.TrimSpace(c.Request().Header.Get(priorityHeader)))
+			if value == "" {
+				return next(c)
+			}
+
+			priority, err := gotenberg.ParsePriority(value)
+			if err != nil {
+				return WrapError(
+					fmt.Errorf("parse priority: %w", err),
+					NewSentinelHttpError(
+						http.StatusBadRequest,
+						fmt.Sprintf("Invalid '%s' header value: want '%s', '%s' or '%s'", priorityHeader, gotenberg.PriorityHigh, gotenberg.PriorityNormal, gotenberg.PriorityLow),
+					),
+				)
+			}
+
+			logger, _ := c.Get("logger").(*slog.Logger)
+
+			if priority == gotenberg.PriorityHigh {
+				identity := CallerIdentity(c)
+
+				current := settings.Load()
+				err = gotenberg.FilterDeadline(current.highPriorityAllowList, current.highPriorityDenyList, identity, time.Now().Add(timeout))
+				if err != nil {
+					if !errors.Is(err, gotenberg.ErrFiltered) && logger != nil {
+						logger.DebugContext(c.Request().Context(), "high priority allow list check failed", slog.Any("error", err))
+					}
+
+					return WrapError(
+						fmt.Errorf("high priority for client '%s': %w", identity, err),
+						NewSentinelHttpError(http.StatusForbidden, "The high priority is not allowed for this client."),
+					)
+				}
+			}
+
+			if logger != nil {
+				c.Set("logger", logger.With(slog.String("priority", priority.String())))
+			}
+			trace.SpanFromContext(c.Request().Context()).SetAttributes(attribute.String("gotenberg.priority", priority.String()))
+
+			c.SetRequest(c.Request().WithContext(gotenberg.ContextWithPriority(c.Request().Context(), priority)))
+
 			return next(c)
 		}
 	}
@@ -434,7 +655,7 @@ func oidcAuthMiddleware(verifier *oidc.IDTokenVerifier) echo.MiddlewareFunc {
 //
 //	ctx := c.Get("context").(*api.Context)
 //	cancel := c.Get("cancel").(context.CancelFunc)
-func contextMiddleware(fs *gotenberg.FileSystem, timeout time.Duration, bodyLimit int64, downloadFromCfg downloadFromConfig) echo.MiddlewareFunc {
+func contextMiddleware(fs *gotenberg.FileSystem, timeout time.Duration, bodyLimit int64, settings *atomic.Pointer[reloadableSettings], uploads *uploadStore, pdfEngine gotenberg.PdfEngine) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			logger, _ := c.Get("logger").(*slog.Logger)
@@ -442,29 +663,62 @@ func contextMiddleware(fs *gotenberg.FileSystem, timeout time.Duration, bodyLimi
 				return errors.New("no logger in context (possible pool reuse)")
 			}
 
+			// The request keeps the same settings from start to finish, even
+			// if they change on reload in the meantime.
+			current := settings.Load()
+
 			// We create a context with a timeout so that underlying processes are
 			// able to stop early and correctly handle a timeout scenario.
-			ctx, cancel, err := newContext(c, logger, fs, timeout, bodyLimit, downloadFromCfg)
+			ctx, cancel, err := newContext(c, logger, fs, timeout, bodyLimit, current.downloadFromCfg, uploads)
 			if err != nil {
 				cancel()
 
 				return fmt.Errorf("create request context: %w", err)
 			}
+
+			ctx.uploadTo, err = parseUploadTo(ctx, current.uploadToCfg)
+			if err != nil {
+				cancel()
+
+				return fmt.Errorf("parse upload to destinations: %w", err)
+			}
+			ctx.pdfEngine = pdfEngine
 			c.Set("context", ctx)
 			c.Set("cancel", cancel)
 
+			// Audit log? The input files are hashed before the route handler
+			// may alter them.
+			files, _ := c.Get("auditFiles").(*auditFiles)
+			if files != nil {
+				files.digestInputs(ctx)
+				ctx.asyncDone = files.asyncDone(c, ctx)
+			}
+
 			// Call the next middleware in the chain.
 			err = next(c)
 
 			if errors.Is(err, ErrAsyncProcess) {
 				// A middleware/handler tells us that it's handling the process
 				// in an asynchronous fashion. Therefore, we must not cancel
-				// the context nor send an output file.
+				// the context nor send an output file. It may have already
+				// acknowledged the request with its own response.
+				if files != nil {
+					files.async = true
+				}
+
+				if c.Response().Committed {
+					return nil
+				}
 				return c.NoContent(http.StatusNoContent)
 			}
 
 			defer cancel()
 
+			if files != nil {
+				// Before the cancellation removes the output files.
+				defer files.digestOutputs(ctx)
+			}
+
 			if errors.Is(err, ErrNoOutputFile) {
 				// A middleware/handler tells us that it's handling the process
 				// in an asynchronous fashion. Therefore, we must not cancel
@@ -476,14 +730,39 @@ func contextMiddleware(fs *gotenberg.FileSystem, timeout time.Duration, bodyLimi
 				return err
 			}
 
-			// No error, let's build the output file.
+			// No error, let's upload the output files to the destinations
+			// from the "uploadTo" form field, if any.
+			if ctx.HasUploadTo() {
+				return uploadOutputFiles(c, ctx, current.uploadToCfg)
+			}
+
+			// Otherwise, let's build the output file.
 			outputPath, err := ctx.BuildOutputFile()
 			if err != nil {
 				return fmt.Errorf("build output file: %w", err)
 			}
 
-			// Send the output file.
-			err = c.Attachment(outputPath, ctx.OutputFilename(outputPath))
+			// A range of the output file would not match its digest.
+			if c.Request().Header.Get("Range") == "" {
+				digest, err := ctx.OutputDigest(outputPath)
+				if err != nil {
+					return fmt.Errorf("compute content digest: %w", err)
+				}
+				c.Response().Header().Set(ContentDigestHeader, digest)
+			}
+
+			// Send the output file. The parts of a "multipart/mixed" body
+			// carry their own filenames.
+			contentType := ctx.OutputContentType(outputPath)
+			if contentType != "" {
+				c.Response().Header().Set(echo.HeaderContentType, contentType)
+			}
+
+			if strings.HasPrefix(contentType, "multipart/") {
+				err = c.File(outputPath)
+			} else {
+				err = c.Attachment(outputPath, ctx.OutputFilename(outputPath))
+			}
 			if err != nil {
 				return fmt.Errorf("send response: %w", err)
 			}
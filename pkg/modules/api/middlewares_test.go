@@ -4,18 +4,27 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+	"github.com/dlclark/regexp2"
 	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 )
 
 // TestRequestCanceled pins the client-abort discriminator: only a
@@ -82,6 +91,39 @@ func TestHttpErrorHandler_ClientClosedRequest(t *testing.T) {
 	}
 }
 
+// TestHttpErrorHandler_Screenshot ensures an error with a screenshot is sent
+// as JSON, and any other error as plain text.
+func TestHttpErrorHandler_Screenshot(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		err             error
+		wantContentType string
+		wantBody        string
+	}{
+		{"plain error", NewSentinelHttpError(http.StatusConflict, "foo"), echo.MIMETextPlainCharsetUTF8, "foo"},
+		{"error with screenshot", WithScreenshot(NewSentinelHttpError(http.StatusConflict, "foo"), []byte("foo")), echo.MIMEApplicationJSON, `{"status":409,"message":"foo","screenshot":"Zm9v"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.Set("logger", slog.New(slog.DiscardHandler))
+
+			httpErrorHandler()(tc.err, c)
+
+			if rec.Code != http.StatusConflict {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusConflict)
+			}
+			if got := rec.Header().Get(echo.HeaderContentType); got != tc.wantContentType {
+				t.Errorf("content type = %q, want %q", got, tc.wantContentType)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); got != tc.wantBody {
+				t.Errorf("body = %q, want %q", got, tc.wantBody)
+			}
+		})
+	}
+}
+
 // TestOutputFilenameMiddleware pins the sanitizing of the
 // "Gotenberg-Output-Filename" header. The value reaches archive entry names and
 // a Content-Disposition header, so a path separator must never survive it.
@@ -230,6 +272,83 @@ func TestOidcAuthMiddleware(t *testing.T) {
 				if err != nil {
 					t.Fatalf("expected the request to pass, got error: %v", err)
 				}
+				if identity := c.Get("identity"); identity != "user" {
+					t.Fatalf("identity = %v, want %q", identity, "user")
+				}
+				return
+			}
+
+			var httpErr *echo.HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected an *echo.HTTPError, got %T (%v)", err, err)
+			}
+			if httpErr.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d", httpErr.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestApiKeyAuthMiddleware(t *testing.T) {
+	// SHA-256 of "foo" and "bar".
+	const (
+		fooHash = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+		barHash = "fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"
+	)
+
+	keysFile := filepath.Join(t.TempDir(), "keys.json")
+	err := os.WriteFile(keysFile, []byte(fmt.Sprintf(`[
+  {"name": "pdfengines", "hash": %q, "routes": ["/forms/pdfengines/*", "/version"]},
+  {"name": "expired", "hash": %q, "expiresAt": "2000-01-01T00:00:00Z"}
+]`, fooHash, barHash)), 0o600)
+	if err != nil {
+		t.Fatalf("write keys file: %v", err)
+	}
+
+	store, err := newApiKeyStore(keysFile)
+	if err != nil {
+		t.Fatalf("load keys: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario   string
+		rootPath   string
+		path       string
+		authHeader string
+		wantStatus int
+	}{
+		{"valid key and prefix route", "/", "/forms/pdfengines/merge", "Bearer foo", http.StatusOK},
+		{"valid key and exact route", "/", "/version", "Bearer foo", http.StatusOK},
+		{"valid key with root path", "/foo/", "/foo/forms/pdfengines/merge", "Bearer foo", http.StatusOK},
+		{"missing header", "/", "/forms/pdfengines/merge", "", http.StatusUnauthorized},
+		{"wrong scheme", "/", "/forms/pdfengines/merge", "Basic Zm9vOmJhcg==", http.StatusUnauthorized},
+		{"unknown key", "/", "/forms/pdfengines/merge", "Bearer baz", http.StatusUnauthorized},
+		{"expired key", "/", "/forms/pdfengines/merge", "Bearer bar", http.StatusUnauthorized},
+		{"route not allowed", "/", "/forms/chromium/convert/url", "Bearer foo", http.StatusForbidden},
+		{"exact route not a prefix", "/", "/versions", "Bearer foo", http.StatusForbidden},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+			if tc.authHeader != "" {
+				req.Header.Set("Authorization", tc.authHeader)
+			}
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			c.Set("rootPath", tc.rootPath)
+			c.Set("logger", slog.New(slog.DiscardHandler))
+
+			handler := apiKeyAuthMiddleware(store)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			err := handler(c)
+
+			if tc.wantStatus == http.StatusOK {
+				if err != nil {
+					t.Fatalf("expected the request to pass, got error: %v", err)
+				}
+				if identity := c.Get("identity"); identity != "pdfengines" {
+					t.Fatalf("identity = %v, want %q", identity, "pdfengines")
+				}
 				return
 			}
 
@@ -243,3 +362,120 @@ func TestOidcAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestClientCertMiddleware(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "billing"},
+		DNSNames: []string{"billing.internal"},
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		path        string
+		allowList   []*regexp2.Regexp
+		state       *tls.ConnectionState
+		wantStatus  int
+		wantSubject any
+	}{
+		{"no TLS", "/", nil, nil, http.StatusOK, nil},
+		{"no client certificate", "/", nil, &tls.ConnectionState{}, http.StatusOK, nil},
+		{"no client certificate with an allow list", "/", []*regexp2.Regexp{regexp2.MustCompile("^CN=billing$", 0)}, &tls.ConnectionState{}, http.StatusForbidden, nil},
+		{"no TLS with an allow list", "/", []*regexp2.Regexp{regexp2.MustCompile("^CN=billing$", 0)}, nil, http.StatusForbidden, nil},
+		{"health probe without client certificate", "/health", []*regexp2.Regexp{regexp2.MustCompile("^CN=billing$", 0)}, &tls.ConnectionState{}, http.StatusOK, nil},
+		{"readiness probe without client certificate", "/ready", []*regexp2.Regexp{regexp2.MustCompile("^CN=billing$", 0)}, &tls.ConnectionState{}, http.StatusOK, nil},
+		{"no allow list", "/", nil, &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}, http.StatusOK, "CN=billing"},
+		{"subject allowed", "/", []*regexp2.Regexp{regexp2.MustCompile("^CN=billing$", 0)}, &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}, http.StatusOK, "CN=billing"},
+		{"SAN allowed", "/", []*regexp2.Regexp{regexp2.MustCompile(`\.internal$`, 0)}, &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}, http.StatusOK, "CN=billing"},
+		{"not allowed", "/", []*regexp2.Regexp{regexp2.MustCompile("^CN=reporting$", 0)}, &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}, http.StatusForbidden, nil},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.TLS = tc.state
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			c.Set("logger", slog.New(slog.DiscardHandler))
+
+			handler := clientCertMiddleware(tc.allowList, time.Second, []string{"/health", "/ready"})(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			err := handler(c)
+
+			if tc.wantStatus == http.StatusOK {
+				if err != nil {
+					t.Fatalf("expected the request to pass, got error: %v", err)
+				}
+				if subject := c.Get("clientCertSubject"); subject != tc.wantSubject {
+					t.Fatalf("subject = %v, want %v", subject, tc.wantSubject)
+				}
+				return
+			}
+
+			var httpErr *echo.HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected an *echo.HTTPError, got %T (%v)", err, err)
+			}
+			if httpErr.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d", httpErr.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestPriorityMiddleware(t *testing.T) {
+	allowList := []*regexp2.Regexp{regexp2.MustCompile("^previews$", 0)}
+
+	for _, tc := range []struct {
+		scenario     string
+		header       string
+		identity     string
+		allowList    []*regexp2.Regexp
+		wantStatus   int
+		wantPriority gotenberg.Priority
+	}{
+		{"no header", "", "", nil, http.StatusOK, gotenberg.PriorityNormal},
+		{"low priority", "low", "", allowList, http.StatusOK, gotenberg.PriorityLow},
+		{"case insensitive", " High ", "", nil, http.StatusOK, gotenberg.PriorityHigh},
+		{"invalid value", "urgent", "", nil, http.StatusBadRequest, gotenberg.PriorityNormal},
+		{"high priority allowed", "high", "previews", allowList, http.StatusOK, gotenberg.PriorityHigh},
+		{"high priority not allowed", "high", "archival", allowList, http.StatusForbidden, gotenberg.PriorityNormal},
+		{"high priority without identity", "high", "", allowList, http.StatusForbidden, gotenberg.PriorityNormal},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tc.header != "" {
+				req.Header.Set("Gotenberg-Priority", tc.header)
+			}
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			c.Set("logger", slog.New(slog.DiscardHandler))
+			if tc.identity != "" {
+				c.Set("identity", tc.identity)
+			}
+
+			settings := new(atomic.Pointer[reloadableSettings])
+			settings.Store(&reloadableSettings{highPriorityAllowList: tc.allowList})
+
+			var priority gotenberg.Priority
+			handler := priorityMiddleware(settings, time.Second)(func(c echo.Context) error {
+				priority = gotenberg.PriorityFromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			err := handler(c)
+
+			if tc.wantStatus == http.StatusOK {
+				if err != nil {
+					t.Fatalf("expected the request to pass, got error: %v", err)
+				}
+				if priority != tc.wantPriority {
+					t.Fatalf("priority = %s, want %s", priority, tc.wantPriority)
+				}
+				return
+			}
+
+			status, _ := ParseError(err)
+			if status != tc.wantStatus {
+				t.Fatalf("status = %d, want %d (%v)", status, tc.wantStatus, err)
+			}
+		})
+	}
+}
@@ -55,6 +55,16 @@ func (ctx *ContextMock) SetFiles(files map[string]string) {
 	ctx.files = files
 }
 
+// SetOriginalFilenames sets the original filenames, by disk path.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.SetOriginalFilenames(map[string]string{
+//	  "/foo/0a1b2c3d.pdf": "foo.pdf",
+//	})
+func (ctx *ContextMock) SetOriginalFilenames(diskToOriginal map[string]string) {
+	ctx.diskToOriginal = diskToOriginal
+}
+
 // SetCancelled sets if the context is canceled or not.
 //
 //	ctx := &api.ContextMock{Context: &api.Context{}}
@@ -135,9 +145,24 @@ func (mod *HealthCheckerMock) Ready() error {
 	return mod.ReadyMock()
 }
 
+// QueueCounterMock is a mock for the [QueueCounter] interface.
+type QueueCounterMock struct {
+	QueuedCountMock func() int64
+	ActiveCountMock func() int64
+}
+
+func (mod *QueueCounterMock) QueuedCount() int64 {
+	return mod.QueuedCountMock()
+}
+
+func (mod *QueueCounterMock) ActiveCount() int64 {
+	return mod.ActiveCountMock()
+}
+
 // Interface guards.
 var (
 	_ Router             = (*RouterMock)(nil)
 	_ MiddlewareProvider = (*MiddlewareProviderMock)(nil)
 	_ HealthChecker      = (*HealthCheckerMock)(nil)
+	_ QueueCounter       = (*QueueCounterMock)(nil)
 )
@@ -0,0 +1,732 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// errFormDataRecorded is returned by [FormData.Validate] while describing a
+// route, so that its handler stops before doing any actual work.
+var errFormDataRecorded = errors.New("form data recorded")
+
+// formFieldKind tells how a route reads a form field.
+type formFieldKind int
+
+const (
+	// valueField is a regular form field, e.g., "landscape".
+	valueField formFieldKind = iota
+
+	// fileField is a file with a given filename, e.g., "index.html".
+	fileField
+
+	// filesField are files with given extensions, e.g., ".pdf".
+	filesField
+
+	// namedFilesField are files uploaded with a given form field name, e.g.,
+	// "embeds".
+	namedFilesField
+)
+
+// Types of value fields which do not map directly to a JSON schema type.
+const (
+	durationFieldType = "duration"
+	inchesFieldType   = "inches"
+	customFieldType   = "custom"
+	stringsFieldType  = "strings"
+)
+
+// formField describes a form field read by a route through its [FormData].
+type formField struct {
+	Kind       formFieldKind
+	Name       string
+	Type       string
+	Required   bool
+	Default    any
+	Extensions []string
+	Multiple   bool
+}
+
+// formRecorder records the form fields read by a route.
+type formRecorder struct {
+	fields []formField
+}
+
+// add records a form field, merging it with a previous read of the same
+// field.
+func (r *formRecorder) add(field formField) {
+	for i, f := range r.fields {
+		if f.Kind != field.Kind || f.Name != field.Name || !slices.Equal(f.Extensions, field.Extensions) {
+			continue
+		}
+
+		r.fields[i].Required = f.Required || field.Required
+		r.fields[i].Multiple = f.Multiple || field.Multiple
+		if f.Default == nil {
+			r.fields[i].Default = field.Default
+		}
+
+		return
+	}
+
+	r.fields = append(r.fields, field)
+}
+
+// describeRoute runs the handler of a "multipart/form-data" route against an
+// empty [FormData] which records every form field the handler reads. The
+// handler stops on [FormData.Validate], before doing any actual work.
+func describeRoute(route Route, logger *slog.Logger) (fields []formField) {
+	recorder := new(formRecorder)
+
+	defer func() {
+		// A handler may not expect empty form data: keep what we recorded
+		// so far, but tell, as the description may be incomplete.
+		if r := recover(); r != nil {
+			logger.Warn(fmt.Sprintf("describe route '%s %s': handler panicked, its OpenAPI description may be incomplete: %v", route.Method, route.Path, r))
+		}
+		fields = recorder.fields
+	}()
+
+	// Anything that would still try to do some work should fail fast.
+	reqCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := httptest.NewRequestWithContext(reqCtx, route.Method, route.Path, nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	handlerLogger := slog.New(slog.DiscardHandler)
+
+	ctx := &Context{
+		values:         make(map[string][]string),
+		files:          make(map[string]string),
+		filesByField:   make(map[string][]string),
+		diskToOriginal: make(map[string]string),
+		logger:         handlerLogger,
+		echoCtx:        c,
+		recorder:       recorder,
+		Context:        reqCtx,
+	}
+
+	c.Set("logger", handlerLogger)
+	c.Set("context", ctx)
+	c.Set("cancel", cancel)
+
+	_ = route.Handler(c)
+
+	return fields
+}
+
+type openApiDocument struct {
+	OpenApi string                                  `json:"openapi"`
+	Info    openApiInfo                             `json:"info"`
+	Paths   map[string]map[string]*openApiOperation `json:"paths"`
+}
+
+type openApiInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openApiOperation struct {
+	OperationId string                     `json:"operationId"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []openApiParameter         `json:"parameters,omitempty"`
+	RequestBody *openApiRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openApiResponse `json:"responses"`
+}
+
+type openApiParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openApiSchema `json:"schema"`
+}
+
+type openApiRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openApiMediaType `json:"content"`
+}
+
+type openApiMediaType struct {
+	Schema *openApiSchema `json:"schema"`
+}
+
+type openApiResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openApiMediaType `json:"content,omitempty"`
+}
+
+type openApiSchema struct {
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Default              any                       `json:"default,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *openApiSchema            `json:"items,omitempty"`
+	Properties           map[string]*openApiSchema `json:"properties,omitempty"`
+	AdditionalProperties *openApiSchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+}
+
+// openApiBuilder builds the OpenAPI document of the [Api].
+type openApiBuilder struct {
+	rootPath            string
+	correlationIdHeader string
+	enableDebugRoute    bool
+	enableAdminRoutes   bool
+	enableUploads       bool
+	enableIdempotency   bool
+	logger              *slog.Logger
+}
+
+// build returns the OpenAPI document for the given routes. Routes paths must
+// start with a slash.
+func (b openApiBuilder) build(routes []Route) openApiDocument {
+	doc := openApiDocument{
+		OpenApi: "3.0.3",
+		Info: openApiInfo{
+			Title:   "Gotenberg",
+			Version: gotenberg.Version,
+		},
+		Paths: make(map[string]map[string]*openApiOperation),
+	}
+
+	for _, route := range routes {
+		op := b.operation(route.Method, route.Path)
+
+		if route.IsMultipart {
+			b.describeMultipart(op, route, describeRoute(route, b.logger))
+		}
+
+		b.add(doc, route.Method, route.Path, op)
+	}
+
+	b.add(doc, http.MethodGet, "/health", b.operation(http.MethodGet, "/health"))
+	b.add(doc, http.MethodHead, "/health", b.operation(http.MethodHead, "/health"))
+	b.add(doc, http.MethodGet, "/version", b.operation(http.MethodGet, "/version"))
+	b.add(doc, http.MethodGet, "/openapi.json", b.operation(http.MethodGet, "/openapi.json"))
+	b.add(doc, http.MethodGet, "/docs", b.operation(http.MethodGet, "/docs"))
+
+	if b.enableDebugRoute {
+		b.add(doc, http.MethodGet, "/debug", b.operation(http.MethodGet, "/debug"))
+	}
+
+	if b.enableAdminRoutes {
+		b.describeAdmin(doc)
+	}
+
+	if b.enableUploads {
+		b.describeUploads(doc)
+	}
+
+	return doc
+}
+
+// add adds an operation to the document, converting the echo path parameters
+// (i.e., ":id") to the OpenAPI syntax (i.e., "{id}").
+func (b openApiBuilder) add(doc openApiDocument, method, path string, op *openApiOperation) {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+
+		name := strings.TrimPrefix(segment, ":")
+		segments[i] = fmt.Sprintf("{%s}", name)
+		op.Parameters = append(op.Parameters, openApiParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &openApiSchema{Type: "string"},
+		})
+	}
+
+	fullPath := b.rootPath + strings.Join(segments, "/")
+	if _, ok := doc.Paths[fullPath]; !ok {
+		doc.Paths[fullPath] = make(map[string]*openApiOperation)
+	}
+
+	doc.Paths[fullPath][strings.ToLower(method)] = op
+}
+
+// operation returns an operation with the defaults shared by every route.
+func (b openApiBuilder) operation(method, path string) *openApiOperation {
+	segments := strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var tag string
+	switch {
+	case len(segments) > 1 && segments[0] == "forms":
+		tag = segments[1]
+	case len(segments) > 0:
+		tag = segments[0]
+	}
+
+	var operationId strings.Builder
+	operationId.WriteString(strings.ToLower(method))
+	for _, segment := range segments {
+		operationId.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+
+	op := &openApiOperation{
+		OperationId: operationId.String(),
+		Responses: map[string]openApiResponse{
+			"200": {Description: "Successful response."},
+		},
+	}
+
+	if tag != "" {
+		op.Tags = []string{tag}
+	}
+
+	return op
+}
+
+// describeMultipart completes an operation with the form fields read by a
+// "multipart/form-data" route. Such routes also accept a JSON body.
+func (b openApiBuilder) describeMultipart(op *openApiOperation, route Route, fields []formField) {
+	multipartSchema := &openApiSchema{Type: "object", Properties: make(map[string]*openApiSchema)}
+	jsonSchema := &openApiSchema{Type: "object", Properties: make(map[string]*openApiSchema)}
+
+	var files []string
+	var filesRequired bool
+
+	for _, field := range fields {
+		switch field.Kind {
+		case valueField:
+			multipartSchema.Properties[field.Name] = multipartValueSchema(field)
+			jsonSchema.Properties[field.Name] = jsonValueSchema(field)
+
+			if raw, ok := route.FormFieldSchemas[field.Name]; ok {
+				schema := new(openApiSchema)
+				err := json.Unmarshal(raw, schema)
+				if err != nil {
+					b.logger.Warn(fmt.Sprintf("describe route '%s %s': invalid JSON schema of form field '%s': %s", route.Method, route.Path, field.Name, err))
+				} else {
+					description := "JSON-encoded."
+					if schema.Description != "" {
+						description = fmt.Sprintf("JSON-encoded. %s", schema.Description)
+					}
+					multipartSchema.Properties[field.Name] = &openApiSchema{Type: "string", Description: description}
+					jsonSchema.Properties[field.Name] = schema
+				}
+			}
+
+			if field.Required {
+				multipartSchema.Required = append(multipartSchema.Required, field.Name)
+				jsonSchema.Required = append(jsonSchema.Required, field.Name)
+			}
+		case fileField:
+			files = append(files, describeFile(field.Name, field.Required))
+			filesRequired = filesRequired || field.Required
+		case filesField:
+			files = append(files, describeFile(fmt.Sprintf("*%s", strings.Join(field.Extensions, ", *")), field.Required))
+			filesRequired = filesRequired || field.Required
+		case namedFilesField:
+			schema := &openApiSchema{Type: "string", Format: "binary"}
+			if field.Multiple {
+				schema = &openApiSchema{Type: "array", Items: schema}
+			}
+			schema.Description = fmt.Sprintf("In a JSON body, use a 'files' entry with the '%s' field.", field.Name)
+			multipartSchema.Properties[field.Name] = schema
+		}
+	}
+
+	description := "Files to process. Files may also come from 'downloadFrom'."
+	switch {
+	case route.FilesDescription != "":
+		description = fmt.Sprintf("%s Files may also come from 'downloadFrom'.", route.FilesDescription)
+	case len(files) > 0:
+		description = fmt.Sprintf("Files to process: %s. Files may also come from 'downloadFrom'.", strings.Join(files, "; "))
+	}
+
+	multipartSchema.Properties[jsonFilesKey] = &openApiSchema{
+		Type:        "array",
+		Description: description,
+		Items:       &openApiSchema{Type: "string", Format: "binary"},
+	}
+	multipartSchema.Properties["downloadFrom"] = &openApiSchema{
+		Type:        "string",
+		Description: `JSON array of files to download, e.g., [{"url":"https://example.com/foo.pdf","extraHttpHeaders":{"X-Foo":"bar"},"embedded":false}].`,
+	}
+
+	multipartSchema.Properties[UploadToFormField] = &openApiSchema{
+		Type:        "string",
+		Description: `JSON array of destinations to upload the resulting files to instead of sending them in the response, e.g., [{"url":"https://bucket.example.com/foo.pdf?X-Amz-Signature=...","method":"PUT","extraHttpHeaders":{"X-Foo":"bar"}}]. A single destination receives the resulting file or archive; otherwise, one destination per resulting file.`,
+	}
+	jsonSchema.Properties[UploadToFormField] = &openApiSchema{
+		Type:        "array",
+		Description: "Destinations to upload the resulting files to instead of sending them in the response. A single destination receives the resulting file or archive; otherwise, one destination per resulting file.",
+		Items: &openApiSchema{
+			Type: "object",
+			Properties: map[string]*openApiSchema{
+				"url":              {Type: "string", Description: "URL to upload the file to, e.g., a presigned URL."},
+				"method":           {Type: "string", Default: http.MethodPut, Enum: []string{http.MethodPut, http.MethodPost}},
+				"extraHttpHeaders": {Type: "object", AdditionalProperties: &openApiSchema{Type: "string"}},
+			},
+			Required: []string{"url"},
+		},
+	}
+
+	outputFormatSchema := &openApiSchema{
+		Type:        "string",
+		Default:     string(outputFormatZip),
+		Description: fmt.Sprintf("Format of the response if there are many resulting files: '%s', '%s', '%s' or '%s'. Takes precedence over the 'Accept' header.", outputFormatZip, outputFormatTar, outputFormatTarGz, outputFormatMultipart),
+	}
+	multipartSchema.Properties[OutputFormatFormField] = outputFormatSchema
+	jsonSchema.Properties[OutputFormatFormField] = outputFormatSchema
+
+	if b.enableUploads {
+		multipartSchema.Properties[UploadsFormField] = &openApiSchema{
+			Type:        "string",
+			Description: `JSON array of the IDs of completed resumable uploads to process, e.g., ["1b4e28ba-2fa1-11d2-883f-0016d3cca427"].`,
+		}
+		jsonSchema.Properties[UploadsFormField] = &openApiSchema{
+			Type:        "array",
+			Description: "IDs of completed resumable uploads to process.",
+			Items:       &openApiSchema{Type: "string"},
+		}
+	}
+
+	jsonSchema.Properties[jsonFilesKey] = &openApiSchema{
+		Type:        "array",
+		Description: description,
+		Items: &openApiSchema{
+			Type: "object",
+			Properties: map[string]*openApiSchema{
+				"filename":         {Type: "string", Description: "Name of the file, extension included. Required with 'content'."},
+				"content":          {Type: "string", Format: "byte", Description: "Base64 encoded content of the file."},
+				"url":              {Type: "string", Description: "URL to download the file from."},
+				"extraHttpHeaders": {Type: "object", AdditionalProperties: &openApiSchema{Type: "string"}},
+				"field": {
+					Type:        "string",
+					Default:     jsonFilesKey,
+					Description: fmt.Sprintf("Form field of the file: '%s', '%s', '%s', '%s', '%s', '%s' or '%s'.", jsonFilesKey, EmbedsFormField, WatermarkFormField, StampFormField, FacturXXmlFormField, PreloadScriptsFormField, ExtraStylesheetsFormField),
+				},
+			},
+		},
+	}
+
+	sort.Strings(multipartSchema.Required)
+	sort.Strings(jsonSchema.Required)
+
+	op.RequestBody = &openApiRequestBody{
+		Required: filesRequired || len(multipartSchema.Required) > 0,
+		Content: map[string]openApiMediaType{
+			echo.MIMEMultipartForm:   {Schema: multipartSchema},
+			echo.MIMEApplicationJSON: {Schema: jsonSchema},
+		},
+	}
+
+	op.Parameters = append(op.Parameters,
+		openApiParameter{
+			Name:        "Gotenberg-Output-Filename",
+			In:          "header",
+			Description: "Filename of the resulting file, without extension.",
+			Schema:      &openApiSchema{Type: "string"},
+		},
+		openApiParameter{
+			Name:        priorityHeader,
+			In:          "header",
+			Description: "Priority class of the request in the conversion queues.",
+			Schema: &openApiSchema{
+				Type:    "string",
+				Default: gotenberg.PriorityNormal.String(),
+				Enum:    []string{gotenberg.PriorityHigh.String(), gotenberg.PriorityNormal.String(), gotenberg.PriorityLow.String()},
+			},
+		},
+		openApiParameter{
+			Name:        b.correlationIdHeader,
+			In:          "header",
+			Description: "Identifier of the request, for correlating logs and traces.",
+			Schema:      &openApiSchema{Type: "string"},
+		},
+		openApiParameter{
+			Name:        wantContentDigestHeader,
+			In:          "header",
+			Description: "Digest algorithms of the 'Content-Digest' response header, with their preferences, e.g., 'sha-512=10, sha-256=1' (RFC 9530). Default to 'sha-256'.",
+			Schema:      &openApiSchema{Type: "string"},
+		},
+	)
+
+	if b.enableIdempotency {
+		op.Parameters = append(op.Parameters, openApiParameter{
+			Name:        idempotencyKeyHeader,
+			In:          "header",
+			Description: fmt.Sprintf("Unique key of the request, at most %d characters. Duplicates of a successful request receive its stored response, with the '%s' header, instead of running the conversion again.", maxIdempotencyKeyLength, idempotentReplayedHeader),
+			Schema:      &openApiSchema{Type: "string"},
+		})
+	}
+
+	errContent := map[string]openApiMediaType{
+		echo.MIMETextPlain: {Schema: &openApiSchema{Type: "string"}},
+	}
+
+	op.Responses = map[string]openApiResponse{
+		"200": {
+			Description: "The resulting file or, if there are many, a ZIP archive, a tar (gzipped or not) archive or a multipart/mixed body, depending on the 'outputFormat' form field or the 'Accept' header. Archives and multipart/mixed bodies include a 'manifest.json' file listing the digests and page counts of the resulting files. The 'Content-Digest' header carries the digests of the response body. With 'uploadTo', a JSON summary of the uploads.",
+			Content: map[string]openApiMediaType{
+				"application/octet-stream": {Schema: &openApiSchema{Type: "string", Format: "binary"}},
+				"multipart/mixed":          {Schema: &openApiSchema{Type: "string", Format: "binary"}},
+				echo.MIMEApplicationJSON: {Schema: &openApiSchema{
+					Type: "array",
+					Items: &openApiSchema{
+						Type: "object",
+						Properties: map[string]*openApiSchema{
+							"filename": {Type: "string"},
+							"url":      {Type: "string", Description: "URL of the destination, without its query string."},
+							"method":   {Type: "string"},
+							"status":   {Type: "integer"},
+							"size":     {Type: "integer", Format: "int64"},
+						},
+					},
+				}},
+			},
+		},
+		"400": {Description: "Invalid form data.", Content: errContent},
+		"413": {Description: "The request body is too large.", Content: errContent},
+		"415": {Description: "The request body is neither multipart/form-data nor JSON.", Content: errContent},
+		"502": {Description: "An upload to a destination from 'uploadTo' failed.", Content: errContent},
+		"503": {Description: "The request timed out or the service is unavailable.", Content: errContent},
+	}
+
+	if b.enableIdempotency {
+		op.Responses["409"] = openApiResponse{Description: "A request with the same idempotency key is still in progress.", Content: errContent}
+	}
+}
+
+// describeAdmin adds the admin routes, which drain the instance, and the
+// readiness routes.
+func (b openApiBuilder) describeAdmin(doc openApiDocument) {
+	statusContent := map[string]openApiMediaType{
+		echo.MIMEApplicationJSON: {Schema: &openApiSchema{
+			Type: "object",
+			Properties: map[string]*openApiSchema{
+				"draining": {Type: "boolean", Description: "Whether the instance is draining."},
+				"queued":   {Type: "integer", Format: "int64", Description: "Number of queued conversions."},
+				"active":   {Type: "integer", Format: "int64", Description: "Number of active conversions."},
+				"async":    {Type: "integer", Format: "int64", Description: "Number of asynchronous conversions, e.g., webhooks."},
+			},
+		}},
+	}
+
+	status := b.operation(http.MethodGet, "/admin/drain")
+	status.Responses = map[string]openApiResponse{
+		"200": {Description: "The drain status and the remaining conversions.", Content: statusContent},
+	}
+	b.add(doc, http.MethodGet, "/admin/drain", status)
+
+	drain := b.operation(http.MethodPost, "/admin/drain")
+	drain.Responses = map[string]openApiResponse{
+		"200": {Description: "The instance is draining: the readiness check fails and new conversions get a 503.", Content: statusContent},
+	}
+	b.add(doc, http.MethodPost, "/admin/drain", drain)
+
+	undrain := b.operation(http.MethodPost, "/admin/undrain")
+	undrain.Responses = map[string]openApiResponse{
+		"200": {Description: "The instance accepts new conversions again.", Content: statusContent},
+	}
+	b.add(doc, http.MethodPost, "/admin/undrain", undrain)
+
+	// Unlike the health check, the readiness check fails while the instance
+	// is draining.
+	b.add(doc, http.MethodGet, "/ready", b.operation(http.MethodGet, "/ready"))
+	b.add(doc, http.MethodHead, "/ready", b.operation(http.MethodHead, "/ready"))
+}
+
+// describeUploads adds the resumable uploads routes, which follow the tus
+// protocol (core and creation and termination extensions).
+func (b openApiBuilder) describeUploads(doc openApiDocument) {
+	errContent := map[string]openApiMediaType{
+		echo.MIMETextPlain: {Schema: &openApiSchema{Type: "string"}},
+	}
+
+	integerHeader := func(name, description string) openApiParameter {
+		return openApiParameter{
+			Name:        name,
+			In:          "header",
+			Description: description,
+			Required:    true,
+			Schema:      &openApiSchema{Type: "integer", Format: "int64"},
+		}
+	}
+
+	create := b.operation(http.MethodPost, "/uploads")
+	create.Parameters = []openApiParameter{
+		integerHeader("Upload-Length", "Size of the file, in bytes."),
+		{
+			Name:        "Upload-Metadata",
+			In:          "header",
+			Description: "Metadata of the file, e.g., 'filename <base64 encoded filename>'.",
+			Required:    true,
+			Schema:      &openApiSchema{Type: "string"},
+		},
+	}
+	create.Responses = map[string]openApiResponse{
+		"201": {Description: "The upload is created; the 'Location' header is its URL."},
+		"400": {Description: "Invalid headers.", Content: errContent},
+		"413": {Description: "The file is too large.", Content: errContent},
+	}
+	b.add(doc, http.MethodPost, "/uploads", create)
+
+	head := b.operation(http.MethodHead, "/uploads/:id")
+	head.Responses = map[string]openApiResponse{
+		"200": {Description: "The 'Upload-Offset' and 'Upload-Length' headers tell the state of the upload."},
+		"404": {Description: "The upload does not exist or has expired."},
+	}
+	b.add(doc, http.MethodHead, "/uploads/:id", head)
+
+	patch := b.operation(http.MethodPatch, "/uploads/:id")
+	patch.Parameters = []openApiParameter{
+		integerHeader("Upload-Offset", "Offset of the chunk, in bytes; must match the current offset of the upload."),
+	}
+	patch.RequestBody = &openApiRequestBody{
+		Required: true,
+		Content: map[string]openApiMediaType{
+			uploadChunkContentType: {Schema: &openApiSchema{Type: "string", Format: "binary"}},
+		},
+	}
+	patch.Responses = map[string]openApiResponse{
+		"204": {Description: "The chunk is written; the 'Upload-Offset' header is the new offset."},
+		"404": {Description: "The upload does not exist or has expired.", Content: errContent},
+		"409": {Description: "The offset does not match, or the upload is already receiving a chunk.", Content: errContent},
+		"413": {Description: "The chunk exceeds the upload length.", Content: errContent},
+		"415": {Description: fmt.Sprintf("The request body is not '%s'.", uploadChunkContentType), Content: errContent},
+	}
+	b.add(doc, http.MethodPatch, "/uploads/:id", patch)
+
+	del := b.operation(http.MethodDelete, "/uploads/:id")
+	del.Responses = map[string]openApiResponse{
+		"204": {Description: "The upload is removed."},
+		"404": {Description: "The upload does not exist or has expired.", Content: errContent},
+	}
+	b.add(doc, http.MethodDelete, "/uploads/:id", del)
+}
+
+// describeFile describes an accepted file.
+func describeFile(name string, required bool) string {
+	if required {
+		return fmt.Sprintf("%s (required)", name)
+	}
+
+	return name
+}
+
+// multipartValueSchema returns the schema of a value field in a
+// "multipart/form-data" body.
+func multipartValueSchema(field formField) *openApiSchema {
+	schema := &openApiSchema{Type: field.Type, Default: field.Default}
+
+	switch field.Type {
+	case durationFieldType:
+		schema.Type = "string"
+		schema.Description = "Duration, e.g., 500ms or 10s."
+	case inchesFieldType:
+		schema.Type = "string"
+		schema.Description = "Size in inches, or with a unit: pt, px, in, mm, cm or pc."
+		if field.Default != nil {
+			schema.Default = fmt.Sprintf("%v", field.Default)
+		}
+	case customFieldType:
+		schema.Type = "string"
+		schema.Description = "Either JSON or a value specific to the field."
+	case stringsFieldType:
+		schema.Type = "array"
+		schema.Items = &openApiSchema{Type: "string"}
+		schema.Description = "Repeat the field for each value."
+	}
+
+	return schema
+}
+
+// jsonValueSchema returns the schema of a value field in a JSON body.
+func jsonValueSchema(field formField) *openApiSchema {
+	schema := multipartValueSchema(field)
+
+	switch field.Type {
+	case customFieldType:
+		// Objects and arrays are given as is.
+		schema.Type = ""
+		schema.Description = "Either a JSON value or a value specific to the field."
+	case stringsFieldType:
+		// JSON bodies do not have repeated fields.
+		schema.Type = "string"
+		schema.Items = nil
+		schema.Description = ""
+	}
+
+	return schema
+}
+
+// openApiDocsTemplate renders a human-readable page from an OpenAPI
+// document. It does not rely on any external asset.
+var openApiDocsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Info.Title }} {{ .Info.Version }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 960px; color: #222; }
+details { border: 1px solid #ddd; border-radius: 4px; margin: .5em 0; padding: .5em 1em; }
+summary { cursor: pointer; font-family: monospace; font-size: 1.1em; }
+.method { display: inline-block; min-width: 4em; font-weight: bold; text-transform: uppercase; }
+table { border-collapse: collapse; width: 100%; margin: .5em 0; }
+th, td { border-bottom: 1px solid #eee; padding: .3em; text-align: left; vertical-align: top; }
+code { background: #f5f5f5; padding: 0 .2em; }
+</style>
+</head>
+<body>
+<h1>{{ .Info.Title }} <small>{{ .Info.Version }}</small></h1>
+<p>OpenAPI specification: <a href="openapi.json">openapi.json</a></p>
+{{ range $path, $ops := .Paths }}{{ range $method, $op := $ops }}
+<details>
+<summary><span class="method">{{ $method }}</span> {{ $path }}</summary>
+{{ with $op.Parameters }}<h4>Parameters</h4>
+<table><tr><th>Name</th><th>In</th><th>Description</th></tr>
+{{ range . }}<tr><td><code>{{ .Name }}</code>{{ if .Required }} *{{ end }}</td><td>{{ .In }}</td><td>{{ .Description }}</td></tr>
+{{ end }}</table>{{ end }}
+{{ with $op.RequestBody }}{{ with index .Content "multipart/form-data" }}<h4>Form fields</h4>
+<table><tr><th>Name</th><th>Type</th><th>Default</th><th>Description</th></tr>
+{{ $required := .Schema.Required }}{{ range $name, $field := .Schema.Properties }}<tr><td><code>{{ $name }}</code>{{ range $required }}{{ if eq . $name }} *{{ end }}{{ end }}</td><td>{{ $field.Type }}{{ with $field.Format }} ({{ . }}){{ end }}</td><td>{{ with $field.Default }}<code>{{ . }}</code>{{ end }}</td><td>{{ $field.Description }}</td></tr>
+{{ end }}</table>{{ end }}{{ end }}
+<h4>Responses</h4>
+<table><tr><th>Status</th><th>Description</th></tr>
+{{ range $status, $resp := $op.Responses }}<tr><td>{{ $status }}</td><td>{{ $resp.Description }}</td></tr>
+{{ end }}</table>
+</details>
+{{ end }}{{ end }}
+</body>
+</html>
+`))
+
+// renderOpenApi returns the JSON and HTML representations of an OpenAPI
+// document.
+func renderOpenApi(doc openApiDocument) ([]byte, []byte, error) {
+	spec, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal OpenAPI document: %w", err)
+	}
+
+	var page bytes.Buffer
+	err = openApiDocsTemplate.Execute(&page, doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("render OpenAPI documentation: %w", err)
+	}
+
+	return spec, page.Bytes(), nil
+}
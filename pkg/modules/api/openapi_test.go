@@ -0,0 +1,248 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestDescribeRoute(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		handler      echo.HandlerFunc
+		expectFields []formField
+		expectPanic  bool
+	}{
+		{
+			scenario: "handler stops on validate",
+			handler: func(c echo.Context) error {
+				ctx := c.Get("context").(*Context)
+
+				var (
+					url       string
+					landscape bool
+					waitDelay time.Duration
+					paths     []string
+				)
+				err := ctx.FormData().
+					MandatoryString("url", &url).
+					Bool("landscape", &landscape, true).
+					Duration("waitDelay", &waitDelay, time.Second).
+					MandatoryPaths([]string{".pdf"}, &paths).
+					Validate()
+				if err != nil {
+					return fmt.Errorf("validate form data: %w", err)
+				}
+
+				panic("handler should have stopped")
+			},
+			expectFields: []formField{
+				{Kind: valueField, Name: "url", Type: "string", Required: true},
+				{Kind: valueField, Name: "landscape", Type: "boolean", Default: true},
+				{Kind: valueField, Name: "waitDelay", Type: durationFieldType, Default: "1s"},
+				{Kind: filesField, Extensions: []string{".pdf"}, Required: true},
+			},
+		},
+		{
+			scenario: "same field read twice",
+			handler: func(c echo.Context) error {
+				ctx := c.Get("context").(*Context)
+
+				var watermark string
+				var watermarks []string
+				return ctx.FormData().
+					Watermark(&watermark).
+					Watermarks(&watermarks).
+					Validate()
+			},
+			expectFields: []formField{
+				{Kind: namedFilesField, Name: WatermarkFormField, Multiple: true},
+			},
+		},
+		{
+			scenario: "handler panics",
+			handler: func(c echo.Context) error {
+				ctx := c.Get("context").(*Context)
+
+				var foo string
+				ctx.FormData().Path("foo.txt", &foo)
+
+				panic("foo")
+			},
+			expectFields: []formField{
+				{Kind: fileField, Name: "foo.txt"},
+			},
+			expectPanic: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var logs bytes.Buffer
+			fields := describeRoute(Route{
+				Method:      http.MethodPost,
+				Path:        "/forms/foo",
+				IsMultipart: true,
+				Handler:     tc.handler,
+			}, slog.New(slog.NewTextHandler(&logs, nil)))
+
+			if !reflect.DeepEqual(fields, tc.expectFields) {
+				t.Errorf("expected fields %+v, but got %+v", tc.expectFields, fields)
+			}
+
+			// A panic must not go unnoticed.
+			if panicked := strings.Contains(logs.String(), "handler panicked"); panicked != tc.expectPanic {
+				t.Errorf("expected a logged panic %t, but got logs '%s'", tc.expectPanic, logs.String())
+			}
+		})
+	}
+}
+
+func TestOpenApiBuilder_Build(t *testing.T) {
+	builder := openApiBuilder{
+		rootPath:            "/foo/",
+		correlationIdHeader: "Gotenberg-Trace",
+		logger:              slog.New(slog.DiscardHandler),
+	}
+
+	doc := builder.build([]Route{
+		{
+			Method:      http.MethodPost,
+			Path:        "/forms/bar/convert",
+			IsMultipart: true,
+			Handler: func(c echo.Context) error {
+				ctx := c.Get("context").(*Context)
+
+				var index string
+				var scale float64
+				return ctx.FormData().
+					MandatoryPath("index.html", &index).
+					MandatoryFloat64("scale", &scale).
+					Validate()
+			},
+		},
+		{
+			Method: http.MethodGet,
+			Path:   "/bar/:id",
+			Handler: func(c echo.Context) error {
+				return nil
+			},
+		},
+	})
+
+	op, ok := doc.Paths["/foo/forms/bar/convert"]["post"]
+	if !ok {
+		t.Fatal("expected multipart route to be described")
+	}
+
+	if op.OperationId != "postFormsBarConvert" {
+		t.Errorf("expected operation ID 'postFormsBarConvert', but got '%s'", op.OperationId)
+	}
+
+	if !reflect.DeepEqual(op.Tags, []string{"bar"}) {
+		t.Errorf("expected tags [bar], but got %v", op.Tags)
+	}
+
+	if op.RequestBody == nil || !op.RequestBody.Required {
+		t.Fatal("expected a required request body")
+	}
+
+	for _, mediaType := range []string{echo.MIMEMultipartForm, echo.MIMEApplicationJSON} {
+		schema := op.RequestBody.Content[mediaType].Schema
+		if schema == nil {
+			t.Fatalf("expected a '%s' schema", mediaType)
+		}
+
+		if !reflect.DeepEqual(schema.Required, []string{"scale"}) {
+			t.Errorf("expected '%s' required fields [scale], but got %v", mediaType, schema.Required)
+		}
+
+		if !strings.Contains(schema.Properties[jsonFilesKey].Description, "index.html (required)") {
+			t.Errorf("expected '%s' files description to mention index.html, but got '%s'", mediaType, schema.Properties[jsonFilesKey].Description)
+		}
+	}
+
+	op, ok = doc.Paths["/foo/bar/{id}"]["get"]
+	if !ok {
+		t.Fatal("expected route with path parameter to be described")
+	}
+
+	if len(op.Parameters) != 1 || op.Parameters[0].Name != "id" || op.Parameters[0].In != "path" {
+		t.Errorf("expected an 'id' path parameter, but got %+v", op.Parameters)
+	}
+
+	for _, path := range []string{"/foo/health", "/foo/version", "/foo/openapi.json", "/foo/docs"} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("expected '%s' to be described", path)
+		}
+	}
+
+	if _, ok := doc.Paths["/foo/debug"]; ok {
+		t.Error("expected '/foo/debug' to not be described")
+	}
+
+	spec, page, err := renderOpenApi(doc)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !json.Valid(spec) {
+		t.Error("expected a valid JSON specification")
+	}
+
+	if !strings.Contains(string(page), "/foo/forms/bar/convert") {
+		t.Error("expected the documentation page to list the multipart route")
+	}
+}
+
+func TestOpenApiBuilder_Build_RouteDescription(t *testing.T) {
+	builder := openApiBuilder{
+		rootPath: "/",
+		logger:   slog.New(slog.DiscardHandler),
+	}
+
+	doc := builder.build([]Route{
+		{
+			Method:           http.MethodPost,
+			Path:             "/forms/bar",
+			IsMultipart:      true,
+			FormFieldSchemas: map[string]json.RawMessage{"steps": json.RawMessage(`{"type":"array","description":"Foo.","items":{"type":"string"}}`)},
+			FilesDescription: "Bar files.",
+			Handler: func(c echo.Context) error {
+				ctx := c.Get("context").(*Context)
+
+				var paths []string
+				return ctx.FormData().
+					MandatoryCustom("steps", func(value string) error { return nil }).
+					Paths([]string{".pdf"}, &paths).
+					Validate()
+			},
+		},
+	})
+
+	op, ok := doc.Paths["/forms/bar"]["post"]
+	if !ok {
+		t.Fatal("expected route to be described")
+	}
+
+	jsonSchema := op.RequestBody.Content[echo.MIMEApplicationJSON].Schema
+	steps := jsonSchema.Properties["steps"]
+	if steps.Type != "array" || steps.Items == nil || steps.Items.Type != "string" {
+		t.Errorf("expected the JSON schema of 'steps' to be an array of strings, but got %+v", steps)
+	}
+
+	multipartSchema := op.RequestBody.Content[echo.MIMEMultipartForm].Schema
+	if multipartSteps := multipartSchema.Properties["steps"]; multipartSteps.Type != "string" || multipartSteps.Description != "JSON-encoded. Foo." {
+		t.Errorf("expected a JSON-encoded string for 'steps', but got %+v", multipartSteps)
+	}
+
+	if files := multipartSchema.Properties[jsonFilesKey].Description; !strings.HasPrefix(files, "Bar files.") {
+		t.Errorf("expected the files description to start with 'Bar files.', but got '%s'", files)
+	}
+}
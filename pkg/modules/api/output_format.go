@@ -0,0 +1,180 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mholt/archives"
+)
+
+// OutputFormatFormField is the form field which selects the format of the
+// response when a request results in many output files. It takes precedence
+// over the "Accept" header.
+const OutputFormatFormField = "outputFormat"
+
+// outputFormat is the format of the response when a request results in many
+// output files.
+type outputFormat string
+
+const (
+	outputFormatZip       outputFormat = "zip"
+	outputFormatTar       outputFormat = "tar"
+	outputFormatTarGz     outputFormat = "tar.gz"
+	outputFormatMultipart outputFormat = "multipart"
+)
+
+// parseOutputFormat returns the output format from the form field value or,
+// if empty, from the "Accept" header. The first media type of the header
+// matching a format wins, regardless of its quality value; ZIP is the
+// default.
+func parseOutputFormat(formValue, accept string) (outputFormat, error) {
+	switch outputFormat(formValue) {
+	case outputFormatZip, outputFormatTar, outputFormatTarGz, outputFormatMultipart:
+		return outputFormat(formValue), nil
+	case "":
+	default:
+		return "", WrapError(
+			fmt.Errorf("unknown output format '%s'", formValue),
+			NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("Invalid '%s' form field value: want '%s', '%s', '%s' or '%s'", OutputFormatFormField, outputFormatZip, outputFormatTar, outputFormatTarGz, outputFormatMultipart),
+			),
+		)
+	}
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case "application/zip":
+			return outputFormatZip, nil
+		case "application/x-tar":
+			return outputFormatTar, nil
+		case "application/gzip", "application/x-gtar", "application/x-tar+gzip":
+			return outputFormatTarGz, nil
+		case "multipart/mixed":
+			return outputFormatMultipart, nil
+		}
+	}
+
+	return outputFormatZip, nil
+}
+
+// OutputExt returns the extension of an output path, including compound
+// extensions such as ".tar.gz".
+func OutputExt(outputPath string) string {
+	if strings.HasSuffix(outputPath, ".tar.gz") {
+		return ".tar.gz"
+	}
+
+	return filepath.Ext(outputPath)
+}
+
+// archiveOutputFiles writes the given output files into an archive at the
+// given path.
+func (ctx *Context) archiveOutputFiles(archiver archives.Archiver, path string, outputPaths []string) error {
+	filesInfo, err := archives.FilesFromDisk(ctx.Context, nil, func() map[string]string {
+		f := make(map[string]string)
+		for _, outputPath := range outputPaths {
+			f[outputPath] = ctx.OriginalFilename(outputPath)
+		}
+		return f
+	}())
+	if err != nil {
+		return fmt.Errorf("create files info: %w", err)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create archive file: %w", err)
+	}
+	defer func(out *os.File) {
+		err := out.Close()
+		if err != nil {
+			ctx.logger.ErrorContext(ctx, fmt.Sprintf("close archive file: %s", err))
+		}
+	}(out)
+
+	err = archiver.Archive(ctx.Context, out, filesInfo)
+	if err != nil {
+		return fmt.Errorf("archive output files: %w", err)
+	}
+
+	return nil
+}
+
+// writeMultipartOutputFiles writes the given output files as a
+// "multipart/mixed" body at the given path, one part per file with its
+// filename. It returns the content type, boundary included.
+func (ctx *Context) writeMultipartOutputFiles(path string, outputPaths []string) (string, error) {
+	out, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create multipart file: %w", err)
+	}
+	defer func(out *os.File) {
+		err := out.Close()
+		if err != nil {
+			ctx.logger.ErrorContext(ctx, fmt.Sprintf("close multipart file: %s", err))
+		}
+	}(out)
+
+	writer := multipart.NewWriter(out)
+
+	for _, outputPath := range outputPaths {
+		filename := ctx.OriginalFilename(outputPath)
+
+		contentType := mime.TypeByExtension(filepath.Ext(filename))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return "", fmt.Errorf("create part for '%s': %w", filename, err)
+		}
+
+		err = copyToPart(part, outputPath)
+		if err != nil {
+			return "", fmt.Errorf("write part for '%s': %w", filename, err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return "", fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	return mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": writer.Boundary()}), nil
+}
+
+// copyToPart copies a file into a part of a "multipart/mixed" body.
+func copyToPart(part io.Writer, path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	_, err = io.Copy(part, in)
+	if err != nil {
+		return fmt.Errorf("copy file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	for _, tc := range []struct {
+		scenario  string
+		formValue string
+		accept    string
+		expect    outputFormat
+		expectErr bool
+	}{
+		{scenario: "default", expect: outputFormatZip},
+		{scenario: "form field", formValue: "tar", expect: outputFormatTar},
+		{scenario: "form field over Accept header", formValue: "tar.gz", accept: "multipart/mixed", expect: outputFormatTarGz},
+		{scenario: "invalid form field", formValue: "rar", expectErr: true},
+		{scenario: "Accept header", accept: "multipart/mixed", expect: outputFormatMultipart},
+		{scenario: "Accept header with gzip", accept: "application/gzip", expect: outputFormatTarGz},
+		{scenario: "first known media type wins", accept: "application/pdf, application/x-tar;q=0.5, application/zip", expect: outputFormatTar},
+		{scenario: "any media type", accept: "*/*", expect: outputFormatZip},
+		{scenario: "malformed Accept header", accept: ";;;", expect: outputFormatZip},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			format, err := parseOutputFormat(tc.formValue, tc.accept)
+
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				status, _ := ParseError(err)
+				if status != http.StatusBadRequest {
+					t.Errorf("expected status %d, but got %d", http.StatusBadRequest, status)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if format != tc.expect {
+				t.Errorf("expected format '%s', but got '%s'", tc.expect, format)
+			}
+		})
+	}
+}
+
+func TestContext_BuildOutputFile_Formats(t *testing.T) {
+	newOutputContext := func(t *testing.T, format outputFormat) *Context {
+		t.Helper()
+
+		dirPath := t.TempDir()
+		ctx := &Context{
+			dirPath:        dirPath,
+			outputFormat:   format,
+			diskToOriginal: make(map[string]string),
+			logger:         slog.New(slog.DiscardHandler),
+			Context:        context.Background(),
+		}
+
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+		c.Set("outputFilename", "foo")
+		ctx.echoCtx = c
+
+		for _, filename := range []string{"a.pdf", "b.pdf"} {
+			path := ctx.GeneratePathFromFilename(filename)
+			err := os.WriteFile(path, []byte(filename), 0o600)
+			if err != nil {
+				t.Fatalf("write %s: %v", path, err)
+			}
+			ctx.outputPaths = append(ctx.outputPaths, path)
+		}
+
+		return ctx
+	}
+
+	expectNames := []string{"a.pdf", "b.pdf", manifestFilename}
+	checkNames := func(t *testing.T, names []string) {
+		t.Helper()
+		sort.Strings(names)
+		if !slices.Equal(names, expectNames) {
+			t.Errorf("expected files %v, but got %v", expectNames, names)
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario       string
+		format         outputFormat
+		expectFilename string
+		expectType     string
+		read           func(t *testing.T, path, contentType string) []string
+	}{
+		{
+			scenario:       "zip",
+			format:         outputFormatZip,
+			expectFilename: "foo.zip",
+			expectType:     "application/zip",
+			read: func(t *testing.T, path, _ string) []string {
+				r, err := zip.OpenReader(path)
+				if err != nil {
+					t.Fatalf("open zip: %v", err)
+				}
+				defer func() {
+					_ = r.Close()
+				}()
+
+				var names []string
+				for _, f := range r.File {
+					names = append(names, f.Name)
+				}
+				return names
+			},
+		},
+		{
+			scenario:       "tar",
+			format:         outputFormatTar,
+			expectFilename: "foo.tar",
+			expectType:     "application/x-tar",
+			read: func(t *testing.T, path, _ string) []string {
+				f, err := os.Open(path)
+				if err != nil {
+					t.Fatalf("open tar: %v", err)
+				}
+				defer func() {
+					_ = f.Close()
+				}()
+
+				return tarNames(t, f)
+			},
+		},
+		{
+			scenario:       "tar.gz",
+			format:         outputFormatTarGz,
+			expectFilename: "foo.tar.gz",
+			expectType:     "application/gzip",
+			read: func(t *testing.T, path, _ string) []string {
+				f, err := os.Open(path)
+				if err != nil {
+					t.Fatalf("open tar.gz: %v", err)
+				}
+				defer func() {
+					_ = f.Close()
+				}()
+
+				gz, err := gzip.NewReader(f)
+				if err != nil {
+					t.Fatalf("open gzip: %v", err)
+				}
+
+				return tarNames(t, gz)
+			},
+		},
+		{
+			scenario:       "multipart",
+			format:         outputFormatMultipart,
+			expectFilename: "foo.multipart",
+			read: func(t *testing.T, path, contentType string) []string {
+				mediaType, params, err := mime.ParseMediaType(contentType)
+				if err != nil || mediaType != "multipart/mixed" {
+					t.Fatalf("expected a multipart/mixed content type, but got '%s'", contentType)
+				}
+
+				f, err := os.Open(path)
+				if err != nil {
+					t.Fatalf("open multipart: %v", err)
+				}
+				defer func() {
+					_ = f.Close()
+				}()
+
+				var names []string
+				r := multipart.NewReader(f, params["boundary"])
+				for {
+					part, err := r.NextPart()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatalf("read part: %v", err)
+					}
+
+					b, err := io.ReadAll(part)
+					if err != nil {
+						t.Fatalf("read part content: %v", err)
+					}
+					if part.FileName() != manifestFilename && string(b) != part.FileName() {
+						t.Errorf("expected content '%s', but got '%s'", part.FileName(), string(b))
+					}
+
+					names = append(names, part.FileName())
+				}
+				return names
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := newOutputContext(t, tc.format)
+
+			outputPath, err := ctx.BuildOutputFile()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			contentType := ctx.OutputContentType(outputPath)
+			if tc.expectType != "" && contentType != tc.expectType {
+				t.Errorf("expected content type '%s', but got '%s'", tc.expectType, contentType)
+			}
+
+			if filename := ctx.OutputFilename(outputPath); filename != tc.expectFilename {
+				t.Errorf("expected filename '%s', but got '%s'", tc.expectFilename, filename)
+			}
+
+			checkNames(t, tc.read(t, outputPath, contentType))
+		})
+	}
+
+	// A single output file has no specific content type.
+	ctx := newOutputContext(t, outputFormatTar)
+	ctx.outputPaths = ctx.outputPaths[:1]
+	outputPath, err := ctx.BuildOutputFile()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	if filepath.Ext(outputPath) != ".pdf" || ctx.OutputContentType(outputPath) != "" {
+		t.Errorf("expected the single output file as is, but got '%s' (%s)", outputPath, ctx.OutputContentType(outputPath))
+	}
+}
+
+func tarNames(t *testing.T, r io.Reader) []string {
+	t.Helper()
+
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read tar: %v", err)
+		}
+		names = append(names, header.Name)
+	}
+	return names
+}
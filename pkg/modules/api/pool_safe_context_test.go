@@ -1,4 +1,4 @@
-package webhook
+package api
 
 import (
 	"log/slog"
@@ -19,7 +19,7 @@ func TestPoolSafeContext_SurvivesUnderlyingReset(t *testing.T) {
 	c.Set("logger", logger)
 	c.Set("correlationId", "abc-123")
 
-	detached := newPoolSafeContext(c, "logger", "correlationId", "missing")
+	detached := NewPoolSafeContext(c, "logger", "correlationId", "missing")
 
 	// Simulate Echo recycling c for a concurrent request. Reset wipes the
 	// shared store, which is exactly the crash scenario the wrapper
@@ -48,7 +48,7 @@ func TestPoolSafeContext_SetDoesNotTouchUnderlying(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	detached := newPoolSafeContext(c)
+	detached := NewPoolSafeContext(c)
 	detached.Set("foo", "bar")
 
 	if got, _ := detached.Get("foo").(string); got != "bar" {
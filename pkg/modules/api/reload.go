@@ -0,0 +1,139 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dlclark/regexp2"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// reloadableSettings gathers the settings which the module applies again on
+// reload. The middlewares load a snapshot of them per request, so that a
+// request never sees a mix of old and new settings.
+type reloadableSettings struct {
+	basicAuthUsername     string
+	basicAuthPassword     string
+	highPriorityAllowList []*regexp2.Regexp
+	highPriorityDenyList  []*regexp2.Regexp
+	downloadFromCfg       downloadFromConfig
+	uploadToCfg           uploadToConfig
+}
+
+// newReloadableSettings reads the reloadable settings from the flags and, for
+// the basic authentication credentials, from the environment.
+func newReloadableSettings(flags gotenberg.ParsedFlags) (*reloadableSettings, error) {
+	settings := &reloadableSettings{
+		highPriorityAllowList: flags.MustRegexpSlice("api-high-priority-allow-list"),
+		highPriorityDenyList:  flags.MustRegexpSlice("api-high-priority-deny-list"),
+		downloadFromCfg: downloadFromConfig{
+			allowList:              flags.MustRegexpSlice("api-download-from-allow-list"),
+			denyList:               flags.MustRegexpSlice("api-download-from-deny-list"),
+			denyPrivateIPs:         flags.MustBool("api-download-from-deny-private-ips"),
+			denyPublicIPs:          flags.MustBool("api-download-from-deny-public-ips"),
+			enableEnvironmentProxy: flags.MustBool("api-download-from-enable-environment-proxy"),
+			maxRetry:               flags.MustInt("api-download-from-max-retry"),
+			disable:                flags.MustBool("api-disable-download-from"),
+		},
+		uploadToCfg: uploadToConfig{
+			allowList:              flags.MustRegexpSlice("api-upload-to-allow-list"),
+			denyList:               flags.MustRegexpSlice("api-upload-to-deny-list"),
+			denyPrivateIPs:         flags.MustBool("api-upload-to-deny-private-ips"),
+			denyPublicIPs:          flags.MustBool("api-upload-to-deny-public-ips"),
+			enableEnvironmentProxy: flags.MustBool("api-upload-to-enable-environment-proxy"),
+			maxRetry:               flags.MustInt("api-upload-to-max-retry"),
+			disable:                flags.MustBool("api-disable-upload-to"),
+		},
+	}
+
+	// Enable basic auth?
+	enableBasicAuth := flags.MustBool("api-enable-basic-auth")
+	if enableBasicAuth {
+		basicAuthUsername, err := secretEnv("GOTENBERG_API_BASIC_AUTH_USERNAME")
+		if err != nil {
+			return nil, fmt.Errorf("get basic auth username from env: %w", err)
+		}
+		basicAuthPassword, err := secretEnv("GOTENBERG_API_BASIC_AUTH_PASSWORD")
+		if err != nil {
+			return nil, fmt.Errorf("get basic auth password from env: %w", err)
+		}
+		settings.basicAuthUsername = basicAuthUsername
+		settings.basicAuthPassword = basicAuthPassword
+	}
+
+	return settings, nil
+}
+
+// validate validates the reloadable settings.
+func (s *reloadableSettings) validate() error {
+	var err error
+
+	if s.downloadFromCfg.enableEnvironmentProxy {
+		proxyErr := gotenberg.ValidateEnvironmentProxyVariables()
+		if proxyErr != nil {
+			err = errors.Join(err, fmt.Errorf("--api-download-from-enable-environment-proxy is set: %w", proxyErr))
+		}
+	}
+
+	if s.uploadToCfg.enableEnvironmentProxy {
+		proxyErr := gotenberg.ValidateEnvironmentProxyVariables()
+		if proxyErr != nil {
+			err = errors.Join(err, fmt.Errorf("--api-upload-to-enable-environment-proxy is set: %w", proxyErr))
+		}
+	}
+
+	return err
+}
+
+// secretEnv retrieves a secret from the file named by the environment
+// variable key suffixed by "_FILE" (e.g., a Docker secret), or from the
+// environment variable key itself. As the environment of a process does not
+// change, the file is the only way to rotate a secret on reload.
+func secretEnv(key string) (string, error) {
+	path, ok := os.LookupEnv(key + "_FILE")
+	if !ok || path == "" {
+		return gotenberg.StringEnv(key)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read file from environment variable '%s_FILE': %w", key, err)
+	}
+
+	val := strings.TrimRight(string(b), "\r\n")
+	if val == "" {
+		return "", fmt.Errorf("file from environment variable '%s_FILE' is empty", key)
+	}
+
+	return val, nil
+}
+
+// Reload applies the new outbound allow/deny lists, IP-class options, high
+// priority lists and basic authentication credentials. In-flight requests
+// keep the settings they started with. Enabling or disabling basic
+// authentication still requires a restart, as it changes the middlewares
+// chain.
+func (a *Api) Reload(flags gotenberg.ParsedFlags) error {
+	settings, err := newReloadableSettings(flags)
+	if err != nil {
+		return err
+	}
+
+	current := a.settings.Load()
+	if (current.basicAuthUsername == "") != (settings.basicAuthUsername == "") {
+		return errors.New("enabling or disabling basic authentication requires a restart")
+	}
+
+	err = settings.validate()
+	if err != nil {
+		return err
+	}
+
+	a.settings.Store(settings)
+	a.logger.Info("reloaded outbound allow/deny lists, high priority lists and basic authentication credentials")
+
+	return nil
+}
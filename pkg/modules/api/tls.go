@@ -0,0 +1,70 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// clientAuthRequire requires a client certificate signed by the client CA.
+	clientAuthRequire = "require"
+
+	// clientAuthOptional verifies the client certificate only if the client
+	// sends one.
+	clientAuthOptional = "optional"
+)
+
+// buildMutualTlsConfig returns a [tls.Config] for the server certificate which
+// verifies client certificates against the given CA bundle.
+func buildMutualTlsConfig(certFile, keyFile, clientCaFile, clientAuth string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate: %w", err)
+	}
+
+	b, err := os.ReadFile(clientCaFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, errors.New("no PEM certificate found in the client CA file")
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+		// Keep HTTP/2 support, as with StartTLS.
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+
+	if clientAuth == clientAuthOptional {
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return cfg, nil
+}
+
+// clientCertIdentities returns the subject and the SANs of a client
+// certificate, i.e., the values matched against the allow list.
+func clientCertIdentities(cert *x509.Certificate) []string {
+	identities := []string{cert.Subject.String()}
+	identities = append(identities, cert.DNSNames...)
+	identities = append(identities, cert.EmailAddresses...)
+
+	for _, ip := range cert.IPAddresses {
+		identities = append(identities, ip.String())
+	}
+
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+
+	return identities
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestCertificate generates a certificate, self-signed when parent is nil,
+// and writes it with its key as PEM files.
+func newTestCertificate(t *testing.T, dirPath, name string, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dirPath, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatalf("write certificate: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dirPath, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600)
+	if err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	return cert, key
+}
+
+func TestBuildMutualTlsConfig(t *testing.T) {
+	dirPath := t.TempDir()
+
+	ca, caKey := newTestCertificate(t, dirPath, "ca", &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	newTestCertificate(t, dirPath, "server", &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+
+	err := os.WriteFile(filepath.Join(dirPath, "empty.crt"), []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario       string
+		clientCaFile   string
+		clientAuth     string
+		wantClientAuth tls.ClientAuthType
+		wantErr        bool
+	}{
+		{"require", "ca.crt", clientAuthRequire, tls.RequireAndVerifyClientCert, false},
+		{"optional", "ca.crt", clientAuthOptional, tls.VerifyClientCertIfGiven, false},
+		{"missing CA file", "foo.crt", clientAuthRequire, tls.NoClientCert, true},
+		{"no PEM certificate", "empty.crt", clientAuthRequire, tls.NoClientCert, true},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			cfg, err := buildMutualTlsConfig(
+				filepath.Join(dirPath, "server.crt"),
+				filepath.Join(dirPath, "server.key"),
+				filepath.Join(dirPath, tc.clientCaFile),
+				tc.clientAuth,
+			)
+
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if cfg.ClientAuth != tc.wantClientAuth {
+				t.Fatalf("client auth = %v, want %v", cfg.ClientAuth, tc.wantClientAuth)
+			}
+		})
+	}
+}
+
+func TestClientCertIdentities(t *testing.T) {
+	uri, err := url.Parse("spiffe://example.org/billing")
+	if err != nil {
+		t.Fatalf("parse URI: %v", err)
+	}
+
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "billing", Organization: []string{"Example"}},
+		DNSNames:       []string{"billing.internal"},
+		EmailAddresses: []string{"billing@example.org"},
+		URIs:           []*url.URL{uri},
+	}
+
+	want := []string{"CN=billing,O=Example", "billing.internal", "billing@example.org", "spiffe://example.org/billing"}
+	got := clientCertIdentities(cert)
+
+	if len(got) != len(want) {
+		t.Fatalf("identities = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("identities = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dlclark/regexp2"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// UploadToFormField is the form field listing the destinations where the
+// output files are uploaded instead of being sent in the response.
+const UploadToFormField = "uploadTo"
+
+type uploadToConfig struct {
+	allowList              []*regexp2.Regexp
+	denyList               []*regexp2.Regexp
+	denyPrivateIPs         bool
+	denyPublicIPs          bool
+	enableEnvironmentProxy bool
+	maxRetry               int
+	disable                bool
+}
+
+type uploadTo struct {
+	// Url is the URL to upload an output file to, e.g., a presigned object
+	// store URL.
+	Url string `json:"url"`
+
+	// Method is the HTTP method of the upload, either "PUT" (default) or
+	// "POST".
+	Method string `json:"method"`
+
+	// ExtraHttpHeaders are the HTTP headers to send alongside.
+	ExtraHttpHeaders map[string]string `json:"extraHttpHeaders"`
+}
+
+// uploadToResult is an entry of the JSON summary returned once the output
+// files have been uploaded.
+type uploadToResult struct {
+	Filename string `json:"filename"`
+	Url      string `json:"url"`
+	Method   string `json:"method"`
+	Status   int    `json:"status"`
+	Size     int64  `json:"size"`
+}
+
+// parseUploadTo parses and validates the "uploadTo" form field, if any. It
+// filters the destinations early, so that a request does not go through a
+// conversion only to fail on a forbidden URL.
+func parseUploadTo(ctx *Context, cfg uploadToConfig) ([]uploadTo, error) {
+	raw, ok := ctx.values[UploadToFormField]
+	if !ok || len(raw) == 0 || strings.TrimSpace(raw[0]) == "" {
+		return nil, nil
+	}
+
+	if cfg.disable {
+		return nil, WrapError(
+			errors.New("upload to feature is disabled"),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' form field value: the upload to feature is disabled", UploadToFormField)),
+		)
+	}
+
+	var dsts []uploadTo
+	err := json.Unmarshal([]byte(raw[0]), &dsts)
+	if err != nil {
+		return nil, WrapError(
+			fmt.Errorf("unmarshal json: %w", err),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' form field value: %s", UploadToFormField, err)),
+		)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		// Should not happen, as context is created with a timeout.
+		return nil, errors.New("context has no deadline")
+	}
+
+	for i, dst := range dsts {
+		if strings.TrimSpace(dst.Url) == "" {
+			return nil, WrapError(
+				errors.New("empty upload to URL"),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' form field entry %d: URL must be set", UploadToFormField, i)),
+			)
+		}
+
+		method := strings.ToUpper(strings.TrimSpace(dst.Method))
+		switch method {
+		case "":
+			method = http.MethodPut
+		case http.MethodPut, http.MethodPost:
+		default:
+			return nil, WrapError(
+				fmt.Errorf("unsupported upload to method '%s'", dst.Method),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' form field entry %d: method must be 'PUT' or 'POST', but got '%s'", UploadToFormField, i, dst.Method)),
+			)
+		}
+		dsts[i].Method = method
+
+		err = gotenberg.FilterOutboundURL(ctx, dst.Url, cfg.allowList, cfg.denyList, deadline, cfg.ipOptions()...)
+		if err != nil {
+			return nil, fmt.Errorf("filter URL: %w", err)
+		}
+	}
+
+	return dsts, nil
+}
+
+func (cfg uploadToConfig) ipOptions() []gotenberg.DecideOption {
+	return []gotenberg.DecideOption{
+		gotenberg.WithDenyPrivateIPs(cfg.denyPrivateIPs),
+		gotenberg.WithDenyPublicIPs(cfg.denyPublicIPs),
+	}
+}
+
+// HasUploadTo tells if the output files are uploaded to the destinations
+// listed in the "uploadTo" form field instead of being sent in the response.
+func (ctx *Context) HasUploadTo() bool {
+	return len(ctx.uploadTo) > 0
+}
+
+// uploadOutputFiles uploads the output files to the destinations listed in
+// the "uploadTo" form field and sends a JSON summary in the response. A
+// single destination receives the output file as built by
+// [Context.BuildOutputFile], i.e., an archive if many output files. Otherwise,
+// each destination receives the output file at the same position.
+func uploadOutputFiles(c echo.Context, ctx *Context, cfg uploadToConfig) error {
+	if len(ctx.outputPaths) == 0 {
+		return errors.New("no output path")
+	}
+
+	var paths []string
+	if len(ctx.uploadTo) == 1 {
+		outputPath, err := ctx.BuildOutputFile()
+		if err != nil {
+			return fmt.Errorf("build output file: %w", err)
+		}
+		paths = []string{outputPath}
+	} else {
+		if len(ctx.uploadTo) != len(ctx.outputPaths) {
+			return WrapError(
+				fmt.Errorf("%d upload to destinations for %d output files", len(ctx.uploadTo), len(ctx.outputPaths)),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' form field value: got %d destinations for %d output files, want 1 or %d", UploadToFormField, len(ctx.uploadTo), len(ctx.outputPaths), len(ctx.outputPaths))),
+			)
+		}
+		paths = ctx.outputPaths
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return errors.New("context has no deadline")
+	}
+
+	client := &retryablehttp.Client{
+		HTTPClient:   gotenberg.NewOutboundHttpClient(time.Until(deadline), cfg.allowList, cfg.denyList, cfg.enableEnvironmentProxy, cfg.ipOptions()...),
+		RetryMax:     cfg.maxRetry,
+		RetryWaitMin: time.Duration(1) * time.Second,
+		RetryWaitMax: time.Until(deadline),
+		Logger:       gotenberg.NewLeveledLogger(ctx.Log()),
+		CheckRetry:   retryablehttp.DefaultRetryPolicy,
+		Backoff:      retryablehttp.DefaultBackoff,
+	}
+
+	results := make([]uploadToResult, len(paths))
+	for i, path := range paths {
+		var filename string
+		if len(paths) == 1 {
+			filename = ctx.OutputFilename(path)
+		} else {
+			filename = ctx.OriginalFilename(path)
+		}
+
+		result, err := uploadOutputFile(ctx, c, client, ctx.uploadTo[i], path, filename)
+		if err != nil {
+			return err
+		}
+		results[i] = result
+	}
+
+	err := c.JSON(http.StatusOK, results)
+	if err != nil {
+		return fmt.Errorf("send response: %w", err)
+	}
+
+	return nil
+}
+
+func uploadOutputFile(ctx *Context, c echo.Context, client *retryablehttp.Client, dst uploadTo, path, filename string) (uploadToResult, error) {
+	uploadCtx, span := gotenberg.Tracer().Start(ctx, fmt.Sprintf("%s Upload To", dst.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.ServerAddress(redactUrl(dst.Url))),
+	)
+	defer span.End()
+
+	fail := func(err error) (uploadToResult, error) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return uploadToResult{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fail(fmt.Errorf("open output file: %w", err))
+	}
+	defer func() {
+		err := f.Close()
+		if err != nil {
+			ctx.Log().ErrorContext(uploadCtx, fmt.Sprintf("close output file: %s", err))
+		}
+	}()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return fail(fmt.Errorf("get stat from output file: %w", err))
+	}
+
+	header := make([]byte, 512)
+	n, err := f.Read(header)
+	if err != nil && stat.Size() > 0 {
+		return fail(fmt.Errorf("read header of output file: %w", err))
+	}
+
+	contentType := ctx.OutputContentType(path)
+	if contentType == "" {
+		contentType = http.DetectContentType(header[:n])
+	}
+
+	ctx.Log().DebugContext(uploadCtx, fmt.Sprintf("upload '%s' to '%s'", filename, redactUrl(dst.Url)))
+
+	// The request rewinds the file on each attempt.
+	req, err := retryablehttp.NewRequestWithContext(uploadCtx, dst.Method, dst.Url, f)
+	if err != nil {
+		return fail(fmt.Errorf("create request to '%s': %w", redactUrl(dst.Url), err))
+	}
+
+	// Presigned URLs usually require a known content length.
+	req.ContentLength = stat.Size()
+	req.Header.Set("User-Agent", "Gotenberg")
+	req.Header.Set(echo.HeaderContentType, contentType)
+	if !strings.HasPrefix(contentType, "multipart/") {
+		req.Header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	}
+	digest, err := ctx.OutputDigest(path)
+	if err != nil {
+		return fail(fmt.Errorf("compute content digest: %w", err))
+	}
+	req.Header.Set(ContentDigestHeader, digest)
+	for key, value := range dst.ExtraHttpHeaders {
+		req.Header.Set(key, value)
+	}
+
+	// Inject OTEL trace context into outbound request.
+	otel.GetTextMapPropagator().Inject(uploadCtx, propagation.HeaderCarrier(req.Header))
+
+	// Propagate correlation ID header.
+	if correlationIdHeader, ok := c.Get("correlationIdHeader").(string); ok {
+		if correlationId, ok := c.Get("correlationId").(string); ok {
+			req.Header.Set(correlationIdHeader, correlationId)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fail(WrapError(
+			fmt.Errorf("upload output file to '%s': %w", redactUrl(dst.Url), err),
+			NewSentinelHttpError(http.StatusBadGateway, fmt.Sprintf("Unable to upload '%s' to '%s'", filename, redactUrl(dst.Url))),
+		))
+	}
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			ctx.Log().ErrorContext(uploadCtx, fmt.Sprintf("close response body from '%s': %s", redactUrl(dst.Url), err))
+		}
+	}()
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fail(WrapError(
+			fmt.Errorf("upload output file to '%s': got status: '%s'", redactUrl(dst.Url), resp.Status),
+			NewSentinelHttpError(http.StatusBadGateway, fmt.Sprintf("Unable to upload '%s' to '%s': got status: '%s'", filename, redactUrl(dst.Url), resp.Status)),
+		))
+	}
+
+	return uploadToResult{
+		Filename: filename,
+		Url:      redactUrl(dst.Url),
+		Method:   dst.Method,
+		Status:   resp.StatusCode,
+		Size:     stat.Size(),
+	}, nil
+}
+
+// redactUrl strips the query string and the user information of a URL, as
+// presigned URLs carry their credentials there.
+func redactUrl(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u.String()
+}
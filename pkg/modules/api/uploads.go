@@ -0,0 +1,553 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// UploadsFormField is the form field which lists, as a JSON array, the IDs of
+// completed resumable uploads to use as input files, e.g., ["<id>"].
+const UploadsFormField = "uploads"
+
+const (
+	// tusResumable is the version of the tus protocol the resumable uploads
+	// follow.
+	tusResumable = "1.0.0"
+
+	// uploadChunkContentType is the content type of a chunk.
+	uploadChunkContentType = "application/offset+octet-stream"
+
+	// uploadSweepInterval is the duration between two sweeps of the expired
+	// uploads.
+	uploadSweepInterval = time.Minute
+)
+
+// upload is a resumable upload. Its chunks are written in a single file
+// within its own directory of the working directory.
+type upload struct {
+	id           string
+	dirPath      string
+	path         string
+	filename     string
+	length       int64
+	offset       int64
+	lastActivity time.Time
+	writing      bool
+}
+
+// complete tells if all the chunks have been received.
+func (u upload) complete() bool {
+	return u.offset == u.length
+}
+
+// uploadStore keeps the resumable uploads in memory, and their content in the
+// working directory of the [gotenberg.FileSystem]. An upload expires after a
+// given duration without activity, and is swept in the background.
+type uploadStore struct {
+	fs         *gotenberg.FileSystem
+	maxSize    int64
+	maxUploads int
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	uploads map[string]*upload
+	now     func() time.Time
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newUploadStore returns an [uploadStore]. A zero maxSize means no limit on
+// the size of an upload, and a zero maxUploads no limit on their number.
+func newUploadStore(fs *gotenberg.FileSystem, maxSize int64, maxUploads int, ttl time.Duration) *uploadStore {
+	return &uploadStore{
+		fs:         fs,
+		maxSize:    maxSize,
+		maxUploads: maxUploads,
+		ttl:        ttl,
+		uploads:    make(map[string]*upload),
+		now:        time.Now,
+		done:       make(chan struct{}),
+	}
+}
+
+// start sweeps the expired uploads periodically, until the store is closed.
+func (s *uploadStore) start() {
+	go func() {
+		ticker := time.NewTicker(uploadSweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.done:
+				return
+			case now := <-ticker.C:
+				s.mu.Lock()
+				s.sweep(now)
+				s.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// close stops the sweeps, and removes the uploads and their content.
+func (s *uploadStore) close() error {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	for id, u := range s.uploads {
+		delete(s.uploads, id)
+		err = errors.Join(err, os.RemoveAll(u.dirPath))
+	}
+
+	return err
+}
+
+// uploadNotFoundError returns the error for unknown or expired uploads.
+func uploadNotFoundError(id string) error {
+	return WrapError(
+		fmt.Errorf("upload '%s' not found", id),
+		NewSentinelHttpError(http.StatusNotFound, fmt.Sprintf("Upload '%s' does not exist or has expired", id)),
+	)
+}
+
+// create registers a new upload of the given length.
+func (s *uploadStore) create(length int64, filename string) (upload, error) {
+	if s.maxSize > 0 && length > s.maxSize {
+		return upload{}, WrapError(
+			fmt.Errorf("upload length limit reached (%d > %d)", length, s.maxSize),
+			NewSentinelHttpError(http.StatusRequestEntityTooLarge, "The upload length exceeds the configured size limit. Increase it with --api-body-limit, or upload a smaller file."),
+		)
+	}
+
+	dirPath, err := s.fs.MkdirAll()
+	if err != nil {
+		return upload{}, fmt.Errorf("create upload directory: %w", err)
+	}
+
+	path := fmt.Sprintf("%s/%s", dirPath, uuid.NewString())
+	f, err := os.Create(path)
+	if err != nil {
+		return upload{}, fmt.Errorf("create upload file: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return upload{}, fmt.Errorf("close upload file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.maxUploads > 0 && len(s.uploads) >= s.maxUploads {
+		// The expired uploads may not have been swept yet.
+		s.sweep(now)
+
+		if len(s.uploads) >= s.maxUploads {
+			return upload{}, errors.Join(
+				WrapError(
+					fmt.Errorf("upload count limit reached (%d)", s.maxUploads),
+					NewSentinelHttpError(http.StatusTooManyRequests, "Too many uploads in progress. Retry later, or increase the limit with --api-uploads-max-count."),
+				),
+				os.RemoveAll(dirPath),
+			)
+		}
+	}
+
+	u := &upload{
+		id:           uuid.NewString(),
+		dirPath:      dirPath,
+		path:         path,
+		filename:     filename,
+		length:       length,
+		lastActivity: now,
+	}
+	s.uploads[u.id] = u
+
+	return *u, nil
+}
+
+// get returns the upload with the given ID. Expired uploads are reported as
+// missing, even if not swept yet.
+func (s *uploadStore) get(id string) (upload, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.lookup(id)
+	if !ok {
+		return upload{}, false
+	}
+
+	return *u, true
+}
+
+// write writes a chunk at the given offset and returns the updated upload.
+// If the chunk breaks off, the offset still accounts for the bytes written,
+// so that the client may resume from there.
+func (s *uploadStore) write(id string, offset int64, r io.Reader) (upload, error) {
+	s.mu.Lock()
+	u, ok := s.lookup(id)
+	if !ok {
+		s.mu.Unlock()
+		return upload{}, uploadNotFoundError(id)
+	}
+
+	if u.writing {
+		s.mu.Unlock()
+		return upload{}, WrapError(
+			fmt.Errorf("upload '%s' already receiving a chunk", id),
+			NewSentinelHttpError(http.StatusConflict, fmt.Sprintf("Upload '%s' is already receiving a chunk", id)),
+		)
+	}
+
+	if offset != u.offset {
+		s.mu.Unlock()
+		return upload{}, WrapError(
+			fmt.Errorf("upload '%s' offset mismatch (%d != %d)", id, offset, u.offset),
+			NewSentinelHttpError(http.StatusConflict, fmt.Sprintf("Invalid 'Upload-Offset' header value: want %d", u.offset)),
+		)
+	}
+
+	u.writing = true
+	remaining := u.length - u.offset
+	s.mu.Unlock()
+
+	n, err := writeChunk(u.path, offset, io.LimitReader(r, remaining))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u.writing = false
+	u.offset += n
+	u.lastActivity = s.now()
+
+	if err != nil {
+		return *u, fmt.Errorf("write chunk: %w", err)
+	}
+
+	if n == remaining {
+		// Anything left is beyond the upload length.
+		extra, _ := r.Read(make([]byte, 1))
+		if extra > 0 {
+			return *u, WrapError(
+				fmt.Errorf("upload '%s' chunk exceeds the upload length", id),
+				NewSentinelHttpError(http.StatusRequestEntityTooLarge, fmt.Sprintf("The chunk exceeds the upload length of %d bytes", u.length)),
+			)
+		}
+	}
+
+	return *u, nil
+}
+
+// writeChunk writes the content of a reader at the given offset of a file.
+func writeChunk(path string, offset int64, r io.Reader) (int64, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("open upload file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	_, err = f.Seek(offset, io.SeekStart)
+	if err != nil {
+		return 0, fmt.Errorf("seek upload file: %w", err)
+	}
+
+	n, err := io.Copy(f, r)
+	if err != nil {
+		return n, fmt.Errorf("copy chunk: %w", err)
+	}
+
+	return n, nil
+}
+
+// remove removes an upload and its content. It returns false if there is no
+// such upload.
+func (s *uploadStore) remove(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.lookup(id)
+	if !ok {
+		return false, nil
+	}
+
+	if u.writing {
+		return true, WrapError(
+			fmt.Errorf("upload '%s' receiving a chunk", id),
+			NewSentinelHttpError(http.StatusConflict, fmt.Sprintf("Upload '%s' is receiving a chunk", id)),
+		)
+	}
+
+	delete(s.uploads, id)
+
+	err := os.RemoveAll(u.dirPath)
+	if err != nil {
+		return true, fmt.Errorf("remove upload directory: %w", err)
+	}
+
+	return true, nil
+}
+
+// link makes the content of a completed upload available within the given
+// directory, with a hard link if possible. It returns the upload and the path
+// of its content. The upload remains available for other requests until it
+// expires or is removed.
+func (s *uploadStore) link(id, dirPath string) (upload, string, error) {
+	s.mu.Lock()
+	u, ok := s.lookup(id)
+	if !ok {
+		s.mu.Unlock()
+		return upload{}, "", WrapError(
+			fmt.Errorf("upload '%s' not found", id),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' form field value: upload '%s' does not exist or has expired", UploadsFormField, id)),
+		)
+	}
+
+	if !u.complete() || u.writing {
+		s.mu.Unlock()
+		return upload{}, "", WrapError(
+			fmt.Errorf("upload '%s' not complete", id),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' form field value: upload '%s' is not complete", UploadsFormField, id)),
+		)
+	}
+
+	u.lastActivity = s.now()
+	linked := *u
+
+	// Use a UUID-based name on disk to avoid filesystem NAME_MAX limits with
+	// long filenames.
+	// See: https://github.com/gotenberg/gotenberg/issues/1500.
+	path := fmt.Sprintf("%s/%s%s", dirPath, uuid.NewString(), filepath.Ext(u.filename))
+
+	err := os.Link(u.path, path)
+	if err == nil {
+		s.mu.Unlock()
+		return linked, path, nil
+	}
+
+	// Hard links may not be supported, so copy instead. Open the file while
+	// holding the lock, so that a sweep cannot remove it beforehand.
+	in, err := os.Open(u.path)
+	s.mu.Unlock()
+	if err != nil {
+		return upload{}, "", fmt.Errorf("open upload file: %w", err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(path)
+	if err != nil {
+		return upload{}, "", fmt.Errorf("create local file: %w", err)
+	}
+	defer func() {
+		_ = out.Close()
+	}()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return upload{}, "", fmt.Errorf("copy upload file to local file: %w", err)
+	}
+
+	return linked, path, nil
+}
+
+// expiresAt returns when an upload expires.
+func (s *uploadStore) expiresAt(u upload) time.Time {
+	return u.lastActivity.Add(s.ttl)
+}
+
+// lookup returns the upload with the given ID, unless expired. The caller
+// must hold the lock.
+func (s *uploadStore) lookup(id string) (*upload, bool) {
+	u, ok := s.uploads[id]
+	if !ok || (!u.writing && !s.now().Before(s.expiresAt(*u))) {
+		return nil, false
+	}
+
+	return u, true
+}
+
+// sweep removes the expired uploads and their content. The caller must hold
+// the lock.
+func (s *uploadStore) sweep(now time.Time) {
+	for id, u := range s.uploads {
+		if u.writing || now.Before(s.expiresAt(*u)) {
+			continue
+		}
+
+		delete(s.uploads, id)
+		_ = os.RemoveAll(u.dirPath)
+	}
+}
+
+// parseUploadMetadata returns the filename from an "Upload-Metadata" header,
+// i.e., comma-separated key and base64 encoded value pairs.
+func parseUploadMetadata(header string) (string, error) {
+	for pair := range strings.SplitSeq(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key != "filename" {
+			continue
+		}
+
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("decode filename: %w", err)
+		}
+
+		filename := sanitizeFilename(string(b))
+		if filename == "" {
+			break
+		}
+
+		return filename, nil
+	}
+
+	return "", errors.New("no filename")
+}
+
+// setUploadHeaders sets the headers describing the state of an upload.
+func setUploadHeaders(c echo.Context, store *uploadStore, u upload) {
+	c.Response().Header().Set("Upload-Offset", strconv.FormatInt(u.offset, 10))
+	c.Response().Header().Set("Upload-Length", strconv.FormatInt(u.length, 10))
+	c.Response().Header().Set("Upload-Expires", store.expiresAt(u).UTC().Format(http.TimeFormat))
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+}
+
+// tusMiddleware checks the tus protocol version of a request and sets it on
+// the response.
+func tusMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Set("Tus-Resumable", tusResumable)
+
+			version := c.Request().Header.Get("Tus-Resumable")
+			if version != "" && version != tusResumable {
+				c.Response().Header().Set("Tus-Version", tusResumable)
+				return WrapError(
+					fmt.Errorf("unsupported tus version '%s'", version),
+					NewSentinelHttpError(http.StatusPreconditionFailed, fmt.Sprintf("Invalid 'Tus-Resumable' header value: want '%s'", tusResumable)),
+				)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// createUploadHandler creates an upload from the "Upload-Length" and
+// "Upload-Metadata" headers. The response's "Location" header is the URL of
+// the upload.
+func createUploadHandler(store *uploadStore, rootPath string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		header := c.Request().Header.Get("Upload-Length")
+		length, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || length < 0 {
+			return WrapError(
+				fmt.Errorf("invalid upload length '%s'", header),
+				NewSentinelHttpError(http.StatusBadRequest, "Invalid 'Upload-Length' header value: want a positive integer"),
+			)
+		}
+
+		filename, err := parseUploadMetadata(c.Request().Header.Get("Upload-Metadata"))
+		if err != nil {
+			return WrapError(
+				fmt.Errorf("parse upload metadata: %w", err),
+				NewSentinelHttpError(http.StatusBadRequest, "Invalid 'Upload-Metadata' header value: want a base64 encoded 'filename'"),
+			)
+		}
+
+		u, err := store.create(length, filename)
+		if err != nil {
+			return fmt.Errorf("create upload: %w", err)
+		}
+
+		setUploadHeaders(c, store, u)
+		c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("%suploads/%s", rootPath, u.id))
+
+		return c.NoContent(http.StatusCreated)
+	}
+}
+
+// uploadOffsetHandler returns the state of an upload in its headers.
+func uploadOffsetHandler(store *uploadStore) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		u, ok := store.get(c.Param("id"))
+		if !ok {
+			return uploadNotFoundError(c.Param("id"))
+		}
+
+		setUploadHeaders(c, store, u)
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// patchUploadHandler writes a chunk at the offset given by the
+// "Upload-Offset" header.
+func patchUploadHandler(store *uploadStore) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().Header.Get(echo.HeaderContentType) != uploadChunkContentType {
+			return WrapError(
+				errors.New("invalid chunk content type"),
+				NewSentinelHttpError(http.StatusUnsupportedMediaType, fmt.Sprintf("Invalid 'Content-Type' header value: want '%s'", uploadChunkContentType)),
+			)
+		}
+
+		header := c.Request().Header.Get("Upload-Offset")
+		offset, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || offset < 0 {
+			return WrapError(
+				fmt.Errorf("invalid upload offset '%s'", header),
+				NewSentinelHttpError(http.StatusBadRequest, "Invalid 'Upload-Offset' header value: want a positive integer"),
+			)
+		}
+
+		u, err := store.write(c.Param("id"), offset, c.Request().Body)
+		if err != nil {
+			return fmt.Errorf("write upload: %w", err)
+		}
+
+		setUploadHeaders(c, store, u)
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// deleteUploadHandler removes an upload.
+func deleteUploadHandler(store *uploadStore) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ok, err := store.remove(c.Param("id"))
+		if err != nil {
+			return fmt.Errorf("remove upload: %w", err)
+		}
+
+		if !ok {
+			return uploadNotFoundError(c.Param("id"))
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,336 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func newTestUploadStore(t *testing.T, maxSize int64, maxUploads int) *uploadStore {
+	t.Helper()
+
+	fs := gotenberg.NewFileSystem(new(gotenberg.OsMkdirAll))
+	t.Cleanup(func() {
+		_ = os.RemoveAll(fs.WorkingDirPath())
+	})
+
+	store := newUploadStore(fs, maxSize, maxUploads, time.Hour)
+	store.start()
+	t.Cleanup(func() {
+		_ = store.close()
+	})
+
+	return store
+}
+
+func TestUploadStore(t *testing.T) {
+	store := newTestUploadStore(t, 10, 0)
+
+	_, err := store.create(11, "foo.pdf")
+	if status, _ := ParseError(err); status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d for a too large upload, got %d (%v)", http.StatusRequestEntityTooLarge, status, err)
+	}
+
+	u, err := store.create(10, "foo.pdf")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	dirPath := t.TempDir()
+
+	_, _, err = store.link(u.id, dirPath)
+	if status, _ := ParseError(err); status != http.StatusBadRequest {
+		t.Fatalf("expected status %d for an incomplete upload, got %d (%v)", http.StatusBadRequest, status, err)
+	}
+
+	// A chunk which breaks off.
+	u, err = store.write(u.id, 0, io.MultiReader(strings.NewReader("0123"), iotest.ErrReader(errors.New("connection reset"))))
+	if err == nil {
+		t.Fatal("expected an error but got none")
+	}
+	if u.offset != 4 {
+		t.Fatalf("expected offset 4, got %d", u.offset)
+	}
+
+	_, err = store.write(u.id, 0, strings.NewReader("0123"))
+	if status, _ := ParseError(err); status != http.StatusConflict {
+		t.Fatalf("expected status %d for an offset mismatch, got %d (%v)", http.StatusConflict, status, err)
+	}
+
+	_, err = store.write(u.id, 4, strings.NewReader("4567890"))
+	if status, _ := ParseError(err); status != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d for a chunk exceeding the length, got %d (%v)", http.StatusRequestEntityTooLarge, status, err)
+	}
+
+	u, ok := store.get(u.id)
+	if !ok || !u.complete() {
+		t.Fatalf("expected a complete upload, got %+v", u)
+	}
+
+	linked, path, err := store.link(u.id, dirPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if linked.filename != "foo.pdf" || !strings.HasSuffix(path, ".pdf") {
+		t.Errorf("expected a PDF file, got '%s' at '%s'", linked.filename, path)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read linked file: %v", err)
+	}
+	if string(b) != "0123456789" {
+		t.Errorf("expected content '0123456789', got '%s'", string(b))
+	}
+
+	// Expiry.
+	store.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, ok = store.get(u.id); ok {
+		t.Error("expected the upload to be expired")
+	}
+
+	store.mu.Lock()
+	store.sweep(store.now())
+	store.mu.Unlock()
+	if _, err = os.Stat(u.dirPath); !os.IsNotExist(err) {
+		t.Errorf("expected the expired upload directory to be removed, got: %v", err)
+	}
+
+	// The linked file survives the upload.
+	if _, err = os.Stat(path); err != nil {
+		t.Errorf("expected the linked file to remain, got: %v", err)
+	}
+}
+
+func TestUploadStore_MaxUploads(t *testing.T) {
+	store := newTestUploadStore(t, 0, 1)
+
+	u, err := store.create(1, "foo.pdf")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = store.create(1, "bar.pdf")
+	if status, _ := ParseError(err); status != http.StatusTooManyRequests {
+		t.Fatalf("expected status %d beyond the maximum number of uploads, got %d (%v)", http.StatusTooManyRequests, status, err)
+	}
+
+	// An expired upload does not count.
+	store.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = store.create(1, "bar.pdf")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+	if _, err = os.Stat(u.dirPath); !os.IsNotExist(err) {
+		t.Errorf("expected the expired upload directory to be removed, got: %v", err)
+	}
+}
+
+func TestUploadStore_Close(t *testing.T) {
+	store := newTestUploadStore(t, 0, 0)
+
+	u, err := store.create(1, "foo.pdf")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = store.close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if _, ok := store.get(u.id); ok {
+		t.Error("expected the upload to be removed")
+	}
+	if _, err = os.Stat(u.dirPath); !os.IsNotExist(err) {
+		t.Errorf("expected the upload directory to be removed, got: %v", err)
+	}
+}
+
+func TestParseUploadMetadata(t *testing.T) {
+	encode := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		header      string
+		expect      string
+		expectError bool
+	}{
+		{scenario: "filename only", header: "filename " + encode("foo.pdf"), expect: "foo.pdf"},
+		{scenario: "many pairs", header: "filetype " + encode("application/pdf") + ", filename " + encode("foo.pdf"), expect: "foo.pdf"},
+		{scenario: "path separators", header: "filename " + encode("../../foo.pdf"), expect: "foo.pdf"},
+		{scenario: "no filename", header: "filetype " + encode("application/pdf"), expectError: true},
+		{scenario: "invalid base64", header: "filename !!!", expectError: true},
+		{scenario: "empty header", header: "", expectError: true},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			filename, err := parseUploadMetadata(tc.header)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if filename != tc.expect {
+				t.Errorf("expected filename '%s', got '%s'", tc.expect, filename)
+			}
+		})
+	}
+}
+
+func TestUploadHandlers(t *testing.T) {
+	store := newTestUploadStore(t, 0, 0)
+
+	e := echo.New()
+	e.HTTPErrorHandler = httpErrorHandler()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("logger", slog.New(slog.DiscardHandler))
+			return next(c)
+		}
+	})
+	e.POST("/uploads", createUploadHandler(store, "/"), tusMiddleware())
+	e.HEAD("/uploads/:id", uploadOffsetHandler(store), tusMiddleware())
+	e.PATCH("/uploads/:id", patchUploadHandler(store), tusMiddleware())
+	e.DELETE("/uploads/:id", deleteUploadHandler(store), tusMiddleware())
+
+	do := func(method, target string, body io.Reader, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, body)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/uploads", nil, map[string]string{"Tus-Resumable": "0.2.2", "Upload-Length": "6", "Upload-Metadata": "filename Zm9vLnBkZg=="})
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status %d for an unsupported version, got %d", http.StatusPreconditionFailed, rec.Code)
+	}
+
+	rec = do(http.MethodPost, "/uploads", nil, map[string]string{"Upload-Length": "foo", "Upload-Metadata": "filename Zm9vLnBkZg=="})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d for an invalid length, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	rec = do(http.MethodPost, "/uploads", nil, map[string]string{"Tus-Resumable": tusResumable, "Upload-Length": "6", "Upload-Metadata": "filename Zm9vLnBkZg=="})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	location := rec.Header().Get(echo.HeaderLocation)
+	if !strings.HasPrefix(location, "/uploads/") {
+		t.Fatalf("expected a location within /uploads/, got '%s'", location)
+	}
+	if rec.Header().Get("Tus-Resumable") != tusResumable {
+		t.Errorf("expected header 'Tus-Resumable' to be '%s', got '%s'", tusResumable, rec.Header().Get("Tus-Resumable"))
+	}
+
+	rec = do(http.MethodPatch, location, strings.NewReader("foo"), map[string]string{"Upload-Offset": "0"})
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected status %d for an invalid content type, got %d", http.StatusUnsupportedMediaType, rec.Code)
+	}
+
+	for i, chunk := range []string{"foo", "bar"} {
+		rec = do(http.MethodPatch, location, strings.NewReader(chunk), map[string]string{
+			echo.HeaderContentType: uploadChunkContentType,
+			"Upload-Offset":        strconv.Itoa(i * 3),
+		})
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusNoContent, rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("Upload-Offset") != strconv.Itoa((i+1)*3) {
+			t.Fatalf("expected offset %d, got '%s'", (i+1)*3, rec.Header().Get("Upload-Offset"))
+		}
+	}
+
+	rec = do(http.MethodHead, location, nil, nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("Upload-Offset") != "6" || rec.Header().Get("Upload-Length") != "6" {
+		t.Fatalf("expected a complete upload, got status %d and headers %v", rec.Code, rec.Header())
+	}
+
+	// Reference the upload in a conversion request.
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	err := writer.WriteField(UploadsFormField, `["`+strings.TrimPrefix(location, "/uploads/")+`"]`)
+	if err != nil {
+		t.Fatalf("write field: %v", err)
+	}
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("close multipart writer: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario   string
+		uploads    *uploadStore
+		bodyLimit  int64
+		wantStatus int
+	}{
+		{"uploads disabled", nil, 0, http.StatusBadRequest},
+		{"body limit exceeded", store, 5, http.StatusRequestEntityTooLarge},
+		{"upload referenced", store, 0, http.StatusOK},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body.Bytes()))
+			req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			ctx, cancel, err := newContext(c, slog.New(slog.DiscardHandler), store.fs, 10*time.Second, tc.bodyLimit, downloadFromConfig{disable: true}, tc.uploads)
+			defer cancel()
+
+			if tc.wantStatus != http.StatusOK {
+				if status, _ := ParseError(err); status != tc.wantStatus {
+					t.Fatalf("expected status %d, got %d (%v)", tc.wantStatus, status, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			b, err := os.ReadFile(ctx.files["foo.pdf"])
+			if err != nil {
+				t.Fatalf("read input file: %v", err)
+			}
+			if string(b) != "foobar" {
+				t.Errorf("expected content 'foobar', got '%s'", string(b))
+			}
+		})
+	}
+
+	rec = do(http.MethodDelete, location, nil, nil)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+
+	rec = do(http.MethodHead, location, nil, nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,251 @@
+package chromium
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pdfengines"
+)
+
+// maxBatchUrls bounds the number of URLs of the "urls" form field. Every URL
+// takes a Chromium tab until the conversions end.
+const maxBatchUrls = 100
+
+// batchUrl is a URL to convert by the /forms/chromium/convert/urls route,
+// with its own options.
+type batchUrl struct {
+	Url     string
+	Options PdfOptions
+}
+
+// batchUrlEntry is an entry of the JSON-encoded "urls" form field. Apart from
+// the URL, each member overrides the matching form field for this URL only,
+// e.g.:
+//
+//	[
+//	  {"url": "https://my.url"},
+//	  {"url": "https://my.other.url", "waitForSelector": "#ready"}
+//	]
+type batchUrlEntry struct {
+	Url                        string          `json:"url"`
+	SkipNetworkIdleEvent       *bool           `json:"skipNetworkIdleEvent"`
+	SkipNetworkAlmostIdleEvent *bool           `json:"skipNetworkAlmostIdleEvent"`
+	FailOnHttpStatusCodes      []int64         `json:"failOnHttpStatusCodes"`
+	WaitDelay                  *string         `json:"waitDelay"`
+	WaitWindowStatus           *string         `json:"waitWindowStatus"`
+	WaitForExpression          *string         `json:"waitForExpression"`
+	WaitForSelector            *string         `json:"waitForSelector"`
+	Cookies                    json.RawMessage `json:"cookies"`
+	UserAgent                  *string         `json:"userAgent"`
+	EmulatedMediaType          *string         `json:"emulatedMediaType"`
+}
+
+// parseBatchUrls unmarshals the "urls" form field. Each URL starts with the
+// options of the other form fields, then applies its own overrides.
+func parseBatchUrls(value string, defaultOptions PdfOptions) ([]batchUrl, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.DisallowUnknownFields()
+
+	var entries []batchUrlEntry
+	err := decoder.Decode(&entries)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal urls: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("no URL, expected at least one")
+	}
+
+	if len(entries) > maxBatchUrls {
+		return nil, fmt.Errorf("too many URLs, got %d, expected at most %d", len(entries), maxBatchUrls)
+	}
+
+	urls := make([]batchUrl, 0, len(entries))
+	for i, entry := range entries {
+		options, entryErr := entry.pdfOptions(defaultOptions)
+		if entryErr != nil {
+			err = errors.Join(err, fmt.Errorf("URL %d: %w", i, entryErr))
+			continue
+		}
+
+		urls = append(urls, batchUrl{Url: entry.Url, Options: options})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return urls, nil
+}
+
+// pdfOptions returns the given options with the overrides of the entry.
+func (entry batchUrlEntry) pdfOptions(options PdfOptions) (PdfOptions, error) {
+	if strings.TrimSpace(entry.Url) == "" {
+		return options, errors.New("URL must be set")
+	}
+
+	if entry.SkipNetworkIdleEvent != nil {
+		options.SkipNetworkIdleEvent = *entry.SkipNetworkIdleEvent
+	}
+
+	if entry.SkipNetworkAlmostIdleEvent != nil {
+		options.SkipNetworkAlmostIdleEvent = *entry.SkipNetworkAlmostIdleEvent
+	}
+
+	if entry.FailOnHttpStatusCodes != nil {
+		options.FailOnHttpStatusCodes = entry.FailOnHttpStatusCodes
+	}
+
+	if entry.WaitDelay != nil {
+		waitDelay, err := time.ParseDuration(*entry.WaitDelay)
+		if err != nil {
+			return options, fmt.Errorf("parse waitDelay: %w", err)
+		}
+		options.WaitDelay = waitDelay
+	}
+
+	if entry.WaitWindowStatus != nil {
+		options.WaitWindowStatus = *entry.WaitWindowStatus
+	}
+
+	if entry.WaitForExpression != nil {
+		options.WaitForExpression = *entry.WaitForExpression
+	}
+
+	if entry.WaitForSelector != nil {
+		options.WaitForSelector = *entry.WaitForSelector
+	}
+
+	if len(entry.Cookies) > 0 {
+		cookies, err := parseCookies(string(entry.Cookies))
+		if err != nil {
+			return options, err
+		}
+		options.Cookies = cookies
+	}
+
+	if entry.UserAgent != nil {
+		options.UserAgent = *entry.UserAgent
+	}
+
+	if entry.EmulatedMediaType != nil {
+		if *entry.EmulatedMediaType != "" && *entry.EmulatedMediaType != "screen" && *entry.EmulatedMediaType != "print" {
+			return options, errors.New("wrong emulatedMediaType, expected either 'screen', 'print' or empty")
+		}
+		options.EmulatedMediaType = *entry.EmulatedMediaType
+	}
+
+	return options, nil
+}
+
+// printPdfs prints the URLs to PDF concurrently, at most maxConcurrency at a
+// time, and returns the output paths in the same order. The first failure
+// cancels the other conversions.
+func printPdfs(ctx *api.Context, chromium Api, urls []batchUrl, maxConcurrency int64) ([]string, error) {
+	// Paths are generated upfront, as the context is not safe for concurrent
+	// use.
+	outputPaths := make([]string, len(urls))
+	for i := range urls {
+		outputPaths[i] = ctx.GeneratePath(".pdf")
+		withDiagnosticsPaths(ctx, &urls[i].Options.Options, fmt.Sprintf("_%d", i))
+	}
+
+	// Beyond Chromium's concurrency, the conversions would only wait in its
+	// queue, and take the place of other requests.
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(int(maxConcurrency))
+	for i, u := range urls {
+		eg.Go(func() error {
+			err := chromium.Pdf(egCtx, ctx.Log(), u.Url, outputPaths[i], u.Options)
+			err = handlePdfError(err, u.Options)
+			if err != nil {
+				return fmt.Errorf("URL %d: %w", i, err)
+			}
+
+			return nil
+		})
+	}
+
+	err := eg.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return outputPaths, nil
+}
+
+// titleBookmarks returns a top-level bookmark per printed URL, labeled by its
+// page title (falling back to the URL) and pointing to its first page in the
+// merged PDF, with the page's own outline nested underneath.
+func titleBookmarks(ctx *api.Context, engine gotenberg.PdfEngine, urls []batchUrl, inputPaths []string) ([]gotenberg.Bookmark, error) {
+	bookmarks := make([]gotenberg.Bookmark, 0, len(inputPaths))
+
+	offset := 0
+	for i, inputPath := range inputPaths {
+		children, err := engine.ReadBookmarks(ctx, ctx.Log(), inputPath)
+		if err != nil {
+			return nil, fmt.Errorf("read bookmarks of URL %d: %w", i, err)
+		}
+
+		bookmarks = append(bookmarks, gotenberg.Bookmark{
+			Title:    pdfengines.DocumentTitle(ctx, engine, inputPath, urls[i].Url),
+			Page:     offset + 1,
+			Children: pdfengines.ShiftBookmarks(children, offset),
+		})
+
+		pageCount, err := engine.PageCount(ctx, ctx.Log(), inputPath)
+		if err != nil {
+			return nil, fmt.Errorf("get page count of URL %d: %w", i, err)
+		}
+		offset += pageCount
+	}
+
+	return bookmarks, nil
+}
+
+func convertUrls(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, maxConcurrency int64, urls []batchUrl, withTitleBookmarks bool, pdfFormats gotenberg.PdfFormats, metadata map[string]any, encrypt gotenberg.EncryptOptions, embedPaths []string, embedsMetadata map[string]map[string]string, facturX gotenberg.FacturX, facturxXmlPath string, watermarks, stamps []gotenberg.Stamp, rotateAngle int, rotatePages string, optimizeImages bool, imageQuality int) error {
+	inputPaths, err := printPdfs(ctx, chromium, urls, maxConcurrency)
+	if err != nil {
+		return err
+	}
+
+	var bookmarks []gotenberg.Bookmark
+	if withTitleBookmarks {
+		bookmarks, err = titleBookmarks(ctx, engine, urls, inputPaths)
+		if err != nil {
+			return fmt.Errorf("title bookmarks: %w", err)
+		}
+	}
+
+	outputPath := ctx.GeneratePath(".pdf")
+	// See https://github.com/gotenberg/gotenberg/issues/1130.
+	filename := ctx.OutputFilename(outputPath)
+	outputPath = ctx.GeneratePathFromFilename(filename)
+
+	err = engine.Merge(ctx, ctx.Log(), inputPaths, outputPath)
+	if err != nil {
+		return fmt.Errorf("merge PDFs: %w", err)
+	}
+
+	err = processPdf(ctx, engine, outputPath, bookmarks, gotenberg.SplitMode{}, pdfFormats, metadata, encrypt, embedPaths, embedsMetadata, facturX, facturxXmlPath, watermarks, stamps, rotateAngle, rotatePages, optimizeImages, imageQuality)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range urls {
+		err = addDiagnosticsOutputPaths(ctx, u.Options.Options)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package chromium
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestParseBatchUrls(t *testing.T) {
+	defaultOptions := DefaultPdfOptions()
+	defaultOptions.WaitForSelector = "#default"
+
+	t.Run("inherits the form options", func(t *testing.T) {
+		urls, err := parseBatchUrls(`[{"url":"https://a.example"},{"url":"https://b.example"}]`, defaultOptions)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(urls) != 2 {
+			t.Fatalf("expected 2 URLs but got %d", len(urls))
+		}
+
+		for i, expect := range []string{"https://a.example", "https://b.example"} {
+			if urls[i].Url != expect {
+				t.Errorf("expected URL %d to be '%s' but got '%s'", i, expect, urls[i].Url)
+			}
+			if urls[i].Options.WaitForSelector != "#default" {
+				t.Errorf("expected URL %d to wait for '#default' but got '%s'", i, urls[i].Options.WaitForSelector)
+			}
+		}
+	})
+
+	t.Run("overrides the form options per URL", func(t *testing.T) {
+		urls, err := parseBatchUrls(`[
+			{"url":"https://a.example"},
+			{"url":"https://b.example","waitForSelector":"#ready","waitDelay":"2s","cookies":[{"name":"foo","value":"bar","domain":"b.example","sameSite":"lax"}]}
+		]`, defaultOptions)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if urls[0].Options.WaitForSelector != "#default" || len(urls[0].Options.Cookies) != 0 {
+			t.Errorf("expected the first URL to keep the form options but got %+v", urls[0].Options.Options)
+		}
+
+		options := urls[1].Options
+		if options.WaitForSelector != "#ready" {
+			t.Errorf("expected to wait for '#ready' but got '%s'", options.WaitForSelector)
+		}
+		if options.WaitDelay != 2*time.Second {
+			t.Errorf("expected a wait delay of 2s but got %s", options.WaitDelay)
+		}
+		if len(options.Cookies) != 1 || options.Cookies[0].SameSite != "Lax" {
+			t.Errorf("expected a normalized cookie but got %+v", options.Cookies)
+		}
+	})
+
+	for _, tc := range []struct {
+		scenario string
+		value    string
+	}{
+		{scenario: "invalid JSON", value: "foo"},
+		{scenario: "no URL", value: "[]"},
+		{scenario: "too many URLs", value: "[" + strings.Repeat(`{"url":"https://a.example"},`, maxBatchUrls) + `{"url":"https://a.example"}]`},
+		{scenario: "empty URL", value: `[{"url":" "}]`},
+		{scenario: "unsupported override", value: `[{"url":"https://a.example","extraHttpHeaders":{"foo":"bar"}}]`},
+		{scenario: "invalid waitDelay", value: `[{"url":"https://a.example","waitDelay":"foo"}]`},
+		{scenario: "invalid cookie", value: `[{"url":"https://a.example","cookies":[{"name":"foo"}]}]`},
+		{scenario: "invalid emulatedMediaType", value: `[{"url":"https://a.example","emulatedMediaType":"foo"}]`},
+	} {
+		t.Run(fmt.Sprintf("rejects %s", tc.scenario), func(t *testing.T) {
+			_, err := parseBatchUrls(tc.value, defaultOptions)
+			if err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestPrintPdfs(t *testing.T) {
+	ctx := &api.ContextMock{Context: &api.Context{Context: context.Background()}}
+	ctx.SetDirPath(t.TempDir())
+	ctx.SetLogger(slog.New(slog.DiscardHandler))
+
+	var mu sync.Mutex
+	var active, maxActive int
+	chromium := &ApiMock{PdfMock: func(ctx context.Context, logger *slog.Logger, url, outputPath string, options PdfOptions) error {
+		mu.Lock()
+		active++
+		maxActive = max(maxActive, active)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		return nil
+	}}
+
+	urls := make([]batchUrl, 10)
+	for i := range urls {
+		urls[i] = batchUrl{Url: fmt.Sprintf("https://%d.example", i), Options: DefaultPdfOptions()}
+	}
+
+	outputPaths, err := printPdfs(ctx.Context, chromium, urls, 2)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(outputPaths) != len(urls) {
+		t.Errorf("expected %d output paths, but got %d", len(urls), len(outputPaths))
+	}
+
+	if maxActive > 2 {
+		t.Errorf("expected at most 2 concurrent conversions, but got %d", maxActive)
+	}
+}
@@ -21,12 +21,31 @@ import (
 	"github.com/shirou/gopsutil/v4/process"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
 type browser interface {
 	gotenberg.Process
 	pdf(ctx context.Context, logger *slog.Logger, url, outputPath string, options PdfOptions, aggregate *networkAggregate) error
 	screenshot(ctx context.Context, logger *slog.Logger, url, outputPath string, options ScreenshotOptions, aggregate *networkAggregate) error
+	login(ctx context.Context, logger *slog.Logger, url string, steps []LoginStep, options Options, aggregate *networkAggregate) (*sessionState, error)
+	reload(policy *outboundPolicy)
+}
+
+// outboundPolicy gathers the allowed/denied lists and the IP-class options
+// that the URLs Chromium loads must pass. It may change on reload.
+type outboundPolicy struct {
+	allowList      []*regexp2.Regexp
+	denyList       []*regexp2.Regexp
+	denyPrivateIPs bool
+	denyPublicIPs  bool
+}
+
+func (p *outboundPolicy) ipOptions() []gotenberg.DecideOption {
+	return []gotenberg.DecideOption{
+		gotenberg.WithDenyPrivateIPs(p.denyPrivateIPs),
+		gotenberg.WithDenyPublicIPs(p.denyPublicIPs),
+	}
 }
 
 type browserArguments struct {
@@ -43,10 +62,7 @@ type browserArguments struct {
 	hyphenDataDirPath        string
 
 	// Tasks specific.
-	allowList         []*regexp2.Regexp
-	denyList          []*regexp2.Regexp
-	denyPrivateIPs    bool
-	denyPublicIPs     bool
+	policy            *outboundPolicy
 	clearCache        bool
 	clearCookies      bool
 	clearStorage      bool
@@ -70,6 +86,7 @@ type chromiumBrowser struct {
 	startMu sync.Mutex
 
 	arguments    browserArguments
+	policy       atomic.Pointer[outboundPolicy]
 	fs           *gotenberg.FileSystem
 	pinningProxy *pinningProxy
 }
@@ -79,8 +96,9 @@ func newChromiumBrowser(arguments browserArguments) browser {
 		initialCtx:   context.Background(),
 		arguments:    arguments,
 		fs:           gotenberg.NewFileSystem(new(gotenberg.OsMkdirAll)),
-		pinningProxy: newPinningProxy(arguments.allowList, arguments.denyList, arguments.denyPrivateIPs, arguments.denyPublicIPs, arguments.enableEnvironmentProxy),
+		pinningProxy: newPinningProxy(arguments.policy, arguments.enableEnvironmentProxy),
 	}
+	b.policy.Store(arguments.policy)
 	b.isStarted.Store(false)
 
 	return b
@@ -181,7 +199,7 @@ func (b *chromiumBrowser) Start(logger *slog.Logger) error {
 		}
 		opts = append(opts, chromedp.ProxyServer(b.pinningProxy.URL()))
 
-		if b.arguments.denyPrivateIPs || b.arguments.denyPublicIPs {
+		if policy := b.policy.Load(); policy.denyPrivateIPs || policy.denyPublicIPs {
 			// Chromium implicitly bypasses the proxy for loopback and
 			// link-local destinations. A WebSocket handshake is never surfaced
 			// as a fetch.EventRequestPaused, so listenForEventRequestPaused
@@ -200,6 +218,9 @@ func (b *chromiumBrowser) Start(logger *slog.Logger) error {
 			// the policy is off, loopback is not restricted, and routing it
 			// through the proxy would merely change how an unreachable loopback
 			// sub-resource reports its failure.
+			//
+			// As a command line flag, it does not follow a reload, which
+			// therefore must not change the IP-class policy.
 			opts = append(opts, chromedp.Flag("proxy-bypass-list", "<-loopback>"))
 		}
 	}
@@ -370,12 +391,15 @@ func (b *chromiumBrowser) pdf(ctx context.Context, logger *slog.Logger, url, out
 		clearCookiesActionFunc(logger, b.arguments.clearCookies),
 		clearStorageActionFunc(logger, b.arguments.clearStorage, url),
 		disableJavaScriptActionFunc(logger, b.arguments.disableJavaScript),
+		restoreSessionActionFunc(logger, options.session),
 		setCookiesActionFunc(logger, options.Cookies),
 		userAgentOverride(logger, options.UserAgent),
+		preloadScriptsActionFunc(logger, b.arguments.disableJavaScript, options.PreloadScripts),
 		navigateActionFunc(logger, url, options.SkipNetworkIdleEvent, options.SkipNetworkAlmostIdleEvent),
 		hideDefaultWhiteBackgroundActionFunc(logger, options.OmitBackground, options.PrintBackground),
 		forceExactColorsActionFunc(logger, options.PrintBackground),
 		emulateMediaTypeActionFunc(logger, options.EmulatedMediaType, options.EmulatedMediaFeatures),
+		injectStylesheetsActionFunc(logger, options.ExtraStylesheets, options.HideSelectors),
 		waitForExpressionBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitForExpression),
 		waitForSelectorVisibleBeforePrintActionFunc(logger, options.WaitForSelector),
 		waitDelayBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitDelay),
@@ -397,12 +421,15 @@ func (b *chromiumBrowser) screenshot(ctx context.Context, logger *slog.Logger, u
 		clearCookiesActionFunc(logger, b.arguments.clearCookies),
 		clearStorageActionFunc(logger, b.arguments.clearStorage, url),
 		disableJavaScriptActionFunc(logger, b.arguments.disableJavaScript),
+		restoreSessionActionFunc(logger, options.session),
 		setCookiesActionFunc(logger, options.Cookies),
 		userAgentOverride(logger, options.UserAgent),
+		preloadScriptsActionFunc(logger, b.arguments.disableJavaScript, options.PreloadScripts),
 		navigateActionFunc(logger, url, options.SkipNetworkIdleEvent, options.SkipNetworkAlmostIdleEvent),
 		hideDefaultWhiteBackgroundActionFunc(logger, options.OmitBackground, true),
 		forceExactColorsActionFunc(logger, true),
 		emulateMediaTypeActionFunc(logger, options.EmulatedMediaType, options.EmulatedMediaFeatures),
+		injectStylesheetsActionFunc(logger, options.ExtraStylesheets, options.HideSelectors),
 		waitForExpressionBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitForExpression),
 		waitForSelectorVisibleBeforePrintActionFunc(logger, options.WaitForSelector),
 		waitDelayBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitDelay),
@@ -414,7 +441,55 @@ func (b *chromiumBrowser) screenshot(ctx context.Context, logger *slog.Logger, u
 	})
 }
 
-func (b *chromiumBrowser) do(ctx context.Context, logger *slog.Logger, url string, options Options, aggregate *networkAggregate, tasks chromedp.Tasks) error {
+func (b *chromiumBrowser) login(ctx context.Context, logger *slog.Logger, url string, steps []LoginStep, options Options, aggregate *networkAggregate) (*sessionState, error) {
+	// The login runs in its own browser context, starting from a blank state
+	// or from the session it extends, so that the credentials never reach the
+	// other conversions.
+	if options.session == nil {
+		options.session = new(sessionState)
+	}
+
+	state := new(sessionState)
+
+	// Note: no error wrapping because it leaks on errors we want to display to
+	// the end user.
+	err := b.do(ctx, logger, url, options, aggregate, chromedp.Tasks{
+		network.Enable(),
+		fetch.Enable(),
+		runtime.Enable(),
+		disableJavaScriptActionFunc(logger, b.arguments.disableJavaScript),
+		restoreSessionActionFunc(logger, options.session),
+		setCookiesActionFunc(logger, options.Cookies),
+		userAgentOverride(logger, options.UserAgent),
+		navigateActionFunc(logger, url, options.SkipNetworkIdleEvent, options.SkipNetworkAlmostIdleEvent),
+		loginStepsActionFunc(logger, steps),
+		waitForExpressionBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitForExpression),
+		waitForSelectorVisibleBeforePrintActionFunc(logger, options.WaitForSelector),
+		waitDelayBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitDelay),
+		// Login specific.
+		captureSessionActionFunc(logger, url, state),
+		// Teardown.
+		page.Close(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// reload swaps the outbound policy of the browser and its pinning proxy. The
+// browser keeps running; the next conversions use the new policy.
+func (b *chromiumBrowser) reload(policy *outboundPolicy) {
+	b.policy.Store(policy)
+	b.pinningProxy.policy.Store(policy)
+}
+
+// failureScreenshotTimeout bounds the capture of a failure screenshot. The
+// conversion gives up to a quarter of its time for it.
+const failureScreenshotTimeout = 5 * time.Second
+
+func (b *chromiumBrowser) do(ctx context.Context, logger *slog.Logger, url string, options Options, aggregate *networkAggregate, tasks chromedp.Tasks) (err error) {
 	if !b.isStarted.Load() {
 		return errors.New("browser not started, cannot handle tasks")
 	}
@@ -424,12 +499,13 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *slog.Logger, url strin
 		return errors.New("context has no deadline")
 	}
 
+	// The conversion keeps the same policy, even if it changes on reload in
+	// the meantime.
+	policy := b.policy.Load()
+
 	// We validate the "main" URL against our allowed / deny lists, and
 	// against the IP-based outbound URL guard. See [gotenberg.FilterOutboundURL].
-	err := gotenberg.FilterOutboundURL(ctx, url, b.arguments.allowList, b.arguments.denyList, deadline,
-		gotenberg.WithDenyPrivateIPs(b.arguments.denyPrivateIPs),
-		gotenberg.WithDenyPublicIPs(b.arguments.denyPublicIPs),
-	)
+	err = gotenberg.FilterOutboundURL(ctx, url, policy.allowList, policy.denyList, deadline, policy.ipOptions()...)
 	if err != nil {
 		return fmt.Errorf("filter URL: %w", err)
 	}
@@ -440,23 +516,72 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *slog.Logger, url strin
 	timeoutCtx, timeoutCancel := context.WithTimeout(b.ctx, time.Until(deadline))
 	defer timeoutCancel()
 
-	taskCtx, taskCancel := chromedp.NewContext(timeoutCtx)
+	// A conversion with a session gets its own browser context, which is
+	// disposed of with the tab.
+	var contextOptions []chromedp.ContextOption
+	if options.session != nil {
+		contextOptions = append(contextOptions, chromedp.WithNewBrowserContext())
+	}
+
+	taskCtx, taskCancel := chromedp.NewContext(timeoutCtx, contextOptions...)
 	defer taskCancel()
 
+	// By default, the tasks run until the deadline, and a failure closes the
+	// tab.
+	runCtx, runCancel := taskCtx, taskCancel
+
+	if options.FailureScreenshot {
+		// The tab must outlive the tasks for the failure screenshot: we
+		// allocate it upfront, and keep some time for the capture.
+		err = chromedp.Run(taskCtx)
+		if err != nil {
+			return fmt.Errorf("allocate tab: %w", err)
+		}
+
+		runCtx, runCancel = context.WithDeadline(taskCtx, deadline.Add(-min(failureScreenshotTimeout, time.Until(deadline)/4)))
+		defer runCancel()
+
+		defer func() {
+			// A failed navigation leaves nothing to see.
+			if err == nil || errors.Is(err, ErrLoadingFailed) {
+				return
+			}
+
+			captureCtx, captureCancel := context.WithTimeout(taskCtx, failureScreenshotTimeout)
+			defer captureCancel()
+
+			var screenshot []byte
+			captureErr := chromedp.Run(captureCtx, captureFailureScreenshotActionFunc(logger, &screenshot))
+			if captureErr != nil {
+				logger.WarnContext(ctx, fmt.Sprintf("capture failure screenshot: %s", captureErr))
+				return
+			}
+
+			err = api.WithScreenshot(err, screenshot)
+		}()
+	}
+
 	// Accumulate per-conversion network activity for telemetry.
 	listenForNetworkActivity(taskCtx, aggregate)
 
+	var diagnostics *diagnosticsRecorder
+	if options.HarPath != "" || options.ConsoleLogPath != "" {
+		diagnostics = newDiagnosticsRecorder()
+		listenForDiagnostics(taskCtx, diagnostics)
+	}
+
 	// We validate all other requests against our allowed / deny lists.
 	// If a request does not pass the validation, we make it fail. It also set
 	// the extra HTTP headers, if any.
 	// See https://github.com/gotenberg/gotenberg/issues/1011.
 	listenForEventRequestPaused(taskCtx, logger, eventRequestPausedOptions{
-		allowList:           b.arguments.allowList,
-		denyList:            b.arguments.denyList,
-		denyPrivateIPs:      b.arguments.denyPrivateIPs,
-		denyPublicIPs:       b.arguments.denyPublicIPs,
+		allowList:           policy.allowList,
+		denyList:            policy.denyList,
+		denyPrivateIPs:      policy.denyPrivateIPs,
+		denyPublicIPs:       policy.denyPublicIPs,
 		allowedFilePrefixes: options.AllowedFilePrefixes,
 		extraHttpHeaders:    options.ExtraHttpHeaders,
+		diagnostics:         diagnostics,
 	})
 
 	// WebSocket handshakes never surface as fetch.EventRequestPaused, so
@@ -464,10 +589,10 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *slog.Logger, url strin
 	// against the same allow / deny lists and IP-class policy.
 	// See https://github.com/gotenberg/gotenberg/issues/1011.
 	listenForEventWebSocketCreated(taskCtx, logger, eventWebSocketCreatedOptions{
-		allowList:      b.arguments.allowList,
-		denyList:       b.arguments.denyList,
-		denyPrivateIPs: b.arguments.denyPrivateIPs,
-		denyPublicIPs:  b.arguments.denyPublicIPs,
+		allowList:      policy.allowList,
+		denyList:       policy.denyList,
+		denyPrivateIPs: policy.denyPrivateIPs,
+		denyPublicIPs:  policy.denyPublicIPs,
 	})
 
 	var (
@@ -490,7 +615,7 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *slog.Logger, url strin
 			ignoreResourceHttpStatusDomains: options.IgnoreResourceHttpStatusDomains,
 			invalidResourceHttpStatusCode:   &invalidResourceHttpStatusCode,
 			invalidResourceHttpStatusCodeMu: &invalidResourceHttpStatusCodeMu,
-			cancelOnMainPageError:           taskCancel,
+			cancelOnMainPageError:           runCancel,
 		})
 	}
 
@@ -520,10 +645,10 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *slog.Logger, url strin
 		loadingFailedMu:         &loadingFailedMu,
 		resourceLoadingFailed:   &resourceLoadingFailed,
 		resourceLoadingFailedMu: &resourceLoadingFailedMu,
-		cancelOnMainPageError:   taskCancel,
+		cancelOnMainPageError:   runCancel,
 	})
 
-	runErr := chromedp.Run(taskCtx, tasks...)
+	runErr := chromedp.Run(runCtx, tasks...)
 
 	// Check event-driven errors first — they take priority over chromedp.Run
 	// errors because they carry the actual root cause (e.g., HTTP 500 from
@@ -598,6 +723,14 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *slog.Logger, url strin
 		}
 	}
 
+	// Diagnostics only come alongside an output file.
+	if diagnostics != nil {
+		err = diagnostics.write(options.HarPath, options.ConsoleLogPath)
+		if err != nil {
+			return fmt.Errorf("write diagnostics: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -24,6 +24,7 @@ import (
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pipeline"
 )
 
 func init() {
@@ -71,6 +72,17 @@ var (
 	// ErrResourceLoadingFailed happens when one or more resources failed to load.
 	ErrResourceLoadingFailed = errors.New("resource loading failed")
 
+	// ErrSessionNotFound happens if the session of [Options.Session] does not
+	// exist or has expired.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrTooManySessions happens when creating a session while the maximum
+	// number of sessions is reached.
+	ErrTooManySessions = errors.New("too many sessions")
+
+	// ErrLoginStepFailed happens if a step of a login script fails.
+	ErrLoginStepFailed = errors.New("login step failed")
+
 	// PDF specific.
 
 	// ErrOmitBackgroundWithoutPrintBackground happens if
@@ -99,12 +111,16 @@ type Chromium struct {
 	autoStart      bool
 	disableRoutes  bool
 	maxConcurrency int64
+	enableSessions bool
+	sessionTtl     time.Duration
+	maxSessions    int
 	args           browserArguments
 
 	logger     *slog.Logger
 	browser    browser
 	supervisor gotenberg.ProcessSupervisor
 	engine     gotenberg.PdfEngine
+	sessions   *sessionStore
 
 	version     string
 	versionOnce sync.Once
@@ -199,6 +215,33 @@ type Options struct {
 	// PDFs with transparency.
 	OmitBackground bool
 
+	// PreloadScripts are scripts to run in every document, before its own
+	// scripts, e.g., to stub analytics or to freeze Date.now. Ignored if
+	// JavaScript is disabled.
+	PreloadScripts []string
+
+	// ExtraStylesheets are stylesheets to add to the page once loaded, e.g.,
+	// print rules for a third-party URL. Their sub-resources go through the
+	// same filtering as the ones of the page.
+	ExtraStylesheets []string
+
+	// HideSelectors are the queries of the elements to hide once the page is
+	// loaded, e.g., cookie banners.
+	HideSelectors []string
+
+	// Diagnostics tells if the conversion should also return a HAR file of
+	// the requests, and a JSON log of the console messages.
+	Diagnostics bool
+
+	// HarPath and ConsoleLogPath are where to write the diagnostics, if any.
+	// Set internally by route handlers, not via form data.
+	HarPath        string
+	ConsoleLogPath string
+
+	// FailureScreenshot tells if a conversion failing after the navigation
+	// should attach a full-page screenshot of the page to its error.
+	FailureScreenshot bool
+
 	// AllowedFilePrefixes restricts file:// sub-resource access to only
 	// these directory prefixes. Applied in listenForEventRequestPaused in
 	// addition to the global allow/deny lists. An empty slice
@@ -207,6 +250,22 @@ type Options struct {
 	// request working directory while routes that navigate remote URLs
 	// leave it empty. Set internally by route handlers, not via form data.
 	AllowedFilePrefixes []string
+
+	// Session is the name of a session whose cookies and local storage to
+	// restore before loading the page. A conversion with a session runs in
+	// its own browser context, so that the session state does not leak into
+	// other conversions.
+	// Only the caller which created the session may use it; anonymous
+	// callers all share the same owner, hence the same sessions.
+	Session string
+
+	// SessionOwner is the identity of the caller which created the session.
+	// Set internally by route handlers, not via form data.
+	SessionOwner string
+
+	// session is the state of [Options.Session], resolved before the
+	// conversion.
+	session *sessionState
 }
 
 // EmulatedMediaFeature gathers the available entries for emulating a media
@@ -242,6 +301,11 @@ func DefaultOptions() Options {
 		EmulatedMediaType:               "",
 		EmulatedMediaFeatures:           nil,
 		OmitBackground:                  false,
+		PreloadScripts:                  nil,
+		ExtraStylesheets:                nil,
+		HideSelectors:                   nil,
+		Diagnostics:                     false,
+		FailureScreenshot:               false,
 	}
 }
 
@@ -353,9 +417,18 @@ type ScreenshotOptions struct {
 
 	// Selector clips the screenshot to the bounding box of the first element
 	// matching this CSS selector. Empty captures the whole page. Takes
-	// precedence over Clip.
+	// precedence over FullPage and Clip.
 	Selector string
 
+	// FullPage defines whether to capture the entire scroll height of the
+	// page, even beyond the maximum texture size of Chromium. Takes
+	// precedence over Clip.
+	FullPage bool
+
+	// Viewports are the device screens to take a screenshot with, one image
+	// per viewport. If set, they replace Width, Height and DeviceScaleFactor.
+	Viewports []Viewport
+
 	// Format is the image compression format, either "png" or "jpeg" or
 	// "webp".
 	Format string
@@ -384,9 +457,27 @@ func DefaultScreenshotOptions() ScreenshotOptions {
 		Quality:           100,
 		OptimizeForSpeed:  false,
 		DeviceScaleFactor: 1.0,
+		FullPage:          false,
+		Viewports:         nil,
 	}
 }
 
+// Viewport is a device screen to take a screenshot with.
+type Viewport struct {
+	// Width is the device screen width in pixels.
+	// Required.
+	Width int `json:"width"`
+
+	// Height is the device screen height in pixels.
+	// Required.
+	Height int `json:"height"`
+
+	// DeviceScaleFactor is the ratio of the resolution in physical pixels to
+	// the resolution in CSS pixels for the current display device.
+	// Optional, defaults to 1.
+	DeviceScaleFactor float64 `json:"deviceScaleFactor,omitempty"`
+}
+
 // Cookie gathers the available entries for setting a cookie in the Chromium
 // cookies' jar.
 type Cookie struct {
@@ -480,6 +571,9 @@ func (mod *Chromium) Descriptor() gotenberg.ModuleDescriptor {
 			fs.Bool("chromium-clear-storage", false, "Clear Chromium local storage between each conversion (session storage is already isolated per conversion)")
 			fs.Bool("chromium-disable-javascript", false, "Disable JavaScript")
 			fs.Bool("chromium-disable-routes", false, "Disable the routes")
+			fs.Bool("chromium-enable-sessions", false, "Enable the routes which run a login script and keep the resulting cookies and local storage in memory, for restoring them in later conversions - sessions belong to the authenticated caller which created them, so without authentication all callers share the same sessions")
+			fs.Duration("chromium-session-ttl", time.Duration(30)*time.Minute, "Set the maximum time-to-live of a session")
+			fs.Int("chromium-max-sessions", 100, "Set the maximum number of sessions - set to 0 to disable this limit")
 
 			// Deprecated flags.
 			fs.Bool("chromium-incognito", false, "Start Chromium with incognito mode")
@@ -500,6 +594,9 @@ func (mod *Chromium) Provision(ctx *gotenberg.Context) error {
 	mod.autoStart = flags.MustBool("chromium-auto-start")
 	mod.disableRoutes = flags.MustBool("chromium-disable-routes")
 	mod.maxConcurrency = flags.MustInt64("chromium-max-concurrency")
+	mod.enableSessions = flags.MustBool("chromium-enable-sessions")
+	mod.sessionTtl = flags.MustDuration("chromium-session-ttl")
+	mod.maxSessions = flags.MustInt("chromium-max-sessions")
 
 	binPath, ok := os.LookupEnv("CHROMIUM_BIN_PATH")
 	if !ok {
@@ -523,10 +620,7 @@ func (mod *Chromium) Provision(ctx *gotenberg.Context) error {
 		wsUrlReadTimeout:         flags.MustDuration("chromium-start-timeout"),
 		hyphenDataDirPath:        hyphenDataDirPath,
 
-		allowList:         flags.MustRegexpSlice("chromium-allow-list"),
-		denyList:          flags.MustRegexpSlice("chromium-deny-list"),
-		denyPrivateIPs:    flags.MustBool("chromium-deny-private-ips"),
-		denyPublicIPs:     flags.MustBool("chromium-deny-public-ips"),
+		policy:            newOutboundPolicy(flags),
 		clearCache:        flags.MustBool("chromium-clear-cache"),
 		clearCookies:      flags.MustBool("chromium-clear-cookies"),
 		clearStorage:      flags.MustBool("chromium-clear-storage"),
@@ -540,6 +634,11 @@ func (mod *Chromium) Provision(ctx *gotenberg.Context) error {
 	mod.browser = newChromiumBrowser(mod.args)
 	mod.supervisor = gotenberg.NewProcessSupervisor(mod.logger, "chromium", mod.browser, flags.MustInt64("chromium-restart-after"), flags.MustInt64("chromium-max-queue-size"), mod.maxConcurrency, flags.MustDuration("chromium-idle-shutdown-timeout"))
 
+	// Sessions.
+	if mod.enableSessions {
+		mod.sessions = newSessionStore(mod.maxSessions)
+	}
+
 	// PDF Engine.
 	provider, err := ctx.Module(new(gotenberg.PdfEngineProvider))
 	if err != nil {
@@ -581,6 +680,23 @@ func (mod *Chromium) Provision(ctx *gotenberg.Context) error {
 		return fmt.Errorf("create chromium.requests.queue_size gauge: %w", err)
 	}
 
+	_, err = meter.Int64ObservableGauge(
+		"chromium.requests.queue_size_by_priority",
+		metric.WithDescription("Current number of Chromium conversion requests waiting to be treated, per priority class"),
+		metric.WithUnit("{request}"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			for _, priority := range gotenberg.Priorities() {
+				o.Observe(mod.supervisor.ReqQueueSizeByPriority(priority), metric.WithAttributes(
+					attribute.String("priority", priority.String()),
+				))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("create chromium.requests.queue_size_by_priority gauge: %w", err)
+	}
+
 	_, err = meter.Int64ObservableCounter(
 		"chromium.process.restarts.total",
 		metric.WithDescription("Current number of Chromium restarts"),
@@ -681,6 +797,14 @@ func (mod *Chromium) Validate() error {
 		return fmt.Errorf("chromium-max-concurrency must be between 1 and 6, got %d", mod.maxConcurrency)
 	}
 
+	if mod.sessionTtl <= 0 {
+		return fmt.Errorf("chromium-session-ttl must be strictly positive, got %s", mod.sessionTtl)
+	}
+
+	if mod.maxSessions < 0 {
+		return fmt.Errorf("chromium-max-sessions must be positive, got %d", mod.maxSessions)
+	}
+
 	if mod.args.enableEnvironmentProxy {
 		proxyErr := gotenberg.ValidateEnvironmentProxyVariables()
 		if proxyErr != nil {
@@ -741,6 +865,31 @@ func (mod *Chromium) Stop(ctx context.Context) error {
 	return fmt.Errorf("stop Chromium: %w", err)
 }
 
+func newOutboundPolicy(flags gotenberg.ParsedFlags) *outboundPolicy {
+	return &outboundPolicy{
+		allowList:      flags.MustRegexpSlice("chromium-allow-list"),
+		denyList:       flags.MustRegexpSlice("chromium-deny-list"),
+		denyPrivateIPs: flags.MustBool("chromium-deny-private-ips"),
+		denyPublicIPs:  flags.MustBool("chromium-deny-public-ips"),
+	}
+}
+
+// Reload applies the new allowed/denied lists without restarting the
+// browser. In-flight conversions keep the ones they started with. The
+// IP-class options also set a command line flag of the browser, hence they
+// require a restart.
+func (mod *Chromium) Reload(flags gotenberg.ParsedFlags) error {
+	policy := newOutboundPolicy(flags)
+	if policy.denyPrivateIPs != mod.args.policy.denyPrivateIPs || policy.denyPublicIPs != mod.args.policy.denyPublicIPs {
+		return errors.New("changing the denial of private or public IPs requires a restart")
+	}
+
+	mod.browser.reload(policy)
+	mod.logger.Info("reloaded allow/deny lists")
+
+	return nil
+}
+
 // Debug returns additional debug data.
 func (mod *Chromium) Debug() map[string]any {
 	return map[string]any{"version": mod.detectVersion()}
@@ -795,6 +944,18 @@ func (mod *Chromium) Metrics() ([]gotenberg.Metric, error) {
 				return float64(mod.supervisor.ReqQueueSize())
 			},
 		},
+		{
+			Name:        "chromium_requests_queue_size_by_priority",
+			Description: "Current number of Chromium conversion requests waiting to be treated, per priority class.",
+			Label:       "priority",
+			ReadSeries: func() map[string]float64 {
+				sizes := make(map[string]float64)
+				for _, priority := range gotenberg.Priorities() {
+					sizes[priority.String()] = float64(mod.supervisor.ReqQueueSizeByPriority(priority))
+				}
+				return sizes
+			},
+		},
 		{
 			Name:        "chromium_restarts_count",
 			Description: "Current number of Chromium restarts.",
@@ -849,6 +1010,16 @@ func (mod *Chromium) Ready() error {
 	}
 }
 
+// QueuedCount returns the number of queued conversions.
+func (mod *Chromium) QueuedCount() int64 {
+	return mod.supervisor.ReqQueueSize()
+}
+
+// ActiveCount returns the number of active conversions.
+func (mod *Chromium) ActiveCount() int64 {
+	return mod.supervisor.ActiveTasksCount()
+}
+
 // Chromium returns an [Api] for interacting with Chromium for converting HTML
 // documents to PDF.
 func (mod *Chromium) Chromium() (Api, error) {
@@ -861,13 +1032,39 @@ func (mod *Chromium) Routes() ([]api.Route, error) {
 		return nil, nil
 	}
 
-	return []api.Route{
+	routes := []api.Route{
 		convertUrlRoute(mod, mod.engine),
-		screenshotUrlRoute(mod),
+		convertUrlsRoute(mod, mod.engine, mod.maxConcurrency),
+		screenshotUrlRoute(mod, mod.maxConcurrency),
 		convertHtmlRoute(mod, mod.engine),
-		screenshotHtmlRoute(mod),
+		screenshotHtmlRoute(mod, mod.maxConcurrency),
 		convertMarkdownRoute(mod, mod.engine),
-		screenshotMarkdownRoute(mod),
+		screenshotMarkdownRoute(mod, mod.maxConcurrency),
+	}
+
+	if mod.enableSessions {
+		routes = append(routes, createSessionRoute(mod), deleteSessionRoute(mod))
+
+		// A result depends on the state of its session, if any, not only on
+		// the session name.
+		for i := range routes {
+			routes[i].DisableCache = true
+		}
+	}
+
+	return routes, nil
+}
+
+// PipelineSteps returns the steps for the pipeline module.
+func (mod *Chromium) PipelineSteps() ([]pipeline.Step, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []pipeline.Step{
+		convertUrlStep(mod),
+		convertHtmlStep(mod),
+		convertMarkdownStep(mod),
 	}, nil
 }
 
@@ -891,11 +1088,17 @@ func (mod *Chromium) Pdf(ctx context.Context, logger *slog.Logger, url, outputPa
 		attribute.Int64("gotenberg.conversions_since_last_restart", mod.supervisor.ConversionsSinceRestart()),
 	)
 
+	session, err := mod.resolveSession(options.Options)
+	if err != nil {
+		return err
+	}
+	options.session = session
+
 	start := time.Now()
 	var conversionStart time.Time
 
 	aggregate := newNetworkAggregate()
-	err := mod.supervisor.Run(ctx, logger, func() error {
+	err = mod.supervisor.Run(ctx, logger, func() error {
 		conversionStart = time.Now()
 		return mod.browser.pdf(ctx, logger, url, outputPath, options, aggregate)
 	})
@@ -942,6 +1145,8 @@ func (mod *Chromium) Pdf(ctx context.Context, logger *slog.Logger, url, outputPa
 	mod.recordNetwork(ctx, span, aggregate)
 
 	if err == nil {
+		gotenberg.RecordEngine(ctx, "chromium")
+
 		if fileInfo, statErr := os.Stat(outputPath); statErr == nil {
 			mod.pdfOutputSizeCounter.Record(ctx, fileInfo.Size())
 			span.SetAttributes(attribute.Int64("gotenberg.conversion.output.bytes", fileInfo.Size()))
@@ -971,11 +1176,17 @@ func (mod *Chromium) Screenshot(ctx context.Context, logger *slog.Logger, url, o
 		attribute.Int64("gotenberg.conversions_since_last_restart", mod.supervisor.ConversionsSinceRestart()),
 	)
 
+	session, err := mod.resolveSession(options.Options)
+	if err != nil {
+		return err
+	}
+	options.session = session
+
 	start := time.Now()
 	var conversionStart time.Time
 
 	aggregate := newNetworkAggregate()
-	err := mod.supervisor.Run(ctx, logger, func() error {
+	err = mod.supervisor.Run(ctx, logger, func() error {
 		conversionStart = time.Now()
 		return mod.browser.screenshot(ctx, logger, url, outputPath, options, aggregate)
 	})
@@ -1022,6 +1233,8 @@ func (mod *Chromium) Screenshot(ctx context.Context, logger *slog.Logger, url, o
 	mod.recordNetwork(ctx, span, aggregate)
 
 	if err == nil {
+		gotenberg.RecordEngine(ctx, "chromium")
+
 		if fileInfo, statErr := os.Stat(outputPath); statErr == nil {
 			mod.imageOutputSizeCounter.Record(ctx, fileInfo.Size())
 		}
@@ -1035,6 +1248,63 @@ func (mod *Chromium) Screenshot(ctx context.Context, logger *slog.Logger, url, o
 	return err
 }
 
+// resolveSession returns the state of [Options.Session], if any.
+func (mod *Chromium) resolveSession(options Options) (*sessionState, error) {
+	if options.Session == "" {
+		return nil, nil
+	}
+
+	session, ok := mod.sessions.get(options.SessionOwner, options.Session)
+	if !ok {
+		return nil, fmt.Errorf("session '%s': %w", options.Session, ErrSessionNotFound)
+	}
+
+	return session, nil
+}
+
+// createSession runs a login script from a URL, then keeps the resulting
+// cookies and local storage until the given time-to-live expires.
+func (mod *Chromium) createSession(ctx context.Context, logger *slog.Logger, name, url string, steps []LoginStep, ttl time.Duration, options Options) (time.Time, error) {
+	ctx, span := gotenberg.Tracer().Start(ctx, "chromium.CreateSession",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(mod.spanAttrs()...),
+	)
+	defer span.End()
+
+	session, err := mod.resolveSession(options)
+	if err != nil {
+		return time.Time{}, err
+	}
+	options.session = session
+
+	var state *sessionState
+	aggregate := newNetworkAggregate()
+	err = mod.supervisor.Run(ctx, logger, func() error {
+		var loginErr error
+		state, loginErr = mod.browser.login(ctx, logger, url, steps, options, aggregate)
+		return loginErr
+	})
+	mod.recordNetwork(ctx, span, aggregate)
+	if err != nil {
+		reason := chromiumErrorType(err, "chromium_unavailable")
+		mod.errsCounter.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("reason", reason),
+		))
+		gotenberg.SpanErrorType(span, reason)
+
+		return time.Time{}, err
+	}
+
+	state.expiresAt = time.Now().Add(ttl)
+
+	err = mod.sessions.put(options.SessionOwner, name, state)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("store session: %w", err)
+	}
+
+	return state.expiresAt, nil
+}
+
 // recordNetwork lifts per-conversion network aggregates onto the span and the
 // network metrics. Counts are dimensioned by outcome and bytes feed a
 // histogram; both are recorded with the conversion context so the SDK attaches
@@ -1104,7 +1374,9 @@ func chromiumErrorType(err error, queueReason string) string {
 		errors.Is(err, ErrLoadingFailed),
 		errors.Is(err, ErrResourceLoadingFailed),
 		errors.Is(err, ErrInvalidEvaluationExpression),
-		errors.Is(err, ErrInvalidSelectorQuery):
+		errors.Is(err, ErrInvalidSelectorQuery),
+		errors.Is(err, ErrSessionNotFound),
+		errors.Is(err, ErrLoginStepFailed):
 		return gotenberg.ErrorTypeInvalidInput
 	case errors.Is(err, gotenberg.ErrMaximumQueueSizeExceeded):
 		return queueReason
@@ -1123,8 +1395,11 @@ var (
 	_ gotenberg.App             = (*Chromium)(nil)
 	_ gotenberg.Debuggable      = (*Chromium)(nil)
 	_ gotenberg.MetricsProvider = (*Chromium)(nil)
+	_ gotenberg.Reloader        = (*Chromium)(nil)
 	_ api.HealthChecker         = (*Chromium)(nil)
+	_ api.QueueCounter          = (*Chromium)(nil)
 	_ api.Router                = (*Chromium)(nil)
+	_ pipeline.StepProvider     = (*Chromium)(nil)
 	_ Api                       = (*Chromium)(nil)
 	_ Provider                  = (*Chromium)(nil)
 )
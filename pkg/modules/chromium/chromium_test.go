@@ -0,0 +1,100 @@
+package chromium
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestChromium_Reload(t *testing.T) {
+	newFlags := func(t *testing.T, args ...string) gotenberg.ParsedFlags {
+		fs := new(Chromium).Descriptor().FlagSet
+		err := fs.Parse(args)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+		return gotenberg.ParsedFlags{FlagSet: fs}
+	}
+
+	for _, tc := range []struct {
+		scenario     string
+		args         []string
+		expectError  bool
+		expectReload bool
+	}{
+		{
+			scenario:     "allow and deny lists",
+			args:         []string{"--chromium-allow-list=^https://", "--chromium-deny-private-ips"},
+			expectReload: true,
+		},
+		{
+			scenario:    "private IPs",
+			args:        []string{"--chromium-deny-private-ips=false"},
+			expectError: true,
+		},
+		{
+			scenario:    "public IPs",
+			args:        []string{"--chromium-deny-private-ips", "--chromium-deny-public-ips"},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			reloaded := false
+			mod := &Chromium{
+				args:   browserArguments{policy: &outboundPolicy{denyPrivateIPs: true}},
+				logger: slog.New(slog.DiscardHandler),
+				browser: &browserMock{
+					reloadMock: func(policy *outboundPolicy) {
+						reloaded = true
+					},
+				},
+			}
+
+			err := mod.Reload(newFlags(t, tc.args...))
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if reloaded != tc.expectReload {
+				t.Errorf("expected reload %t, but got %t", tc.expectReload, reloaded)
+			}
+		})
+	}
+}
+
+func TestChromium_Routes(t *testing.T) {
+	remote := map[string]bool{
+		"/forms/chromium/convert/url":    true,
+		"/forms/chromium/convert/urls":   true,
+		"/forms/chromium/screenshot/url": true,
+	}
+
+	routes, err := new(Chromium).Routes()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, route := range routes {
+		if route.DisableCache != remote[route.Path] {
+			t.Errorf("expected route '%s' to have DisableCache %t, but got %t", route.Path, remote[route.Path], route.DisableCache)
+		}
+	}
+
+	// A result depends on the state of its session.
+	routes, err = (&Chromium{enableSessions: true}).Routes()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, route := range routes {
+		if !route.DisableCache {
+			t.Errorf("expected route '%s' to not be cached with sessions", route.Path)
+		}
+	}
+}
@@ -0,0 +1,591 @@
+package chromium
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// maxDiagnosticsEntries bounds the requests and the console messages kept per
+// conversion so a pathological page cannot grow the diagnostics without limit.
+const maxDiagnosticsEntries = 1000
+
+// diagnosticsRequest is the state of a request, from the Network domain
+// events.
+type diagnosticsRequest struct {
+	resourceType      network.ResourceType
+	request           *network.Request
+	startedAt         time.Time
+	startedMono       time.Time
+	response          *network.Response
+	endedMono         time.Time
+	encodedDataLength float64
+	errorText         string
+	blockedReason     string
+	// filterReason is why the outbound filter failed the request, if so.
+	filterReason string
+}
+
+// consoleMessage is an entry of the console log.
+type consoleMessage struct {
+	Type         string    `json:"type"`
+	Text         string    `json:"text"`
+	Timestamp    time.Time `json:"timestamp"`
+	Url          string    `json:"url,omitempty"`
+	LineNumber   *int64    `json:"lineNumber,omitempty"`
+	ColumnNumber *int64    `json:"columnNumber,omitempty"`
+}
+
+// diagnosticsRecorder records the requests and the console messages of a
+// conversion. It is safe for concurrent use by the chromedp event listener
+// goroutines and the conversion goroutine that writes the artifacts
+// afterwards. A nil recorder records nothing.
+type diagnosticsRecorder struct {
+	mu sync.Mutex
+
+	requests []*diagnosticsRequest
+	pending  map[network.RequestID]*diagnosticsRequest
+	// filterReasons are the reasons why the outbound filter failed requests
+	// not started yet, by request id.
+	filterReasons map[network.RequestID]string
+	console       []consoleMessage
+}
+
+func newDiagnosticsRecorder() *diagnosticsRecorder {
+	return &diagnosticsRecorder{
+		pending:       make(map[network.RequestID]*diagnosticsRequest),
+		filterReasons: make(map[network.RequestID]string),
+	}
+}
+
+// onRequestWillBeSent starts a request. A redirect ends the previous request
+// with the same id.
+func (r *diagnosticsRecorder) onRequestWillBeSent(ev *network.EventRequestWillBeSent) {
+	if r == nil || ev == nil || ev.Request == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if previous, ok := r.pending[ev.RequestID]; ok && ev.RedirectResponse != nil {
+		previous.response = ev.RedirectResponse
+		previous.endedMono = monotonicTime(ev.Timestamp)
+	}
+
+	if len(r.requests) >= maxDiagnosticsEntries {
+		delete(r.pending, ev.RequestID)
+		return
+	}
+
+	req := &diagnosticsRequest{
+		resourceType: ev.Type,
+		request:      ev.Request,
+		startedMono:  monotonicTime(ev.Timestamp),
+		filterReason: r.filterReasons[ev.RequestID],
+	}
+	delete(r.filterReasons, ev.RequestID)
+	if ev.WallTime != nil {
+		req.startedAt = ev.WallTime.Time()
+	}
+
+	r.requests = append(r.requests, req)
+	r.pending[ev.RequestID] = req
+}
+
+// onResponseReceived records the response of a request.
+func (r *diagnosticsRecorder) onResponseReceived(ev *network.EventResponseReceived) {
+	if r == nil || ev == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if req, ok := r.pending[ev.RequestID]; ok {
+		req.response = ev.Response
+	}
+}
+
+// onLoadingFinished ends a successful request.
+func (r *diagnosticsRecorder) onLoadingFinished(ev *network.EventLoadingFinished) {
+	if r == nil || ev == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	req, ok := r.pending[ev.RequestID]
+	if !ok {
+		return
+	}
+
+	req.endedMono = monotonicTime(ev.Timestamp)
+	req.encodedDataLength = ev.EncodedDataLength
+	delete(r.pending, ev.RequestID)
+}
+
+// onLoadingFailed ends a failed request.
+func (r *diagnosticsRecorder) onLoadingFailed(ev *network.EventLoadingFailed) {
+	if r == nil || ev == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	req, ok := r.pending[ev.RequestID]
+	if !ok {
+		return
+	}
+
+	req.endedMono = monotonicTime(ev.Timestamp)
+	req.errorText = ev.ErrorText
+	req.blockedReason = ev.BlockedReason.String()
+	delete(r.pending, ev.RequestID)
+}
+
+// onRequestBlocked records why the outbound filter failed a request. See
+// [listenForEventRequestPaused].
+func (r *diagnosticsRecorder) onRequestBlocked(requestID network.RequestID, reason string) {
+	if r == nil || requestID == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if req, ok := r.pending[requestID]; ok {
+		req.filterReason = reason
+		return
+	}
+
+	if len(r.filterReasons) < maxDiagnosticsEntries {
+		r.filterReasons[requestID] = reason
+	}
+}
+
+// onConsoleAPICalled records a call to the console API, e.g., console.log.
+func (r *diagnosticsRecorder) onConsoleAPICalled(ev *runtime.EventConsoleAPICalled) {
+	if r == nil || ev == nil {
+		return
+	}
+
+	msg := consoleMessage{
+		Type: ev.Type.String(),
+		Text: consoleText(ev.Args),
+	}
+	if ev.Timestamp != nil {
+		msg.Timestamp = ev.Timestamp.Time().UTC()
+	}
+	if ev.StackTrace != nil && len(ev.StackTrace.CallFrames) > 0 {
+		frame := ev.StackTrace.CallFrames[0]
+		msg.Url = frame.URL
+		msg.LineNumber = &frame.LineNumber
+		msg.ColumnNumber = &frame.ColumnNumber
+	}
+
+	r.addConsoleMessage(msg)
+}
+
+// onExceptionThrown records an uncaught exception.
+func (r *diagnosticsRecorder) onExceptionThrown(ev *runtime.EventExceptionThrown) {
+	if r == nil || ev == nil || ev.ExceptionDetails == nil {
+		return
+	}
+
+	details := ev.ExceptionDetails
+
+	text := details.Text
+	if details.Exception != nil && details.Exception.Description != "" {
+		text = details.Exception.Description
+	}
+
+	msg := consoleMessage{
+		Type:         "exception",
+		Text:         text,
+		Url:          details.URL,
+		LineNumber:   &details.LineNumber,
+		ColumnNumber: &details.ColumnNumber,
+	}
+	if ev.Timestamp != nil {
+		msg.Timestamp = ev.Timestamp.Time().UTC()
+	}
+
+	r.addConsoleMessage(msg)
+}
+
+func (r *diagnosticsRecorder) addConsoleMessage(msg consoleMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.console) >= maxDiagnosticsEntries {
+		return
+	}
+
+	r.console = append(r.console, msg)
+}
+
+// write writes the HAR file and the console log.
+func (r *diagnosticsRecorder) write(harPath, consoleLogPath string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if harPath != "" {
+		err := writeJson(harPath, r.har())
+		if err != nil {
+			return fmt.Errorf("write HAR file: %w", err)
+		}
+	}
+
+	if consoleLogPath == "" {
+		return nil
+	}
+
+	console := r.console
+	if console == nil {
+		console = []consoleMessage{}
+	}
+
+	sort.SliceStable(console, func(i, j int) bool {
+		return console[i].Timestamp.Before(console[j].Timestamp)
+	})
+
+	err := writeJson(consoleLogPath, console)
+	if err != nil {
+		return fmt.Errorf("write console log: %w", err)
+	}
+
+	return nil
+}
+
+// withDiagnosticsPaths sets where to write the diagnostics, if asked for,
+// within the working directory of the context. The suffix tells apart the
+// diagnostics of many conversions of the same request.
+func withDiagnosticsPaths(ctx *api.Context, options *Options, suffix string) {
+	if !options.Diagnostics {
+		return
+	}
+
+	options.HarPath = ctx.GeneratePathFromFilename(fmt.Sprintf("diagnostics%s.har", suffix))
+	options.ConsoleLogPath = ctx.GeneratePathFromFilename(fmt.Sprintf("console%s.json", suffix))
+}
+
+// addDiagnosticsOutputPaths adds the diagnostics, if any, to the output
+// files.
+func addDiagnosticsOutputPaths(ctx *api.Context, options Options) error {
+	if options.HarPath == "" {
+		return nil
+	}
+
+	err := ctx.AddOutputPaths(options.HarPath, options.ConsoleLogPath)
+	if err != nil {
+		return fmt.Errorf("add diagnostics output paths: %w", err)
+	}
+
+	return nil
+}
+
+func writeJson(path string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	return os.WriteFile(path, b, 0o600)
+}
+
+// HAR 1.2 format, see http://www.softwareishard.com/blog/har-12-spec/.
+type (
+	harFile struct {
+		Log harLog `json:"log"`
+	}
+
+	harLog struct {
+		Version string     `json:"version"`
+		Creator harCreator `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	}
+
+	harCreator struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	harEntry struct {
+		StartedDateTime string      `json:"startedDateTime"`
+		Time            float64     `json:"time"`
+		Request         harRequest  `json:"request"`
+		Response        harResponse `json:"response"`
+		Cache           struct{}    `json:"cache"`
+		Timings         harTimings  `json:"timings"`
+		ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+		// Custom fields.
+		ResourceType  string `json:"_resourceType,omitempty"`
+		Error         string `json:"_error,omitempty"`
+		BlockedReason string `json:"_blockedReason,omitempty"`
+	}
+
+	harRequest struct {
+		Method      string         `json:"method"`
+		Url         string         `json:"url"`
+		HttpVersion string         `json:"httpVersion"`
+		Cookies     []harNameValue `json:"cookies"`
+		Headers     []harNameValue `json:"headers"`
+		QueryString []harNameValue `json:"queryString"`
+		HeadersSize int            `json:"headersSize"`
+		BodySize    int            `json:"bodySize"`
+	}
+
+	harResponse struct {
+		Status      int64          `json:"status"`
+		StatusText  string         `json:"statusText"`
+		HttpVersion string         `json:"httpVersion"`
+		Cookies     []harNameValue `json:"cookies"`
+		Headers     []harNameValue `json:"headers"`
+		Content     harContent     `json:"content"`
+		RedirectUrl string         `json:"redirectURL"`
+		HeadersSize int            `json:"headersSize"`
+		BodySize    int64          `json:"bodySize"`
+	}
+
+	harContent struct {
+		Size     int64  `json:"size"`
+		MimeType string `json:"mimeType"`
+	}
+
+	harNameValue struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	harTimings struct {
+		Blocked float64 `json:"blocked"`
+		Dns     float64 `json:"dns"`
+		Connect float64 `json:"connect"`
+		Send    float64 `json:"send"`
+		Wait    float64 `json:"wait"`
+		Receive float64 `json:"receive"`
+		Ssl     float64 `json:"ssl"`
+	}
+)
+
+// har returns the HAR representation of the recorded requests. The caller
+// must hold the lock.
+func (r *diagnosticsRecorder) har() harFile {
+	entries := make([]harEntry, 0, len(r.requests))
+	for _, req := range r.requests {
+		entry := harEntry{
+			StartedDateTime: req.startedAt.UTC().Format(time.RFC3339Nano),
+			Request: harRequest{
+				Method:      req.request.Method,
+				Url:         req.request.URL + req.request.URLFragment,
+				HttpVersion: "",
+				Cookies:     []harNameValue{},
+				Headers:     harHeaders(req.request.Headers),
+				QueryString: harQueryString(req.request.URL),
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			Response: harResponse{
+				Cookies: []harNameValue{},
+				Headers: []harNameValue{},
+				Content: harContent{
+					MimeType: "x-unknown",
+				},
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			Timings: harTimings{
+				Blocked: -1,
+				Dns:     -1,
+				Connect: -1,
+				Ssl:     -1,
+			},
+			ResourceType:  req.resourceType.String(),
+			Error:         req.errorText,
+			BlockedReason: req.blockedReason,
+		}
+
+		if req.filterReason != "" {
+			entry.BlockedReason = req.filterReason
+		}
+
+		if !req.endedMono.IsZero() && !req.startedMono.IsZero() {
+			entry.Time = milliseconds(req.endedMono.Sub(req.startedMono))
+		}
+
+		if res := req.response; res != nil {
+			entry.Request.HttpVersion = res.Protocol
+			entry.Response.Status = res.Status
+			entry.Response.StatusText = res.StatusText
+			entry.Response.HttpVersion = res.Protocol
+			entry.Response.Headers = harHeaders(res.Headers)
+			entry.Response.Content.Size = int64(req.encodedDataLength)
+			entry.Response.BodySize = int64(req.encodedDataLength)
+			entry.ServerIPAddress = res.RemoteIPAddress
+
+			if res.MimeType != "" {
+				entry.Response.Content.MimeType = res.MimeType
+			}
+
+			if location, ok := res.Headers["Location"].(string); ok {
+				entry.Response.RedirectUrl = location
+			} else if location, ok := res.Headers["location"].(string); ok {
+				entry.Response.RedirectUrl = location
+			}
+
+			if res.Timing != nil {
+				entry.Timings = harTimingsOf(res.Timing, req.endedMono)
+				entry.Time = entry.Timings.total()
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return harFile{
+		Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{
+				Name:    "Gotenberg",
+				Version: gotenberg.Version,
+			},
+			Entries: entries,
+		},
+	}
+}
+
+// harTimingsOf converts the timing of a response, relative to its request
+// time in milliseconds, to HAR timings.
+func harTimingsOf(timing *network.ResourceTiming, endedMono time.Time) harTimings {
+	span := func(start, end float64) float64 {
+		if start < 0 || end < 0 {
+			return -1
+		}
+		return end - start
+	}
+
+	timings := harTimings{
+		Blocked: -1,
+		Dns:     span(timing.DNSStart, timing.DNSEnd),
+		Connect: span(timing.ConnectStart, timing.ConnectEnd),
+		Ssl:     span(timing.SslStart, timing.SslEnd),
+		Send:    max(0, span(timing.SendStart, timing.SendEnd)),
+		Wait:    max(0, span(timing.SendEnd, timing.ReceiveHeadersEnd)),
+	}
+
+	if !endedMono.IsZero() && cdp.MonotonicTimeEpoch != nil {
+		requestTime := cdp.MonotonicTimeEpoch.Add(time.Duration(timing.RequestTime * float64(time.Second)))
+		timings.Receive = max(0, milliseconds(endedMono.Sub(requestTime))-timing.ReceiveHeadersEnd)
+	}
+
+	return timings
+}
+
+// total returns the total time of the timings, i.e., the sum of the known
+// ones. The SSL time is already part of the connect time.
+func (t harTimings) total() float64 {
+	var total float64
+	for _, v := range []float64{t.Blocked, t.Dns, t.Connect, t.Send, t.Wait, t.Receive} {
+		if v > 0 {
+			total += v
+		}
+	}
+
+	return total
+}
+
+func harHeaders(headers network.Headers) []harNameValue {
+	values := make([]harNameValue, 0, len(headers))
+	for name, value := range headers {
+		values = append(values, harNameValue{Name: name, Value: fmt.Sprintf("%v", value)})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name < values[j].Name
+	})
+
+	return values
+}
+
+func harQueryString(rawURL string) []harNameValue {
+	values := []harNameValue{}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return values
+	}
+
+	query := parsed.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range query[name] {
+			values = append(values, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return values
+}
+
+// consoleText returns the text of the arguments of a console API call, the
+// way the DevTools console displays the primitive ones.
+func consoleText(args []*runtime.RemoteObject) string {
+	var text string
+	for i, arg := range args {
+		if i > 0 {
+			text += " "
+		}
+
+		switch {
+		case arg == nil:
+		case len(arg.Value) > 0:
+			var s string
+			if json.Unmarshal(arg.Value, &s) == nil {
+				text += s
+			} else {
+				text += string(arg.Value)
+			}
+		case arg.UnserializableValue != "":
+			text += string(arg.UnserializableValue)
+		case arg.Description != "":
+			text += arg.Description
+		default:
+			text += arg.Type.String()
+		}
+	}
+
+	return text
+}
+
+func monotonicTime(t *cdp.MonotonicTime) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return t.Time()
+}
+
+// milliseconds returns a duration in milliseconds, with a microsecond
+// precision.
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+}
@@ -0,0 +1,158 @@
+package chromium
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+)
+
+func TestDiagnosticsRecorder(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mono := func(offset time.Duration) *cdp.MonotonicTime {
+		t := cdp.MonotonicTime(start.Add(offset))
+		return &t
+	}
+	wall := cdp.TimeSinceEpoch(start)
+
+	recorder := newDiagnosticsRecorder()
+
+	// A request redirected, then finished.
+	recorder.onRequestWillBeSent(&network.EventRequestWillBeSent{
+		RequestID: "1",
+		Request:   &network.Request{Method: "GET", URL: "http://a.example/?b=2&a=1"},
+		Timestamp: mono(0),
+		WallTime:  &wall,
+		Type:      network.ResourceTypeDocument,
+	})
+	recorder.onRequestWillBeSent(&network.EventRequestWillBeSent{
+		RequestID:        "1",
+		Request:          &network.Request{Method: "GET", URL: "http://a.example/home"},
+		Timestamp:        mono(10 * time.Millisecond),
+		WallTime:         &wall,
+		RedirectResponse: &network.Response{Status: 302, Headers: network.Headers{"Location": "/home"}},
+		Type:             network.ResourceTypeDocument,
+	})
+	recorder.onResponseReceived(&network.EventResponseReceived{
+		RequestID: "1",
+		Response:  &network.Response{Status: 200, StatusText: "OK", MimeType: "text/html", Protocol: "http/1.1"},
+	})
+	recorder.onLoadingFinished(&network.EventLoadingFinished{
+		RequestID:         "1",
+		Timestamp:         mono(30 * time.Millisecond),
+		EncodedDataLength: 42,
+	})
+
+	// A request the outbound filter fails, whatever the order of the events.
+	recorder.onRequestBlocked("2", "'http://b.example/' does not match the authorized URLs")
+	recorder.onRequestWillBeSent(&network.EventRequestWillBeSent{
+		RequestID: "2",
+		Request:   &network.Request{Method: "GET", URL: "http://b.example/"},
+		Timestamp: mono(0),
+		WallTime:  &wall,
+		Type:      network.ResourceTypeScript,
+	})
+	recorder.onLoadingFailed(&network.EventLoadingFailed{
+		RequestID: "2",
+		Timestamp: mono(5 * time.Millisecond),
+		ErrorText: "net::ERR_ACCESS_DENIED",
+	})
+
+	recorder.onConsoleAPICalled(&runtime.EventConsoleAPICalled{
+		Type: runtime.APITypeLog,
+		Args: []*runtime.RemoteObject{
+			{Type: runtime.TypeString, Value: []byte(`"foo"`)},
+			{Type: runtime.TypeNumber, Value: []byte(`42`)},
+			{Type: runtime.TypeObject, Description: "Object"},
+		},
+	})
+
+	har := recorder.har()
+
+	if har.Log.Version != "1.2" {
+		t.Errorf("expected HAR version 1.2 but got '%s'", har.Log.Version)
+	}
+
+	if len(har.Log.Entries) != 3 {
+		t.Fatalf("expected 3 entries but got %d", len(har.Log.Entries))
+	}
+
+	redirect := har.Log.Entries[0]
+	if redirect.Response.Status != 302 || redirect.Response.RedirectUrl != "/home" || redirect.Time != 10 {
+		t.Errorf("expected a 302 redirect to '/home' in 10ms but got %+v", redirect)
+	}
+	if want := []harNameValue{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}}; !reflect.DeepEqual(redirect.Request.QueryString, want) {
+		t.Errorf("expected query string %+v but got %+v", want, redirect.Request.QueryString)
+	}
+	if redirect.StartedDateTime != "2026-01-02T03:04:05Z" {
+		t.Errorf("expected started date time '2026-01-02T03:04:05Z' but got '%s'", redirect.StartedDateTime)
+	}
+
+	home := har.Log.Entries[1]
+	if home.Response.Status != 200 || home.Response.Content.Size != 42 || home.Response.Content.MimeType != "text/html" || home.Time != 20 {
+		t.Errorf("expected a 200 response of 42 bytes in 20ms but got %+v", home)
+	}
+
+	blocked := har.Log.Entries[2]
+	if blocked.Response.Status != 0 || blocked.Error != "net::ERR_ACCESS_DENIED" || blocked.BlockedReason != "'http://b.example/' does not match the authorized URLs" {
+		t.Errorf("expected a request blocked by the outbound filter but got %+v", blocked)
+	}
+
+	dir := t.TempDir()
+	harPath := filepath.Join(dir, "diagnostics.har")
+	consoleLogPath := filepath.Join(dir, "console.json")
+
+	err := recorder.write(harPath, consoleLogPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	b, err := os.ReadFile(consoleLogPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var console []consoleMessage
+	err = json.Unmarshal(b, &console)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(console) != 1 || console[0].Type != "log" || console[0].Text != "foo 42 Object" {
+		t.Errorf("expected a 'foo 42 Object' log but got %+v", console)
+	}
+
+	_, err = os.Stat(harPath)
+	if err != nil {
+		t.Errorf("expected a HAR file but got: %v", err)
+	}
+}
+
+func TestHarTimingsOf(t *testing.T) {
+	timings := harTimingsOf(&network.ResourceTiming{
+		DNSStart:          -1,
+		DNSEnd:            -1,
+		ConnectStart:      1,
+		ConnectEnd:        4,
+		SslStart:          -1,
+		SslEnd:            -1,
+		SendStart:         5,
+		SendEnd:           6,
+		ReceiveHeadersEnd: 16,
+	}, time.Time{})
+
+	expect := harTimings{Blocked: -1, Dns: -1, Connect: 3, Ssl: -1, Send: 1, Wait: 10}
+	if timings != expect {
+		t.Errorf("expected %+v but got %+v", expect, timings)
+	}
+
+	if timings.total() != 14 {
+		t.Errorf("expected a total of 14ms but got %v", timings.total())
+	}
+}
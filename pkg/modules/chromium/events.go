@@ -44,6 +44,27 @@ func listenForNetworkActivity(ctx context.Context, aggregate *networkAggregate)
 	})
 }
 
+// listenForDiagnostics records the requests and the console messages of a
+// conversion into recorder.
+func listenForDiagnostics(ctx context.Context, recorder *diagnosticsRecorder) {
+	chromedp.ListenTarget(ctx, func(ev any) {
+		switch e := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			recorder.onRequestWillBeSent(e)
+		case *network.EventResponseReceived:
+			recorder.onResponseReceived(e)
+		case *network.EventLoadingFinished:
+			recorder.onLoadingFinished(e)
+		case *network.EventLoadingFailed:
+			recorder.onLoadingFailed(e)
+		case *runtime.EventConsoleAPICalled:
+			recorder.onConsoleAPICalled(e)
+		case *runtime.EventExceptionThrown:
+			recorder.onExceptionThrown(e)
+		}
+	})
+}
+
 type eventWebSocketCreatedOptions struct {
 	allowList, denyList []*regexp2.Regexp
 	denyPrivateIPs      bool
@@ -98,6 +119,7 @@ type eventRequestPausedOptions struct {
 	denyPublicIPs       bool
 	allowedFilePrefixes []string
 	extraHttpHeaders    []ExtraHttpHeader
+	diagnostics         *diagnosticsRecorder
 }
 
 // listenForEventRequestPaused listens for requests to check if they are
@@ -134,6 +156,7 @@ func listenForEventRequestPaused(ctx context.Context, logger *slog.Logger, optio
 				)
 				if err != nil {
 					logger.WarnContext(ctx, err.Error())
+					options.diagnostics.onRequestBlocked(e.NetworkID, err.Error())
 					allow = false
 				}
 
@@ -148,6 +171,7 @@ func listenForEventRequestPaused(ctx context.Context, logger *slog.Logger, optio
 				// directories of other in-flight conversions.
 				if allow && strings.HasPrefix(e.Request.URL, "file://") && !isAllowedFileSubResource(e.Request.URL, options.allowedFilePrefixes) {
 					logger.WarnContext(ctx, fmt.Sprintf("'%s' is not within any allowed file prefix", e.Request.URL))
+					options.diagnostics.onRequestBlocked(e.NetworkID, "not within any allowed file prefix")
 					allow = false
 				}
 
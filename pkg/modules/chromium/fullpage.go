@@ -0,0 +1,192 @@
+package chromium
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"log/slog"
+	"math"
+	"os"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// maxTileHeight is the height in physical pixels of the tiles of a
+	// full-page screenshot. Chromium fails to capture beyond its maximum
+	// texture size, i.e., 16384 pixels.
+	maxTileHeight = 16384
+
+	// maxFullPageHeight bounds the height in physical pixels of a full-page
+	// screenshot, as JPEG does not go beyond.
+	maxFullPageHeight = 65535
+
+	// maxFullPageWidth bounds the width in physical pixels of a full-page
+	// screenshot, as a tile does not go beyond Chromium's maximum texture
+	// size either.
+	maxFullPageWidth = 16384
+
+	// maxFullPagePixels bounds the number of physical pixels of a full-page
+	// screenshot, as the canvas of the stitched tiles takes 4 bytes per
+	// pixel, i.e., 256 MiB at most.
+	maxFullPagePixels = 64 * 1024 * 1024
+)
+
+// captureFullPageActionFunc captures the entire scroll height of the page.
+// A page higher than a tile is captured tile by tile, and the tiles stitched
+// together.
+func captureFullPageActionFunc(logger *slog.Logger, outputPath string, options ScreenshotOptions) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		_, _, _, _, _, cssContentSize, err := page.GetLayoutMetrics().Do(ctx)
+		if err != nil {
+			return fmt.Errorf("get layout metrics: %w", err)
+		}
+
+		scale := options.DeviceScaleFactor
+		if scale <= 0 {
+			scale = 1
+		}
+
+		contentWidth := math.Ceil(cssContentSize.Width)
+		contentHeight := math.Ceil(cssContentSize.Height)
+		width, height := clipFullPage(contentWidth, contentHeight, scale)
+		if width != contentWidth || height != contentHeight {
+			logger.WarnContext(ctx, fmt.Sprintf("full page of %.0fx%.0f pixels clipped to %.0fx%.0f pixels", contentWidth*scale, contentHeight*scale, width*scale, height*scale))
+		}
+
+		tileHeight := math.Floor(maxTileHeight / scale)
+
+		captureTile := func(y, h float64, format page.CaptureScreenshotFormat) ([]byte, error) {
+			captureScreenshot := page.CaptureScreenshot().
+				WithCaptureBeyondViewport(true).
+				WithFromSurface(true).
+				WithOptimizeForSpeed(options.OptimizeForSpeed).
+				WithFormat(format).
+				WithClip(&page.Viewport{
+					X:      cssContentSize.X,
+					Y:      cssContentSize.Y + y,
+					Width:  width,
+					Height: h,
+					Scale:  1,
+				})
+
+			if format == page.CaptureScreenshotFormatJpeg {
+				captureScreenshot = captureScreenshot.WithQuality(int64(options.Quality))
+			}
+
+			buffer, err := captureScreenshot.Do(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("capture screenshot: %w", err)
+			}
+
+			return buffer, nil
+		}
+
+		var buffer []byte
+		if height <= tileHeight {
+			logger.DebugContext(ctx, fmt.Sprintf("capture full page of %.0fx%.0f", width, height))
+
+			buffer, err = captureTile(0, height, page.CaptureScreenshotFormat(options.Format))
+			if err != nil {
+				return err
+			}
+		} else {
+			var tiles [][]byte
+			for y := 0.0; y < height; y += tileHeight {
+				logger.DebugContext(ctx, fmt.Sprintf("capture full page tile %d", len(tiles)))
+
+				tile, err := captureTile(y, min(tileHeight, height-y), page.CaptureScreenshotFormatPng)
+				if err != nil {
+					return err
+				}
+				tiles = append(tiles, tile)
+			}
+
+			logger.DebugContext(ctx, fmt.Sprintf("stitch %d tiles of a full page of %.0fx%.0f", len(tiles), width, height))
+
+			buffer, err = stitchTiles(tiles, options.Format, options.Quality)
+			if err != nil {
+				return fmt.Errorf("stitch tiles: %w", err)
+			}
+		}
+
+		err = os.WriteFile(outputPath, buffer, 0o600)
+		if err != nil {
+			return fmt.Errorf("write result to output path: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// clipFullPage returns the size in CSS pixels of a full-page screenshot of a
+// page of the given size, so that it does not exceed the maximum width,
+// height and number of physical pixels.
+func clipFullPage(width, height, scale float64) (float64, float64) {
+	width = min(width, math.Floor(maxFullPageWidth/scale))
+	height = min(height, math.Floor(maxFullPageHeight/scale))
+
+	if width > 0 {
+		height = min(height, math.Floor(maxFullPagePixels/(width*scale*scale)))
+	}
+
+	return width, height
+}
+
+// stitchTiles stacks the PNG tiles vertically, and encodes the result in the
+// given format, either "png" or "jpeg".
+func stitchTiles(tiles [][]byte, format string, quality int) ([]byte, error) {
+	if format != "png" && format != "jpeg" {
+		return nil, fmt.Errorf("unsupported format '%s'", format)
+	}
+
+	// The tiles are decoded one at a time, so that only the canvas stays in
+	// memory.
+	var width, height int
+	for i, tile := range tiles {
+		config, err := png.DecodeConfig(bytes.NewReader(tile))
+		if err != nil {
+			return nil, fmt.Errorf("decode config of tile %d: %w", i, err)
+		}
+
+		width = max(width, config.Width)
+		height += config.Height
+	}
+
+	if width*height > maxFullPagePixels {
+		return nil, fmt.Errorf("canvas of %dx%d pixels exceeds %d pixels", width, height, maxFullPagePixels)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	y := 0
+	for i, tile := range tiles {
+		img, err := png.Decode(bytes.NewReader(tile))
+		if err != nil {
+			return nil, fmt.Errorf("decode tile %d: %w", i, err)
+		}
+
+		bounds := img.Bounds()
+		draw.Draw(canvas, image.Rect(0, y, bounds.Dx(), y+bounds.Dy()), img, bounds.Min, draw.Src)
+		y += bounds.Dy()
+	}
+
+	var buffer bytes.Buffer
+
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buffer, canvas, &jpeg.Options{Quality: quality})
+	} else {
+		err = png.Encode(&buffer, canvas)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
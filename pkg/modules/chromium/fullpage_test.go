@@ -0,0 +1,114 @@
+package chromium
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestClipFullPage(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		width        float64
+		height       float64
+		scale        float64
+		expectWidth  float64
+		expectHeight float64
+	}{
+		{"within limits", 1280, 5000, 1, 1280, 5000},
+		{"too high", 800, 100000, 1, 800, maxFullPageHeight},
+		{"too wide", 50000, 100, 1, maxFullPageWidth, 100},
+		{"too many pixels", 4000, 60000, 1, 4000, 16777},
+		{"too many pixels with scale", 4000, 60000, 2, 4000, 4194},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			width, height := clipFullPage(tc.width, tc.height, tc.scale)
+
+			if width != tc.expectWidth || height != tc.expectHeight {
+				t.Errorf("expected %.0fx%.0f but got %.0fx%.0f", tc.expectWidth, tc.expectHeight, width, height)
+			}
+
+			if width*height*tc.scale*tc.scale > maxFullPagePixels {
+				t.Errorf("expected at most %d pixels but got %.0f", maxFullPagePixels, width*height*tc.scale*tc.scale)
+			}
+		})
+	}
+}
+
+func TestStitchTiles(t *testing.T) {
+	tile := func(height int, c color.Color) []byte {
+		img := image.NewRGBA(image.Rect(0, 0, 4, height))
+		for y := range height {
+			for x := range 4 {
+				img.Set(x, y, c)
+			}
+		}
+
+		var buffer bytes.Buffer
+		err := png.Encode(&buffer, img)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return buffer.Bytes()
+	}
+
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	tiles := [][]byte{tile(3, red), tile(2, blue)}
+
+	t.Run("png", func(t *testing.T) {
+		b, err := stitchTiles(tiles, "png", 100)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		img, err := png.Decode(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if img.Bounds() != image.Rect(0, 0, 4, 5) {
+			t.Fatalf("expected bounds of 4x5 but got %v", img.Bounds())
+		}
+
+		for y, expect := range []color.Color{red, red, red, blue, blue} {
+			if !colorEqual(img.At(0, y), expect) {
+				t.Errorf("expected %v at row %d but got %v", expect, y, img.At(0, y))
+			}
+		}
+	})
+
+	t.Run("jpeg", func(t *testing.T) {
+		b, err := stitchTiles(tiles, "jpeg", 90)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(b))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if config.Width != 4 || config.Height != 5 {
+			t.Errorf("expected 4x5 but got %dx%d", config.Width, config.Height)
+		}
+	})
+
+	t.Run("webp", func(t *testing.T) {
+		_, err := stitchTiles(tiles, "webp", 100)
+		if err == nil {
+			t.Error("expected error but got none")
+		}
+	})
+}
+
+func colorEqual(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
@@ -26,6 +26,8 @@ type browserMock struct {
 	gotenberg.ProcessMock
 	pdfMock        func(ctx context.Context, logger *slog.Logger, url, outputPath string, options PdfOptions, aggregate *networkAggregate) error
 	screenshotMock func(ctx context.Context, logger *slog.Logger, url, outputPath string, options ScreenshotOptions, aggregate *networkAggregate) error
+	loginMock      func(ctx context.Context, logger *slog.Logger, url string, steps []LoginStep, options Options, aggregate *networkAggregate) (*sessionState, error)
+	reloadMock     func(policy *outboundPolicy)
 }
 
 func (b *browserMock) pdf(ctx context.Context, logger *slog.Logger, url, outputPath string, options PdfOptions, aggregate *networkAggregate) error {
@@ -36,6 +38,14 @@ func (b *browserMock) screenshot(ctx context.Context, logger *slog.Logger, url,
 	return b.screenshotMock(ctx, logger, url, outputPath, options, aggregate)
 }
 
+func (b *browserMock) login(ctx context.Context, logger *slog.Logger, url string, steps []LoginStep, options Options, aggregate *networkAggregate) (*sessionState, error) {
+	return b.loginMock(ctx, logger, url, steps, options, aggregate)
+}
+
+func (b *browserMock) reload(policy *outboundPolicy) {
+	b.reloadMock(policy)
+}
+
 // Interface guards.
 var (
 	_ Api     = (*ApiMock)(nil)
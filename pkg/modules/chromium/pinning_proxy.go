@@ -11,9 +11,9 @@ import (
 	"net/netip"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/dlclark/regexp2"
 	"golang.org/x/net/http/httpproxy"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
@@ -31,11 +31,12 @@ import (
 // through CONNECT with Chromium performing its own TLS handshake using
 // the original hostname, preserving SNI and certificate validation.
 type pinningProxy struct {
-	allowList []*regexp2.Regexp
-	denyList  []*regexp2.Regexp
+	// policy is the outbound policy applied on every request. It may change
+	// on reload.
+	policy atomic.Pointer[outboundPolicy]
 
 	// decide resolves and validates a URL. Tests may override it.
-	decide func(ctx context.Context, rawURL string, allowList, denyList []*regexp2.Regexp, deadline time.Time) (gotenberg.OutboundDecision, error)
+	decide func(ctx context.Context, rawURL string, policy *outboundPolicy, deadline time.Time) (gotenberg.OutboundDecision, error)
 
 	// dialPinned dials the pinned IPs for a decision. Tests may override
 	// it to connect to a stub upstream regardless of decision.
@@ -63,19 +64,14 @@ type pinningProxy struct {
 }
 
 // newPinningProxy returns a pinning proxy configured with the given
-// allow/deny lists and IP-class policy. The policy bools are applied via
+// allow/deny lists and IP-class policy. The policy is applied via
 // [gotenberg.DecideOutbound] on every request the proxy sees, so
 // Chromium inherits whatever posture the operator selected. The
 // returned proxy is not yet listening; call Start.
-func newPinningProxy(allowList, denyList []*regexp2.Regexp, denyPrivateIPs, denyPublicIPs, enableEnvironmentProxy bool) *pinningProxy {
+func newPinningProxy(policy *outboundPolicy, enableEnvironmentProxy bool) *pinningProxy {
 	p := &pinningProxy{
-		allowList: allowList,
-		denyList:  denyList,
-		decide: func(ctx context.Context, rawURL string, allow, deny []*regexp2.Regexp, deadline time.Time) (gotenberg.OutboundDecision, error) {
-			return gotenberg.DecideOutbound(ctx, rawURL, allow, deny, deadline,
-				gotenberg.WithDenyPrivateIPs(denyPrivateIPs),
-				gotenberg.WithDenyPublicIPs(denyPublicIPs),
-			)
+		decide: func(ctx context.Context, rawURL string, policy *outboundPolicy, deadline time.Time) (gotenberg.OutboundDecision, error) {
+			return gotenberg.DecideOutbound(ctx, rawURL, policy.allowList, policy.denyList, deadline, policy.ipOptions()...)
 		},
 		dialPinned: gotenberg.DialPinned,
 		dialBypass: func(ctx context.Context, network, addr string) (net.Conn, error) {
@@ -84,6 +80,8 @@ func newPinningProxy(allowList, denyList []*regexp2.Regexp, denyPrivateIPs, deny
 		},
 	}
 
+	p.policy.Store(policy)
+
 	if enableEnvironmentProxy {
 		// Honor the standard proxy environment variables, credentials
 		// included. httpproxy reads the environment now and applies NO_PROXY.
@@ -195,7 +193,7 @@ func (p *pinningProxy) handleConnect(w http.ResponseWriter, req *http.Request) {
 	// The validation URL uses https:// so that http-like scheme checks
 	// apply in [gotenberg.DecideOutbound]. The scheme does not influence
 	// the CONNECT handling beyond filtering.
-	decision, err := p.decide(req.Context(), "https://"+req.Host, p.allowList, p.denyList, deadline)
+	decision, err := p.decide(req.Context(), "https://"+req.Host, p.policy.Load(), deadline)
 	if err != nil {
 		if isClientCancellation(req.Context(), err) {
 			p.logger.DebugContext(req.Context(), fmt.Sprintf("CONNECT abandoned by client for '%s': %s", req.Host, err))
@@ -298,7 +296,7 @@ func (p *pinningProxy) handleForward(w http.ResponseWriter, req *http.Request) {
 		deadline = time.Now().Add(30 * time.Second)
 	}
 
-	decision, err := p.decide(req.Context(), req.URL.String(), p.allowList, p.denyList, deadline)
+	decision, err := p.decide(req.Context(), req.URL.String(), p.policy.Load(), deadline)
 	if err != nil {
 		if isClientCancellation(req.Context(), err) {
 			p.logger.DebugContext(req.Context(), fmt.Sprintf("forward abandoned by client for '%s': %s", req.URL, err))
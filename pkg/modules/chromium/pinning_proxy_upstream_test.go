@@ -14,8 +14,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dlclark/regexp2"
-
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 )
 
@@ -38,10 +36,10 @@ func TestPinningProxy_Forward_ThroughUpstreamProxy(t *testing.T) {
 	upstreamURL := mustParseURL(t, upstream.URL)
 	upstreamURL.User = url.UserPassword("bob", "pw")
 
-	p := newPinningProxy(nil, nil, false, false, true)
+	p := newPinningProxy(new(outboundPolicy), true)
 	// Force every destination through our stub upstream proxy.
 	p.upstreamProxy = func(_ *url.URL) (*url.URL, error) { return upstreamURL, nil }
-	p.decide = func(_ context.Context, _ string, _, _ []*regexp2.Regexp, _ time.Time) (gotenberg.OutboundDecision, error) {
+	p.decide = func(_ context.Context, _ string, _ *outboundPolicy, _ time.Time) (gotenberg.OutboundDecision, error) {
 		return gotenberg.OutboundDecision{Pinned: []netip.Addr{netip.MustParseAddr("127.0.0.1")}}, nil
 	}
 	p.dialPinned = func(_ context.Context, _ string, _ []netip.Addr, _ string) (net.Conn, error) {
@@ -78,6 +78,12 @@ func FormDataChromiumOptions(ctx *api.Context) (*api.FormData, Options) {
 		emulatedMediaType               string
 		emulatedMediaFeatures           []EmulatedMediaFeature
 		omitBackground                  bool
+		diagnostics                     bool
+		failureScreenshot               bool
+		preloadScripts                  []string
+		extraStylesheets                []string
+		hideSelectors                   []string
+		session                         string
 	)
 
 	form := ctx.FormData().
@@ -134,39 +140,8 @@ func FormDataChromiumOptions(ctx *api.Context) (*api.FormData, Options) {
 				return nil
 			}
 
-			// sameSite attribute from cookies must accept case-insensitive
-			// values.
-			// See https://github.com/gotenberg/gotenberg/issues/1331.
-			normalized, err := sameSiteRegexp.ReplaceFunc(value, func(m regexp2.Match) string {
-				groups := m.Groups()
-				provided := groups[2].String()
-				var canon string
-				switch strings.ToLower(provided) {
-				case "lax":
-					canon = "Lax"
-				case "strict":
-					canon = "Strict"
-				case "none":
-					canon = "None"
-				default:
-					canon = provided
-				}
-				return groups[1].String() + canon + groups[3].String()
-			}, -1, -1)
-			if err != nil {
-				return fmt.Errorf("normalize sameSite from cookies: %w", err)
-			}
-
-			err = json.Unmarshal([]byte(normalized), &cookies)
-			if err != nil {
-				return fmt.Errorf("unmarshal cookies: %w", err)
-			}
-
-			for i, cookie := range cookies {
-				if strings.TrimSpace(cookie.Name) == "" || strings.TrimSpace(cookie.Value) == "" || strings.TrimSpace(cookie.Domain) == "" {
-					err = errors.Join(err, fmt.Errorf("cookie %d must have its name, value and domain set", i))
-				}
-			}
+			var err error
+			cookies, err = parseCookies(value)
 
 			return err
 		}).
@@ -239,6 +214,10 @@ func FormDataChromiumOptions(ctx *api.Context) (*api.FormData, Options) {
 
 			return err
 		}).
+		PreloadScripts(&preloadScripts).
+		ExtraStylesheets(&extraStylesheets).
+		Strings("hideSelectors", &hideSelectors).
+		String("session", &session, "").
 		Custom("emulatedMediaType", func(value string) error {
 			if value == "" {
 				emulatedMediaType = defaultOptions.EmulatedMediaType
@@ -274,7 +253,9 @@ func FormDataChromiumOptions(ctx *api.Context) (*api.FormData, Options) {
 
 			return err
 		}).
-		Bool("omitBackground", &omitBackground, defaultOptions.OmitBackground)
+		Bool("omitBackground", &omitBackground, defaultOptions.OmitBackground).
+		Bool("diagnostics", &diagnostics, defaultOptions.Diagnostics).
+		Bool("failureScreenshot", &failureScreenshot, defaultOptions.FailureScreenshot)
 
 	options := Options{
 		SkipNetworkIdleEvent:            skipNetworkIdleEvent,
@@ -294,11 +275,59 @@ func FormDataChromiumOptions(ctx *api.Context) (*api.FormData, Options) {
 		EmulatedMediaType:               emulatedMediaType,
 		EmulatedMediaFeatures:           emulatedMediaFeatures,
 		OmitBackground:                  omitBackground,
+		PreloadScripts:                  preloadScripts,
+		ExtraStylesheets:                extraStylesheets,
+		HideSelectors:                   hideSelectors,
+		Diagnostics:                     diagnostics,
+		FailureScreenshot:               failureScreenshot,
+		Session:                         session,
+		SessionOwner:                    ctx.Identity(),
 	}
 
 	return form, options
 }
 
+// parseCookies unmarshals JSON-encoded cookies, e.g., the "cookies" form
+// field.
+func parseCookies(value string) ([]Cookie, error) {
+	// sameSite attribute from cookies must accept case-insensitive
+	// values.
+	// See https://github.com/gotenberg/gotenberg/issues/1331.
+	normalized, err := sameSiteRegexp.ReplaceFunc(value, func(m regexp2.Match) string {
+		groups := m.Groups()
+		provided := groups[2].String()
+		var canon string
+		switch strings.ToLower(provided) {
+		case "lax":
+			canon = "Lax"
+		case "strict":
+			canon = "Strict"
+		case "none":
+			canon = "None"
+		default:
+			canon = provided
+		}
+		return groups[1].String() + canon + groups[3].String()
+	}, -1, -1)
+	if err != nil {
+		return nil, fmt.Errorf("normalize sameSite from cookies: %w", err)
+	}
+
+	var cookies []Cookie
+	err = json.Unmarshal([]byte(normalized), &cookies)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal cookies: %w", err)
+	}
+
+	for i, cookie := range cookies {
+		if strings.TrimSpace(cookie.Name) == "" || strings.TrimSpace(cookie.Value) == "" || strings.TrimSpace(cookie.Domain) == "" {
+			err = errors.Join(err, fmt.Errorf("cookie %d must have its name, value and domain set", i))
+		}
+	}
+
+	return cookies, err
+}
+
 // FormDataChromiumPdfOptions creates [PdfOptions] from the form data. Fallback to
 // the default value if the considered key is not present.
 func FormDataChromiumPdfOptions(ctx *api.Context) (*api.FormData, PdfOptions) {
@@ -371,6 +400,8 @@ func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, Screens
 		quality           int
 		optimizeForSpeed  bool
 		deviceScaleFactor float64
+		fullPage          bool
+		viewports         []Viewport
 	)
 
 	form.
@@ -415,7 +446,39 @@ func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, Screens
 			return nil
 		}).
 		Bool("optimizeForSpeed", &optimizeForSpeed, defaultScreenshotOptions.OptimizeForSpeed).
-		Float64("deviceScaleFactor", &deviceScaleFactor, defaultScreenshotOptions.DeviceScaleFactor)
+		Float64("deviceScaleFactor", &deviceScaleFactor, defaultScreenshotOptions.DeviceScaleFactor).
+		Custom("fullPage", func(value string) error {
+			if value == "" {
+				fullPage = defaultScreenshotOptions.FullPage
+				return nil
+			}
+
+			boolValue, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+
+			// The tiles of a full page are stitched together with an
+			// encoder the webp format does not have.
+			if boolValue && format == "webp" {
+				return errors.New("webp format not supported, expected either 'png' or 'jpeg' format")
+			}
+
+			fullPage = boolValue
+
+			return nil
+		}).
+		Custom("viewports", func(value string) error {
+			if value == "" {
+				viewports = defaultScreenshotOptions.Viewports
+				return nil
+			}
+
+			var err error
+			viewports, err = parseViewports(value)
+
+			return err
+		})
 
 	screenshotOptions := ScreenshotOptions{
 		Options:           options,
@@ -427,14 +490,16 @@ func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, Screens
 		Quality:           quality,
 		OptimizeForSpeed:  optimizeForSpeed,
 		DeviceScaleFactor: deviceScaleFactor,
+		FullPage:          fullPage,
+		Viewports:         viewports,
 	}
 
 	return form, screenshotOptions
 }
 
 // rejectFileScheme returns an HTTP 400 [api] error when rawURL uses the
-// file:// scheme. /forms/chromium/convert/url and
-// /forms/chromium/screenshot/url accept user-supplied URLs and are
+// file:// scheme. /forms/chromium/convert/url, /forms/chromium/convert/urls
+// and /forms/chromium/screenshot/url accept user-supplied URLs and are
 // intended for navigating to remote HTTP(S) resources; allowing file://
 // lets a caller reach Chromium's working directory through the default
 // deny-list's /tmp/ allowance, which exists only to serve main-page
@@ -463,9 +528,10 @@ func rejectFileScheme(rawURL string) error {
 // convertUrlRoute returns an [api.Route] which can convert a URL to PDF.
 func convertUrlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 	return api.Route{
-		Method:      http.MethodPost,
-		Path:        "/forms/chromium/convert/url",
-		IsMultipart: true,
+		Method:       http.MethodPost,
+		Path:         "/forms/chromium/convert/url",
+		IsMultipart:  true,
+		DisableCache: true,
 		Handler: func(c echo.Context) error {
 			ctx := c.Get("context").(*api.Context)
 			form, options := FormDataChromiumPdfOptions(ctx)
@@ -521,13 +587,85 @@ func convertUrlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 	}
 }
 
+// convertUrlsRoute returns an [api.Route] which can convert many URLs to a
+// single PDF, merged in the given order. At most maxConcurrency URLs are
+// converted at a time.
+func convertUrlsRoute(chromium Api, engine gotenberg.PdfEngine, maxConcurrency int64) api.Route {
+	return api.Route{
+		Method:       http.MethodPost,
+		Path:         "/forms/chromium/convert/urls",
+		IsMultipart:  true,
+		DisableCache: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+			form, options := FormDataChromiumPdfOptions(ctx)
+			pdfFormats := pdfengines.FormDataPdfFormats(form)
+			metadata := pdfengines.FormDataPdfMetadata(form, false)
+			encrypt := pdfengines.FormDataPdfEncrypt(form)
+			embedPaths := pdfengines.FormDataPdfEmbeds(form)
+			watermarks, wErr := pdfengines.FormDataPdfWatermarks(form)
+			if wErr != nil {
+				return fmt.Errorf("form data watermarks: %w", wErr)
+			}
+			stamps, sErr := pdfengines.FormDataPdfStamps(form)
+			if sErr != nil {
+				return fmt.Errorf("form data stamps: %w", sErr)
+			}
+			var watermarkFiles, stampFiles []string
+			form.Watermarks(&watermarkFiles).Stamps(&stampFiles)
+			rotateAngle, rotatePages := pdfengines.FormDataPdfRotate(form, false)
+			optimizeImages, imageQuality := pdfengines.FormDataPdfOptimize(form)
+			embedsMetadata := pdfengines.FormDataPdfEmbedsMetadata(form)
+			facturX, facturxXmlPath := pdfengines.FormDataPdfFacturX(form)
+
+			var urls []batchUrl
+			var titleBookmarks bool
+			err := form.
+				MandatoryCustom("urls", func(value string) error {
+					var err error
+					urls, err = parseBatchUrls(value, options)
+					return err
+				}).
+				Bool("titleBookmarks", &titleBookmarks, true).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			for _, u := range urls {
+				err = rejectFileScheme(u.Url)
+				if err != nil {
+					return fmt.Errorf("reject URL scheme: %w", err)
+				}
+			}
+
+			err = pdfengines.BindWatermarkFiles(watermarks, watermarkFiles)
+			if err != nil {
+				return fmt.Errorf("bind watermark files: %w", err)
+			}
+			err = pdfengines.BindStampFiles(stamps, stampFiles)
+			if err != nil {
+				return fmt.Errorf("bind stamp files: %w", err)
+			}
+
+			err = convertUrls(ctx, chromium, engine, maxConcurrency, urls, titleBookmarks, pdfFormats, metadata, encrypt, embedPaths, embedsMetadata, facturX, facturxXmlPath, watermarks, stamps, rotateAngle, rotatePages, optimizeImages, imageQuality)
+			if err != nil {
+				return fmt.Errorf("convert URLs to PDF: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
 // screenshotUrlRoute returns an [api.Route] which can take a screenshot from a
 // URL.
-func screenshotUrlRoute(chromium Api) api.Route {
+func screenshotUrlRoute(chromium Api, maxConcurrency int64) api.Route {
 	return api.Route{
-		Method:      http.MethodPost,
-		Path:        "/forms/chromium/screenshot/url",
-		IsMultipart: true,
+		Method:       http.MethodPost,
+		Path:         "/forms/chromium/screenshot/url",
+		IsMultipart:  true,
+		DisableCache: true,
 		Handler: func(c echo.Context) error {
 			ctx := c.Get("context").(*api.Context)
 			form, options := FormDataChromiumScreenshotOptions(ctx)
@@ -545,7 +683,7 @@ func screenshotUrlRoute(chromium Api) api.Route {
 				return fmt.Errorf("reject URL scheme: %w", err)
 			}
 
-			err = screenshotUrl(ctx, chromium, url, options)
+			err = screenshotUrl(ctx, chromium, url, options, maxConcurrency)
 			if err != nil {
 				return fmt.Errorf("URL screenshot: %w", err)
 			}
@@ -616,7 +754,7 @@ func convertHtmlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 
 // screenshotHtmlRoute returns an [api.Route] which can take a screenshot from
 // an HTML file.
-func screenshotHtmlRoute(chromium Api) api.Route {
+func screenshotHtmlRoute(chromium Api, maxConcurrency int64) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/chromium/screenshot/html",
@@ -635,7 +773,7 @@ func screenshotHtmlRoute(chromium Api) api.Route {
 
 			url := fmt.Sprintf("file://%s", inputPath)
 			options.AllowedFilePrefixes = []string{ctx.DirPath()}
-			err = screenshotUrl(ctx, chromium, url, options)
+			err = screenshotUrl(ctx, chromium, url, options, maxConcurrency)
 			if err != nil {
 				return fmt.Errorf("HTML screenshot: %w", err)
 			}
@@ -715,7 +853,7 @@ func convertMarkdownRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 
 // screenshotMarkdownRoute returns an [api.Route] which can take a screenshot
 // from Markdown files.
-func screenshotMarkdownRoute(chromium Api) api.Route {
+func screenshotMarkdownRoute(chromium Api, maxConcurrency int64) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/chromium/screenshot/markdown",
@@ -743,7 +881,7 @@ func screenshotMarkdownRoute(chromium Api) api.Route {
 			}
 
 			options.AllowedFilePrefixes = []string{ctx.DirPath()}
-			err = screenshotUrl(ctx, chromium, url, options)
+			err = screenshotUrl(ctx, chromium, url, options, maxConcurrency)
 			if err != nil {
 				return fmt.Errorf("markdown screenshot: %w", err)
 			}
@@ -828,69 +966,103 @@ func markdownToHtml(ctx *api.Context, inputPath string, markdownPaths []string)
 	return fmt.Sprintf("file://%s", inputPath), nil
 }
 
-func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url string, options PdfOptions, mode gotenberg.SplitMode, pdfFormats gotenberg.PdfFormats, metadata map[string]any, encrypt gotenberg.EncryptOptions, embedPaths []string, embedsMetadata map[string]map[string]string, facturX gotenberg.FacturX, facturxXmlPath string, watermarks, stamps []gotenberg.Stamp, rotateAngle int, rotatePages string, optimizeImages bool, imageQuality int) error {
+// printPdf prints the given URL to PDF with Chromium, and returns the output
+// path.
+func printPdf(ctx *api.Context, chromium Api, url string, options PdfOptions) (string, error) {
 	outputPath := ctx.GeneratePath(".pdf")
 	// See https://github.com/gotenberg/gotenberg/issues/1130.
 	filename := ctx.OutputFilename(outputPath)
 	outputPath = ctx.GeneratePathFromFilename(filename)
 
 	err := chromium.Pdf(ctx, ctx.Log(), url, outputPath, options)
-	err = handleChromiumError(err, options.Options)
+	err = handlePdfError(err, options)
 	if err != nil {
-		if errors.Is(err, ErrOmitBackgroundWithoutPrintBackground) {
-			return api.WrapError(
-				fmt.Errorf("convert to PDF: %w", err),
-				api.NewSentinelHttpError(
-					http.StatusBadRequest,
-					"omitBackground requires printBackground set to true",
-				),
-			)
-		}
+		return "", err
+	}
 
-		if errors.Is(err, ErrPrintingFailed) {
-			return api.WrapError(
-				fmt.Errorf("convert to PDF: %w", err),
-				api.NewSentinelHttpError(
-					http.StatusBadRequest,
-					"Chromium failed to print the PDF; this usually happens when the page is too large",
-				),
-			)
-		}
+	return outputPath, nil
+}
 
-		if errors.Is(err, ErrInvalidPrinterSettings) {
-			return api.WrapError(
-				fmt.Errorf("convert to PDF: %w", err),
-				api.NewSentinelHttpError(
-					http.StatusBadRequest,
-					"Chromium does not handle the provided settings; please check for aberrant form values",
-				),
-			)
-		}
+// handlePdfError maps the errors of [Api.Pdf] to HTTP errors.
+func handlePdfError(err error, options PdfOptions) error {
+	err = handleChromiumError(err, options.Options)
+	if err == nil {
+		return nil
+	}
 
-		if errors.Is(err, ErrPageRangesExceedsPageCount) {
-			return api.WrapError(
-				fmt.Errorf("convert to PDF: %w", err),
-				api.NewSentinelHttpError(
-					http.StatusBadRequest,
-					fmt.Sprintf("The page ranges '%s' (nativePageRanges) exceeds the page count", options.PageRanges),
-				),
-			)
-		}
+	if errors.Is(err, ErrOmitBackgroundWithoutPrintBackground) {
+		return api.WrapError(
+			fmt.Errorf("convert to PDF: %w", err),
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				"omitBackground requires printBackground set to true",
+			),
+		)
+	}
 
-		if errors.Is(err, ErrPageRangesSyntaxError) {
-			return api.WrapError(
-				fmt.Errorf("convert to PDF: %w", err),
-				api.NewSentinelHttpError(
-					http.StatusBadRequest,
-					fmt.Sprintf("Chromium does not handle the page ranges '%s' (nativePageRanges) syntax", options.PageRanges),
-				),
-			)
-		}
+	if errors.Is(err, ErrPrintingFailed) {
+		return api.WrapError(
+			fmt.Errorf("convert to PDF: %w", err),
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				"Chromium failed to print the PDF; this usually happens when the page is too large",
+			),
+		)
+	}
+
+	if errors.Is(err, ErrInvalidPrinterSettings) {
+		return api.WrapError(
+			fmt.Errorf("convert to PDF: %w", err),
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				"Chromium does not handle the provided settings; please check for aberrant form values",
+			),
+		)
+	}
 
-		return fmt.Errorf("convert to PDF: %w", err)
+	if errors.Is(err, ErrPageRangesExceedsPageCount) {
+		return api.WrapError(
+			fmt.Errorf("convert to PDF: %w", err),
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("The page ranges '%s' (nativePageRanges) exceeds the page count", options.PageRanges),
+			),
+		)
 	}
 
-	err = pdfengines.ValidatePdfFormatsCompat(pdfFormats, encrypt.UserPassword, embedPaths)
+	if errors.Is(err, ErrPageRangesSyntaxError) {
+		return api.WrapError(
+			fmt.Errorf("convert to PDF: %w", err),
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("Chromium does not handle the page ranges '%s' (nativePageRanges) syntax", options.PageRanges),
+			),
+		)
+	}
+
+	return fmt.Errorf("convert to PDF: %w", err)
+}
+
+func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url string, options PdfOptions, mode gotenberg.SplitMode, pdfFormats gotenberg.PdfFormats, metadata map[string]any, encrypt gotenberg.EncryptOptions, embedPaths []string, embedsMetadata map[string]map[string]string, facturX gotenberg.FacturX, facturxXmlPath string, watermarks, stamps []gotenberg.Stamp, rotateAngle int, rotatePages string, optimizeImages bool, imageQuality int) error {
+	withDiagnosticsPaths(ctx, &options.Options, "")
+
+	outputPath, err := printPdf(ctx, chromium, url, options)
+	if err != nil {
+		return err
+	}
+
+	err = processPdf(ctx, engine, outputPath, nil, mode, pdfFormats, metadata, encrypt, embedPaths, embedsMetadata, facturX, facturxXmlPath, watermarks, stamps, rotateAngle, rotatePages, optimizeImages, imageQuality)
+	if err != nil {
+		return err
+	}
+
+	return addDiagnosticsOutputPaths(ctx, options.Options)
+}
+
+// processPdf applies the PDF engines features to a PDF printed by Chromium,
+// and adds the result to the output paths.
+func processPdf(ctx *api.Context, engine gotenberg.PdfEngine, outputPath string, bookmarks []gotenberg.Bookmark, mode gotenberg.SplitMode, pdfFormats gotenberg.PdfFormats, metadata map[string]any, encrypt gotenberg.EncryptOptions, embedPaths []string, embedsMetadata map[string]map[string]string, facturX gotenberg.FacturX, facturxXmlPath string, watermarks, stamps []gotenberg.Stamp, rotateAngle int, rotatePages string, optimizeImages bool, imageQuality int) error {
+	err := pdfengines.ValidatePdfFormatsCompat(pdfFormats, encrypt.UserPassword, embedPaths)
 	if err != nil {
 		return err
 	}
@@ -937,8 +1109,15 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 		return fmt.Errorf("convert PDF(s): %w", err)
 	}
 
-	// Metadata, embeds are written after Convert, as LibreOffice
+	// Bookmarks, metadata, embeds are written after Convert, as LibreOffice
 	// strips them during PDF/A conversion.
+	if len(bookmarks) > 0 {
+		err = pdfengines.WriteBookmarksStub(ctx, engine, bookmarks, convertOutputPaths)
+		if err != nil {
+			return fmt.Errorf("write bookmarks: %w", err)
+		}
+	}
+
 	err = pdfengines.WriteMetadataStub(ctx, engine, metadata, convertOutputPaths)
 	if err != nil {
 		return fmt.Errorf("write metadata: %w", err)
@@ -987,22 +1166,17 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 	return nil
 }
 
-func screenshotUrl(ctx *api.Context, chromium Api, url string, options ScreenshotOptions) error {
+func screenshotUrl(ctx *api.Context, chromium Api, url string, options ScreenshotOptions, maxConcurrency int64) error {
+	if len(options.Viewports) > 0 {
+		return screenshotViewports(ctx, chromium, url, options, maxConcurrency)
+	}
+
 	ext := fmt.Sprintf(".%s", options.Format)
 	outputPath := ctx.GeneratePath(ext)
+	withDiagnosticsPaths(ctx, &options.Options, "")
 
 	err := chromium.Screenshot(ctx, ctx.Log(), url, outputPath, options)
-	if errors.Is(err, ErrScreenshotSelectorNotFound) {
-		err = api.WrapError(
-			err,
-			api.NewSentinelHttpError(
-				http.StatusBadRequest,
-				fmt.Sprintf("The selector '%s' (selector) matched no element with a visible box", options.Selector),
-			),
-		)
-	} else {
-		err = handleChromiumError(err, options.Options)
-	}
+	err = handleScreenshotError(err, options)
 	if err != nil {
 		return fmt.Errorf("screenshot: %w", err)
 	}
@@ -1012,7 +1186,21 @@ func screenshotUrl(ctx *api.Context, chromium Api, url string, options Screensho
 		return fmt.Errorf("add output path: %w", err)
 	}
 
-	return nil
+	return addDiagnosticsOutputPaths(ctx, options.Options)
+}
+
+func handleScreenshotError(err error, options ScreenshotOptions) error {
+	if errors.Is(err, ErrScreenshotSelectorNotFound) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("The selector '%s' (selector) matched no element with a visible box", options.Selector),
+			),
+		)
+	}
+
+	return handleChromiumError(err, options.Options)
 }
 
 func handleChromiumError(err error, options Options) error {
@@ -1103,5 +1291,154 @@ func handleChromiumError(err error, options Options) error {
 		)
 	}
 
+	if errors.Is(err, ErrSessionNotFound) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("The session '%s' (session) does not exist or has expired", options.Session),
+			),
+		)
+	}
+
+	if errors.Is(err, ErrLoginStepFailed) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("The login script failed at %s", strings.ReplaceAll(err.Error(), fmt.Sprintf(": %s", ErrLoginStepFailed.Error()), "")),
+			),
+		)
+	}
+
+	if errors.Is(err, ErrTooManySessions) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusTooManyRequests,
+				"Too many sessions, delete some or wait for them to expire",
+			),
+		)
+	}
+
 	return err
 }
+
+// sessionResponse is the JSON representation of a session.
+type sessionResponse struct {
+	Name      string    `json:"name"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// createSessionRoute returns an [api.Route] which runs a login script from a
+// URL, and keeps the resulting cookies and local storage under the given
+// name. The "session" form field of the other routes restores them.
+func createSessionRoute(mod *Chromium) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/chromium/sessions",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+			form, options := FormDataChromiumOptions(ctx)
+
+			var (
+				name  string
+				url   string
+				steps []LoginStep
+				ttl   time.Duration
+			)
+
+			err := form.
+				MandatoryCustom("name", func(value string) error {
+					if !sessionNameRegexp.MatchString(value) {
+						return fmt.Errorf("wrong value, expected to match '%s'", sessionNameRegexp)
+					}
+
+					name = value
+
+					return nil
+				}).
+				MandatoryString("url", &url).
+				Custom("steps", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					var err error
+					steps, err = parseLoginSteps(value)
+
+					return err
+				}).
+				Custom("ttl", func(value string) error {
+					if value == "" {
+						ttl = mod.sessionTtl
+						return nil
+					}
+
+					var err error
+					ttl, err = time.ParseDuration(value)
+					if err != nil {
+						return err
+					}
+
+					if ttl <= 0 || ttl > mod.sessionTtl {
+						return fmt.Errorf("wrong value, expected a duration strictly positive and at most %s", mod.sessionTtl)
+					}
+
+					return nil
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			err = rejectFileScheme(url)
+			if err != nil {
+				return fmt.Errorf("reject URL scheme: %w", err)
+			}
+
+			expiresAt, err := mod.createSession(ctx, ctx.Log(), name, url, steps, ttl, options)
+			err = handleChromiumError(err, options)
+			if err != nil {
+				return fmt.Errorf("create session: %w", err)
+			}
+
+			err = c.JSON(http.StatusCreated, sessionResponse{
+				Name:      name,
+				ExpiresAt: expiresAt.UTC(),
+			})
+			if err != nil {
+				if strings.Contains(err.Error(), "request method or response status code does not allow body") {
+					// High probability that the user is using the webhook
+					// feature. It does not make sense for this route.
+					return api.ErrNoOutputFile
+				}
+				return fmt.Errorf("return JSON response: %w", err)
+			}
+
+			return api.ErrNoOutputFile
+		},
+	}
+}
+
+// deleteSessionRoute returns an [api.Route] which deletes a session of the
+// caller.
+func deleteSessionRoute(mod *Chromium) api.Route {
+	return api.Route{
+		Method: http.MethodDelete,
+		Path:   "/chromium/sessions/:name",
+		Handler: func(c echo.Context) error {
+			name := c.Param("name")
+
+			if !mod.sessions.delete(api.CallerIdentity(c), name) {
+				return api.WrapError(
+					fmt.Errorf("session '%s' not found", name),
+					api.NewSentinelHttpError(http.StatusNotFound, fmt.Sprintf("Session '%s' does not exist or has expired", name)),
+				)
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		},
+	}
+}
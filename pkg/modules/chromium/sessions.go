@@ -0,0 +1,193 @@
+package chromium
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionNameRegexp matches the valid session names.
+var sessionNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// Login step actions.
+const (
+	loginStepNavigate        = "navigate"
+	loginStepFill            = "fill"
+	loginStepClick           = "click"
+	loginStepSubmit          = "submit"
+	loginStepWaitForSelector = "waitForSelector"
+	loginStepWait            = "wait"
+)
+
+// LoginStep is a step of the login script run when creating a session, e.g.:
+//
+//	[
+//	  {"action": "fill", "selector": "#username", "value": "alice"},
+//	  {"action": "fill", "selector": "#password", "value": "secret"},
+//	  {"action": "submit", "selector": "form"},
+//	  {"action": "waitForSelector", "selector": "#dashboard"}
+//	]
+type LoginStep struct {
+	// Action is either "navigate", "fill", "click", "submit",
+	// "waitForSelector" or "wait".
+	// Required.
+	Action string `json:"action"`
+
+	// Url is the URL to navigate to.
+	// Required for "navigate".
+	Url string `json:"url,omitempty"`
+
+	// Selector is the query of the element to act on, or to wait for.
+	// Required for "fill", "click", "submit" and "waitForSelector".
+	Selector string `json:"selector,omitempty"`
+
+	// Value is the text to type into the element.
+	// Required for "fill".
+	Value string `json:"value,omitempty"`
+
+	// Delay is the duration to wait, e.g., "500ms".
+	// Required for "wait".
+	Delay string `json:"delay,omitempty"`
+}
+
+// parseLoginSteps unmarshals and validates the JSON-encoded login steps.
+func parseLoginSteps(value string) ([]LoginStep, error) {
+	var steps []LoginStep
+	err := json.Unmarshal([]byte(value), &steps)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal steps: %w", err)
+	}
+
+	for i, step := range steps {
+		var stepErr error
+
+		switch step.Action {
+		case loginStepNavigate:
+			if strings.TrimSpace(step.Url) == "" {
+				stepErr = errors.New("url must be set")
+			}
+		case loginStepFill, loginStepClick, loginStepSubmit, loginStepWaitForSelector:
+			if strings.TrimSpace(step.Selector) == "" {
+				stepErr = errors.New("selector must be set")
+			}
+		case loginStepWait:
+			_, stepErr = time.ParseDuration(step.Delay)
+		default:
+			stepErr = fmt.Errorf("unknown action '%s'", step.Action)
+		}
+
+		if stepErr != nil {
+			err = errors.Join(err, fmt.Errorf("step %d: %w", i, stepErr))
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+// sessionState is the browser state captured after a login script: the
+// cookies of the visited sites and the local storage of the last page.
+type sessionState struct {
+	cookies      []Cookie
+	localStorage map[string]map[string]string
+	expiresAt    time.Time
+}
+
+// sessionKey identifies a session. Sessions of a caller are out of reach of
+// the others. Anonymous callers share the empty owner, and therefore their
+// sessions.
+type sessionKey struct {
+	owner string
+	name  string
+}
+
+// sessionStore keeps the sessions in memory, so that their credentials never
+// land on disk.
+type sessionStore struct {
+	mu          sync.Mutex
+	sessions    map[sessionKey]*sessionState
+	maxSessions int
+	now         func() time.Time
+}
+
+func newSessionStore(maxSessions int) *sessionStore {
+	return &sessionStore{
+		sessions:    make(map[sessionKey]*sessionState),
+		maxSessions: maxSessions,
+		now:         time.Now,
+	}
+}
+
+// put adds or replaces a session. It fails if the store is full, once the
+// expired sessions are gone.
+func (store *sessionStore) put(owner, name string, state *sessionState) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	key := sessionKey{owner: owner, name: name}
+
+	now := store.now()
+	for k, s := range store.sessions {
+		if !now.Before(s.expiresAt) {
+			delete(store.sessions, k)
+		}
+	}
+
+	_, exists := store.sessions[key]
+	if !exists && store.maxSessions > 0 && len(store.sessions) >= store.maxSessions {
+		return ErrTooManySessions
+	}
+
+	store.sessions[key] = state
+
+	return nil
+}
+
+// get returns a session. Expired sessions are reported as missing. A nil
+// store has no session.
+func (store *sessionStore) get(owner, name string) (*sessionState, bool) {
+	if store == nil {
+		return nil, false
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	key := sessionKey{owner: owner, name: name}
+
+	state, ok := store.sessions[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !store.now().Before(state.expiresAt) {
+		delete(store.sessions, key)
+		return nil, false
+	}
+
+	return state, true
+}
+
+// delete removes a session, and tells if it existed.
+func (store *sessionStore) delete(owner, name string) bool {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	key := sessionKey{owner: owner, name: name}
+
+	state, ok := store.sessions[key]
+	if !ok {
+		return false
+	}
+
+	delete(store.sessions, key)
+
+	return store.now().Before(state.expiresAt)
+}
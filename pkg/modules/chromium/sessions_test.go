@@ -0,0 +1,110 @@
+package chromium
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseLoginSteps(t *testing.T) {
+	steps, err := parseLoginSteps(`[
+		{"action":"fill","selector":"#username","value":"alice"},
+		{"action":"submit","selector":"form"},
+		{"action":"wait","delay":"500ms"}
+	]`)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(steps) != 3 {
+		t.Fatalf("expected 3 steps but got %d", len(steps))
+	}
+
+	if steps[0].Value != "alice" {
+		t.Errorf("expected the first step to fill 'alice' but got '%s'", steps[0].Value)
+	}
+
+	for _, tc := range []struct {
+		scenario string
+		value    string
+	}{
+		{scenario: "invalid JSON", value: "foo"},
+		{scenario: "unknown action", value: `[{"action":"hover","selector":"a"}]`},
+		{scenario: "navigate without URL", value: `[{"action":"navigate"}]`},
+		{scenario: "fill without selector", value: `[{"action":"fill","value":"alice"}]`},
+		{scenario: "invalid delay", value: `[{"action":"wait","delay":"foo"}]`},
+	} {
+		t.Run(fmt.Sprintf("rejects %s", tc.scenario), func(t *testing.T) {
+			_, err := parseLoginSteps(tc.value)
+			if err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	now := time.Now()
+
+	store := newSessionStore(2)
+	store.now = func() time.Time { return now }
+
+	err := store.put("alice", "foo", &sessionState{expiresAt: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, ok := store.get("alice", "foo")
+	if !ok {
+		t.Error("expected the session of alice")
+	}
+
+	_, ok = store.get("bob", "foo")
+	if ok {
+		t.Error("expected bob not to reach the session of alice")
+	}
+
+	err = store.put("bob", "foo", &sessionState{expiresAt: now.Add(time.Second)})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = store.put("carol", "foo", &sessionState{expiresAt: now.Add(time.Minute)})
+	if !errors.Is(err, ErrTooManySessions) {
+		t.Errorf("expected %v but got: %v", ErrTooManySessions, err)
+	}
+
+	// Replacing a session does not count against the limit.
+	err = store.put("alice", "foo", &sessionState{expiresAt: now.Add(time.Minute)})
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	now = now.Add(2 * time.Second)
+
+	_, ok = store.get("bob", "foo")
+	if ok {
+		t.Error("expected the session of bob to be expired")
+	}
+
+	// Expired sessions free their slot.
+	err = store.put("carol", "foo", &sessionState{expiresAt: now.Add(time.Minute)})
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+	if !store.delete("alice", "foo") {
+		t.Error("expected the session of alice to be deleted")
+	}
+
+	if store.delete("alice", "foo") {
+		t.Error("expected no session of alice to delete")
+	}
+
+	var nilStore *sessionStore
+	_, ok = nilStore.get("alice", "foo")
+	if ok {
+		t.Error("expected a nil store to have no session")
+	}
+}
@@ -0,0 +1,106 @@
+package chromium
+
+import (
+	"fmt"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pipeline"
+)
+
+// convertUrlStep returns a [pipeline.Step] which converts a URL to PDF.
+func convertUrlStep(chromium Api) pipeline.Step {
+	return pipeline.Step{
+		Name:    "chromium/convert/url",
+		Convert: true,
+		Run: func(ctx *api.Context, _ []string) ([]string, error) {
+			form, options := FormDataChromiumPdfOptions(ctx)
+
+			var url string
+			err := form.
+				MandatoryString("url", &url).
+				Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			err = rejectFileScheme(url)
+			if err != nil {
+				return nil, fmt.Errorf("reject URL scheme: %w", err)
+			}
+
+			outputPath, err := printPdf(ctx, chromium, url, options)
+			if err != nil {
+				return nil, fmt.Errorf("convert URL to PDF: %w", err)
+			}
+
+			return []string{outputPath}, nil
+		},
+	}
+}
+
+// convertHtmlStep returns a [pipeline.Step] which converts the request's
+// "index.html" file to PDF.
+func convertHtmlStep(chromium Api) pipeline.Step {
+	return pipeline.Step{
+		Name:    "chromium/convert/html",
+		Convert: true,
+		Run: func(ctx *api.Context, _ []string) ([]string, error) {
+			form, options := FormDataChromiumPdfOptions(ctx)
+
+			var inputPath string
+			err := form.
+				MandatoryPath("index.html", &inputPath).
+				Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			url := fmt.Sprintf("file://%s", inputPath)
+			options.AllowedFilePrefixes = []string{ctx.DirPath()}
+			outputPath, err := printPdf(ctx, chromium, url, options)
+			if err != nil {
+				return nil, fmt.Errorf("convert HTML to PDF: %w", err)
+			}
+
+			return []string{outputPath}, nil
+		},
+	}
+}
+
+// convertMarkdownStep returns a [pipeline.Step] which converts the request's
+// "index.html" and Markdown files to PDF.
+func convertMarkdownStep(chromium Api) pipeline.Step {
+	return pipeline.Step{
+		Name:    "chromium/convert/markdown",
+		Convert: true,
+		Run: func(ctx *api.Context, _ []string) ([]string, error) {
+			form, options := FormDataChromiumPdfOptions(ctx)
+
+			var (
+				inputPath     string
+				markdownPaths []string
+			)
+
+			err := form.
+				MandatoryPath("index.html", &inputPath).
+				MandatoryPaths([]string{".md"}, &markdownPaths).
+				Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			url, err := markdownToHtml(ctx, inputPath, markdownPaths)
+			if err != nil {
+				return nil, fmt.Errorf("transform markdown file(s) to HTML: %w", err)
+			}
+
+			options.AllowedFilePrefixes = []string{ctx.DirPath()}
+			outputPath, err := printPdf(ctx, chromium, url, options)
+			if err != nil {
+				return nil, fmt.Errorf("convert markdown to PDF: %w", err)
+			}
+
+			return []string{outputPath}, nil
+		},
+	}
+}
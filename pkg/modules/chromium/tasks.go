@@ -3,11 +3,13 @@ package chromium
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"time"
 
@@ -198,6 +200,10 @@ func printToPdfAttrs(options PdfOptions) []attribute.KeyValue {
 
 func captureScreenshotActionFunc(logger *slog.Logger, outputPath string, options ScreenshotOptions) chromedp.ActionFunc {
 	return func(ctx context.Context) error {
+		if options.FullPage && options.Selector == "" {
+			return captureFullPageActionFunc(logger, outputPath, options).Do(ctx)
+		}
+
 		captureScreenshot := page.CaptureScreenshot().
 			WithCaptureBeyondViewport(true).
 			WithFromSurface(true).
@@ -254,6 +260,27 @@ func captureScreenshotActionFunc(logger *slog.Logger, outputPath string, options
 	}
 }
 
+// captureFailureScreenshotActionFunc captures the whole page, as is, into a PNG
+// screenshot.
+func captureFailureScreenshotActionFunc(logger *slog.Logger, screenshot *[]byte) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		logger.DebugContext(ctx, "capture failure screenshot")
+
+		buffer, err := page.CaptureScreenshot().
+			WithCaptureBeyondViewport(true).
+			WithFromSurface(true).
+			WithFormat(page.CaptureScreenshotFormatPng).
+			Do(ctx)
+		if err != nil {
+			return fmt.Errorf("capture screenshot: %w", err)
+		}
+
+		*screenshot = buffer
+
+		return nil
+	}
+}
+
 // elementClip resolves the first element matching selector to a page-space clip
 // rectangle for Page.captureScreenshot.
 //
@@ -464,6 +491,173 @@ func setCookiesActionFunc(logger *slog.Logger, cookies []Cookie) chromedp.Action
 	}
 }
 
+// restoreSessionActionFunc restores the cookies and the local storage of a
+// session before the page loads. As the local storage of an origin is only
+// reachable from one of its documents, a script fills it as soon as a
+// document of this origin is created.
+func restoreSessionActionFunc(logger *slog.Logger, session *sessionState) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if session == nil {
+			logger.DebugContext(ctx, "no session to restore")
+			return nil
+		}
+
+		logger.DebugContext(ctx, fmt.Sprintf("restore session with %d cookie(s) and the local storage of %d origin(s)", len(session.cookies), len(session.localStorage)))
+
+		err := setCookiesActionFunc(logger, session.cookies).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("restore session cookies: %w", err)
+		}
+
+		if len(session.localStorage) == 0 {
+			return nil
+		}
+
+		items, err := json.Marshal(session.localStorage)
+		if err != nil {
+			return fmt.Errorf("marshal session local storage: %w", err)
+		}
+
+		script := fmt.Sprintf(`(function(items) {
+  var entries = items[window.location.origin];
+  if (!entries) {
+    return;
+  }
+  for (var key in entries) {
+    window.localStorage.setItem(key, entries[key]);
+  }
+})(%s);`, items)
+
+		_, err = page.AddScriptToEvaluateOnNewDocument(script).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("restore session local storage: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// loginStepsActionFunc runs the steps of a login script. Values are never
+// logged, as they are usually credentials.
+func loginStepsActionFunc(logger *slog.Logger, steps []LoginStep) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		for i, step := range steps {
+			logger.DebugContext(ctx, fmt.Sprintf("login step %d: %s", i, step.Action))
+
+			var err error
+			switch step.Action {
+			case loginStepNavigate:
+				err = navigateActionFunc(logger, step.Url, true, true).Do(ctx)
+			case loginStepFill:
+				err = chromedp.SendKeys(step.Selector, step.Value, chromedp.ByQuery).Do(ctx)
+			case loginStepClick:
+				err = chromedp.Click(step.Selector, chromedp.ByQuery).Do(ctx)
+			case loginStepSubmit:
+				err = chromedp.Submit(step.Selector, chromedp.ByQuery).Do(ctx)
+			case loginStepWaitForSelector:
+				err = chromedp.WaitVisible(step.Selector, chromedp.ByQuery, chromedp.RetryInterval(time.Duration(100)*time.Millisecond)).Do(ctx)
+			case loginStepWait:
+				delay, _ := time.ParseDuration(step.Delay)
+				select {
+				case <-ctx.Done():
+					err = ctx.Err()
+				case <-time.After(delay):
+				}
+			default:
+				// Should not happen, as the steps have been validated.
+				err = fmt.Errorf("unknown action '%s'", step.Action)
+			}
+
+			if err != nil {
+				return fmt.Errorf("step %d (%s): %v: %w", i, step.Action, err, ErrLoginStepFailed)
+			}
+		}
+
+		return nil
+	}
+}
+
+// captureSessionActionFunc captures the cookies of the login URL and of the
+// current page, and the local storage of the current page.
+func captureSessionActionFunc(logger *slog.Logger, loginUrl string, state *sessionState) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		var currentUrl string
+		err := chromedp.Evaluate(`window.location.href`, &currentUrl).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("get current URL: %w", err)
+		}
+
+		cookies, err := network.GetCookies().WithURLs([]string{loginUrl, currentUrl}).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("get cookies: %w", err)
+		}
+
+		for _, cookie := range cookies {
+			state.cookies = append(state.cookies, Cookie{
+				Name:     cookie.Name,
+				Value:    cookie.Value,
+				Domain:   cookie.Domain,
+				Path:     cookie.Path,
+				Secure:   cookie.Secure,
+				HttpOnly: cookie.HTTPOnly,
+				SameSite: cookie.SameSite,
+			})
+		}
+
+		state.localStorage = make(map[string]map[string]string)
+
+		origin, ok := httpOrigin(currentUrl)
+		if ok {
+			var raw string
+			err = chromedp.Evaluate(`JSON.stringify(Object.assign({}, window.localStorage))`, &raw).Do(ctx)
+			if err != nil {
+				return fmt.Errorf("get local storage: %w", err)
+			}
+
+			var items map[string]string
+			err = json.Unmarshal([]byte(raw), &items)
+			if err != nil {
+				return fmt.Errorf("unmarshal local storage: %w", err)
+			}
+
+			if len(items) > 0 {
+				state.localStorage[origin] = items
+			}
+		}
+
+		logger.DebugContext(ctx, fmt.Sprintf("captured %d cookie(s) and the local storage of %d origin(s)", len(state.cookies), len(state.localStorage)))
+
+		return nil
+	}
+}
+
+// preloadScriptsActionFunc installs scripts which run in every document of
+// the tab, before its own scripts.
+func preloadScriptsActionFunc(logger *slog.Logger, disableJavaScript bool, scripts []string) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if len(scripts) == 0 {
+			logger.DebugContext(ctx, "no preload scripts")
+			return nil
+		}
+
+		if disableJavaScript {
+			logger.DebugContext(ctx, "JavaScript disabled, skipping preload scripts")
+			return nil
+		}
+
+		logger.DebugContext(ctx, fmt.Sprintf("install %d preload script(s)", len(scripts)))
+
+		for i, script := range scripts {
+			_, err := page.AddScriptToEvaluateOnNewDocument(script).Do(ctx)
+			if err != nil {
+				return fmt.Errorf("install preload script %d: %w", i, err)
+			}
+		}
+
+		return nil
+	}
+}
+
 func userAgentOverride(logger *slog.Logger, userAgent string) chromedp.ActionFunc {
 	return func(ctx context.Context) error {
 		if len(userAgent) == 0 {
@@ -619,6 +813,49 @@ func forceExactColorsActionFunc(logger *slog.Logger, printBackground bool) chrom
 	}
 }
 
+// injectStylesheetsActionFunc adds the extra stylesheets to the loaded page,
+// then a stylesheet which hides the elements matching the given selectors.
+func injectStylesheetsActionFunc(logger *slog.Logger, stylesheets, hideSelectors []string) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if len(stylesheets) == 0 && len(hideSelectors) == 0 {
+			logger.DebugContext(ctx, "no extra stylesheets")
+			return nil
+		}
+
+		logger.DebugContext(ctx, fmt.Sprintf("inject %d extra stylesheet(s) and hide %d selector(s)", len(stylesheets), len(hideSelectors)))
+
+		css := slices.Clone(stylesheets)
+		for _, selector := range hideSelectors {
+			// One rule per selector, so that an invalid one does not void
+			// the others.
+			css = append(css, fmt.Sprintf("%s { display: none !important; }", selector))
+		}
+
+		b, err := json.Marshal(css)
+		if err != nil {
+			return fmt.Errorf("marshal extra stylesheets: %w", err)
+		}
+
+		script := fmt.Sprintf(`
+(() => {
+	const stylesheets = %s;
+	for (const css of stylesheets) {
+		const style = document.createElement('style');
+		style.appendChild(document.createTextNode(css));
+		(document.head || document.documentElement).appendChild(style);
+	}
+})();
+`, b)
+
+		err = chromedp.Evaluate(script, nil).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("inject extra stylesheets: %w", err)
+		}
+
+		return nil
+	}
+}
+
 func emulateMediaTypeActionFunc(logger *slog.Logger, mediaType string, mediaFeatures []EmulatedMediaFeature) chromedp.ActionFunc {
 	return func(ctx context.Context) error {
 		if mediaType == "" && len(mediaFeatures) == 0 {
@@ -0,0 +1,132 @@
+package chromium
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// maxViewports bounds the number of viewports of the "viewports" form field.
+// Every viewport takes a Chromium tab until the screenshots end.
+const maxViewports = 10
+
+// parseViewports unmarshals and validates the JSON-encoded viewports, e.g.:
+//
+//	[
+//	  {"width": 375, "height": 812, "deviceScaleFactor": 3},
+//	  {"width": 1440, "height": 900}
+//	]
+func parseViewports(value string) ([]Viewport, error) {
+	var viewports []Viewport
+	err := json.Unmarshal([]byte(value), &viewports)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal viewports: %w", err)
+	}
+
+	if len(viewports) > maxViewports {
+		return nil, fmt.Errorf("too many viewports, got %d, expected at most %d", len(viewports), maxViewports)
+	}
+
+	seen := make(map[string]struct{}, len(viewports))
+	for i := range viewports {
+		viewport := &viewports[i]
+		if viewport.DeviceScaleFactor == 0 {
+			viewport.DeviceScaleFactor = 1
+		}
+
+		var viewportErr error
+		switch {
+		case viewport.Width <= 0:
+			viewportErr = errors.New("width must be positive")
+		case viewport.Height <= 0:
+			viewportErr = errors.New("height must be positive")
+		case viewport.DeviceScaleFactor < 0:
+			viewportErr = errors.New("deviceScaleFactor must be positive")
+		}
+
+		if viewportErr == nil {
+			name := viewport.name()
+			if _, ok := seen[name]; ok {
+				viewportErr = fmt.Errorf("duplicate of viewport %s", name)
+			}
+			seen[name] = struct{}{}
+		}
+
+		if viewportErr != nil {
+			err = errors.Join(err, fmt.Errorf("viewport %d: %w", i, viewportErr))
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return viewports, nil
+}
+
+// name returns the name of the viewport, e.g., "375x812@3x".
+func (viewport Viewport) name() string {
+	return fmt.Sprintf("%dx%d@%sx", viewport.Width, viewport.Height, strconv.FormatFloat(viewport.DeviceScaleFactor, 'f', -1, 64))
+}
+
+// screenshotViewports takes a screenshot of the URL per viewport
+// concurrently, at most maxConcurrency at a time, each one named after its
+// viewport. The first failure cancels the other screenshots.
+func screenshotViewports(ctx *api.Context, chromium Api, url string, options ScreenshotOptions, maxConcurrency int64) error {
+	// Paths are generated upfront, as the context is not safe for concurrent
+	// use.
+	outputPaths := make([]string, len(options.Viewports))
+	viewportsOptions := make([]ScreenshotOptions, len(options.Viewports))
+	for i, viewport := range options.Viewports {
+		outputPaths[i] = ctx.GeneratePathFromFilename(fmt.Sprintf("%s.%s", viewport.name(), options.Format))
+
+		viewportOptions := options
+		viewportOptions.Width = viewport.Width
+		viewportOptions.Height = viewport.Height
+		viewportOptions.DeviceScaleFactor = viewport.DeviceScaleFactor
+		viewportOptions.Viewports = nil
+		withDiagnosticsPaths(ctx, &viewportOptions.Options, fmt.Sprintf("_%s", viewport.name()))
+
+		viewportsOptions[i] = viewportOptions
+	}
+
+	// Beyond Chromium's concurrency, the screenshots would only wait in its
+	// queue, and take the place of other requests.
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(int(maxConcurrency))
+	for i, viewportOptions := range viewportsOptions {
+		eg.Go(func() error {
+			err := chromium.Screenshot(egCtx, ctx.Log(), url, outputPaths[i], viewportOptions)
+			err = handleScreenshotError(err, viewportOptions)
+			if err != nil {
+				return fmt.Errorf("viewport %s: %w", options.Viewports[i].name(), err)
+			}
+
+			return nil
+		})
+	}
+
+	err := eg.Wait()
+	if err != nil {
+		return fmt.Errorf("screenshot: %w", err)
+	}
+
+	err = ctx.AddOutputPaths(outputPaths...)
+	if err != nil {
+		return fmt.Errorf("add output paths: %w", err)
+	}
+
+	for _, viewportOptions := range viewportsOptions {
+		err = addDiagnosticsOutputPaths(ctx, viewportOptions.Options)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
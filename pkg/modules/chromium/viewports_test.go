@@ -0,0 +1,98 @@
+package chromium
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestParseViewports(t *testing.T) {
+	t.Run("defaults the device scale factor", func(t *testing.T) {
+		viewports, err := parseViewports(`[{"width":375,"height":812,"deviceScaleFactor":3},{"width":1440,"height":900}]`)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		expect := []Viewport{
+			{Width: 375, Height: 812, DeviceScaleFactor: 3},
+			{Width: 1440, Height: 900, DeviceScaleFactor: 1},
+		}
+		if !reflect.DeepEqual(viewports, expect) {
+			t.Errorf("expected %+v but got %+v", expect, viewports)
+		}
+
+		for i, name := range []string{"375x812@3x", "1440x900@1x"} {
+			if viewports[i].name() != name {
+				t.Errorf("expected viewport %d to be named '%s' but got '%s'", i, name, viewports[i].name())
+			}
+		}
+	})
+
+	for _, tc := range []struct {
+		scenario string
+		value    string
+	}{
+		{scenario: "invalid JSON", value: "foo"},
+		{scenario: "too many viewports", value: "[" + strings.Repeat(`{"width":375,"height":812},`, maxViewports) + `{"width":375,"height":812}]`},
+		{scenario: "no width", value: `[{"height":812}]`},
+		{scenario: "negative height", value: `[{"width":375,"height":-1}]`},
+		{scenario: "negative deviceScaleFactor", value: `[{"width":375,"height":812,"deviceScaleFactor":-1}]`},
+		{scenario: "duplicate viewports", value: `[{"width":375,"height":812},{"width":375,"height":812,"deviceScaleFactor":1}]`},
+	} {
+		t.Run(fmt.Sprintf("rejects %s", tc.scenario), func(t *testing.T) {
+			_, err := parseViewports(tc.value)
+			if err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestScreenshotViewports(t *testing.T) {
+	ctx := &api.ContextMock{Context: &api.Context{Context: context.Background()}}
+	ctx.SetDirPath(t.TempDir())
+	ctx.SetOriginalFilenames(make(map[string]string))
+	ctx.SetLogger(slog.New(slog.DiscardHandler))
+
+	var mu sync.Mutex
+	var active, maxActive int
+	chromium := &ApiMock{ScreenshotMock: func(ctx context.Context, logger *slog.Logger, url, outputPath string, options ScreenshotOptions) error {
+		mu.Lock()
+		active++
+		maxActive = max(maxActive, active)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		return nil
+	}}
+
+	options := DefaultScreenshotOptions()
+	for i := range maxViewports {
+		options.Viewports = append(options.Viewports, Viewport{Width: 100 + i, Height: 100, DeviceScaleFactor: 1})
+	}
+
+	err := screenshotViewports(ctx.Context, chromium, "https://foo.example", options, 2)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if outputPaths := ctx.OutputPaths(); len(outputPaths) != maxViewports {
+		t.Errorf("expected %d output paths, but got %d", maxViewports, len(outputPaths))
+	}
+
+	if maxActive > 2 {
+		t.Errorf("expected at most 2 concurrent screenshots, but got %d", maxActive)
+	}
+}
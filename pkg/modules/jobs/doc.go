@@ -0,0 +1,4 @@
+// Package jobs adds an asynchronous mode to multipart/form-data routes. The
+// client receives a job ID right away, polls the job status, and downloads
+// the result once the job has succeeded.
+package jobs
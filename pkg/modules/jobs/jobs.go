@@ -0,0 +1,192 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Jobs))
+}
+
+// Jobs is a module that provides an asynchronous mode for multipart/form-data
+// routes, plus the routes for polling the jobs and downloading their results.
+type Jobs struct {
+	retention time.Duration
+	disable   bool
+
+	store      *store
+	fs         *gotenberg.FileSystem
+	resultsDir string
+	asyncCount atomic.Int64
+	done       chan struct{}
+	logger     *slog.Logger
+}
+
+// Descriptor returns a [Jobs]'s module descriptor.
+func (mod *Jobs) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "jobs",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("jobs", flag.ExitOnError)
+			fs.Duration("jobs-result-retention", time.Duration(1)*time.Hour, "Set how long the status and result of a completed job remain available")
+			fs.Bool("jobs-disable", false, "Disable the asynchronous jobs feature")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Jobs) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Jobs) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.retention = flags.MustDuration("jobs-result-retention")
+	mod.disable = flags.MustBool("jobs-disable")
+
+	mod.store = newStore(mod.retention)
+	mod.fs = gotenberg.NewFileSystem(new(gotenberg.OsMkdirAll))
+	mod.resultsDir = mod.fs.NewDirPath()
+	mod.done = make(chan struct{})
+	mod.asyncCount.Store(0)
+	mod.logger = gotenberg.Logger(mod)
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Jobs) Validate() error {
+	if mod.disable {
+		return nil
+	}
+
+	if mod.retention <= 0 {
+		return errors.New("result retention must be more than 0")
+	}
+
+	return nil
+}
+
+// Start creates the results directory and starts removing expired jobs.
+func (mod *Jobs) Start() error {
+	if mod.disable {
+		return nil
+	}
+
+	err := os.MkdirAll(mod.resultsDir, 0o755)
+	if err != nil {
+		return fmt.Errorf("create results directory: %w", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(min(mod.retention, time.Minute))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-mod.done:
+				return
+			case now := <-ticker.C:
+				mod.purge(now)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Jobs) StartupMessage() string {
+	if mod.disable {
+		return "asynchronous jobs disabled"
+	}
+
+	return fmt.Sprintf("asynchronous jobs enabled, results kept for %s", mod.retention)
+}
+
+// Stop removes the results once the other modules have gracefully stopped.
+func (mod *Jobs) Stop(ctx context.Context) error {
+	if mod.disable {
+		return nil
+	}
+
+	// Block until the context is done so that the API may wait for the
+	// running jobs before we remove their results.
+	mod.logger.DebugContext(ctx, "wait for the end of grace duration")
+
+	<-ctx.Done()
+	close(mod.done)
+
+	err := os.RemoveAll(mod.fs.WorkingDirPath())
+	if err != nil {
+		return fmt.Errorf("remove results: %w", err)
+	}
+
+	return nil
+}
+
+// Middlewares returns the middleware.
+func (mod *Jobs) Middlewares() ([]api.Middleware, error) {
+	if mod.disable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		jobsMiddleware(mod),
+	}, nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *Jobs) Routes() ([]api.Route, error) {
+	if mod.disable {
+		return nil, nil
+	}
+
+	return []api.Route{
+		jobStatusRoute(mod),
+		jobResultRoute(mod),
+	}, nil
+}
+
+// AsyncCount returns the number of running jobs.
+func (mod *Jobs) AsyncCount() int64 {
+	return mod.asyncCount.Load()
+}
+
+// purge removes the expired jobs and their results.
+func (mod *Jobs) purge(now time.Time) {
+	for _, j := range mod.store.purge(now) {
+		if j.resultPath == "" {
+			continue
+		}
+
+		err := os.Remove(j.resultPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			mod.logger.ErrorContext(context.Background(), fmt.Sprintf("remove result of job '%s': %s", j.id, err))
+			continue
+		}
+
+		mod.logger.DebugContext(context.Background(), fmt.Sprintf("job '%s' expired", j.id))
+	}
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module        = (*Jobs)(nil)
+	_ gotenberg.Provisioner   = (*Jobs)(nil)
+	_ gotenberg.Validator     = (*Jobs)(nil)
+	_ gotenberg.App           = (*Jobs)(nil)
+	_ api.MiddlewareProvider  = (*Jobs)(nil)
+	_ api.Router              = (*Jobs)(nil)
+	_ api.AsynchronousCounter = (*Jobs)(nil)
+)
@@ -0,0 +1,164 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func jobsMiddleware(mod *Jobs) api.Middleware {
+	return api.Middleware{
+		Stack: api.MultipartStack,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					asyncHeader := c.Request().Header.Get("Gotenberg-Async")
+					if asyncHeader == "" {
+						// Synchronous request, call the next middleware in the chain.
+						return next(c)
+					}
+
+					async, err := strconv.ParseBool(asyncHeader)
+					if err != nil {
+						return api.WrapError(
+							fmt.Errorf("parse 'Gotenberg-Async' header: %w", err),
+							api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'Gotenberg-Async' header value: expected 'true' or 'false', but got '%s'", asyncHeader)),
+						)
+					}
+
+					if !async {
+						return next(c)
+					}
+
+					if c.Request().Header.Get("Gotenberg-Webhook-Url") != "" {
+						return api.WrapError(
+							errors.New("both async and webhook modes requested"),
+							api.NewSentinelHttpError(http.StatusBadRequest, "The 'Gotenberg-Async' and 'Gotenberg-Webhook-Url' headers are mutually exclusive"),
+						)
+					}
+
+					ctx := c.Get("context").(*api.Context)
+					cancel := c.Get("cancel").(context.CancelFunc)
+
+					if ctx.HasUploadTo() {
+						return api.WrapError(
+							errors.New("both async and upload to modes requested"),
+							api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The 'Gotenberg-Async' header and the '%s' form field are mutually exclusive", api.UploadToFormField)),
+						)
+					}
+
+					// Retrieve values from echo.Context before it gets recycled.
+					// See https://github.com/gotenberg/gotenberg/issues/1000.
+					rootPath := c.Get("rootPath").(string)
+					outputFilename := c.Get("outputFilename").(string)
+
+					j := mod.store.create()
+					ctx.Log().DebugContext(ctx, fmt.Sprintf("job '%s' created", j.id))
+
+					cancel = api.DetachAsyncContext(ctx, cancel, "jobs.Async")
+
+					// Echo recycles the echo.Context as soon as this handler
+					// returns. See the webhook middleware for the details.
+					detached := api.NewPoolSafeContext(c, "logger", "context", "correlationId", "correlationIdHeader", "startTime", "identity", "clientCertSubject", "outputFilename")
+
+					var asyncErr error
+					handleError := func(err error) {
+						asyncErr = err
+						ctx.Log().ErrorContext(ctx, err.Error())
+						status, message := api.ParseError(err)
+						mod.store.fail(j.id, status, message)
+					}
+
+					// Acknowledge the job before spawning the goroutine, so that
+					// the response is committed before anything downstream may
+					// touch it.
+					c.Response().Header().Set(echo.HeaderLocation, fmt.Sprintf("%sjobs/%s", rootPath, j.id))
+					err = c.JSON(http.StatusAccepted, newJobResponse(j, mod.retention))
+					if err != nil {
+						ctx.Log().ErrorContext(ctx, fmt.Sprintf("send job response: %s", err))
+					}
+
+					mod.asyncCount.Add(1)
+					go func() {
+						defer cancel()
+						defer func() {
+							ctx.AsyncDone(asyncErr)
+						}()
+						defer mod.asyncCount.Add(-1)
+
+						defer func() {
+							r := recover()
+							if r == nil {
+								return
+							}
+							handleError(fmt.Errorf("job goroutine panic: %v", r))
+						}()
+
+						mod.store.start(j.id)
+
+						// Call the next middleware in the chain.
+						err := next(detached)
+						if err != nil {
+							if errors.Is(err, api.ErrNoOutputFile) {
+								handleError(api.WrapError(
+									fmt.Errorf("%w - the jobs middleware cannot handle the result of this route", err),
+									api.NewSentinelHttpError(
+										http.StatusBadRequest,
+										"The 'Gotenberg-Async' header only works with multipart/form-data routes that result in output files",
+									),
+								))
+								return
+							}
+							handleError(err)
+							return
+						}
+
+						outputPath, err := ctx.BuildOutputFile()
+						if err != nil {
+							handleError(fmt.Errorf("build output file: %w", err))
+							return
+						}
+
+						// The conversion is over; report it while the output
+						// files are still in the working directory.
+						ctx.AsyncDone(nil)
+
+						// With the algorithms preferred by the client which
+						// submitted the job.
+						digest, err := ctx.OutputDigest(outputPath)
+						if err != nil {
+							handleError(fmt.Errorf("compute content digest: %w", err))
+							return
+						}
+
+						// The working directory goes away with the context, so
+						// the result moves to the module's directory.
+						resultPath := filepath.Join(mod.resultsDir, j.id+api.OutputExt(outputPath))
+						err = ctx.Rename(outputPath, resultPath)
+						if err != nil {
+							handleError(fmt.Errorf("keep output file: %w", err))
+							return
+						}
+
+						resultFilename := ctx.OriginalFilename(outputPath)
+						if outputFilename != "" {
+							resultFilename = fmt.Sprintf("%s%s", outputFilename, api.OutputExt(outputPath))
+						}
+
+						mod.store.succeed(j.id, resultPath, resultFilename, ctx.OutputContentType(outputPath), digest)
+						ctx.Log().DebugContext(ctx, fmt.Sprintf("job '%s' succeeded", j.id))
+					}()
+
+					return api.ErrAsyncProcess
+				}
+			}
+		}(),
+	}
+}
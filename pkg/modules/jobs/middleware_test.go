@@ -0,0 +1,236 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func newTestJobs(t *testing.T) *Jobs {
+	t.Helper()
+
+	mod := &Jobs{
+		retention:  time.Hour,
+		store:      newStore(time.Hour),
+		resultsDir: t.TempDir(),
+		logger:     slog.New(slog.DiscardHandler),
+	}
+
+	return mod
+}
+
+func newTestEchoContext(t *testing.T, headers map[string]string) (echo.Context, *httptest.ResponseRecorder, *api.ContextMock) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/forms/foo", nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	dirPath := t.TempDir()
+	reqCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(cancel)
+
+	ctx := &api.ContextMock{Context: &api.Context{Context: reqCtx}}
+	ctx.SetDirPath(dirPath)
+	ctx.SetLogger(slog.New(slog.DiscardHandler))
+	ctx.SetEchoContext(c)
+	ctx.SetPathRename(new(gotenberg.OsPathRename))
+
+	c.Set("logger", slog.New(slog.DiscardHandler))
+	c.Set("context", ctx.Context)
+	c.Set("cancel", context.CancelFunc(func() {}))
+	c.Set("rootPath", "/")
+	c.Set("outputFilename", "")
+
+	return c, rec, ctx
+}
+
+func waitForJobs(t *testing.T, mod *Jobs) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for mod.AsyncCount() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("jobs did not complete in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestJobsMiddleware_Headers(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		headers       map[string]string
+		expectNext    bool
+		expectErr     bool
+		expectHttpErr bool
+	}{
+		{
+			scenario:   "no Gotenberg-Async header",
+			headers:    nil,
+			expectNext: true,
+		},
+		{
+			scenario:   "Gotenberg-Async header set to false",
+			headers:    map[string]string{"Gotenberg-Async": "false"},
+			expectNext: true,
+		},
+		{
+			scenario:      "invalid Gotenberg-Async header",
+			headers:       map[string]string{"Gotenberg-Async": "foo"},
+			expectErr:     true,
+			expectHttpErr: true,
+		},
+		{
+			scenario: "Gotenberg-Async header with a webhook",
+			headers: map[string]string{
+				"Gotenberg-Async":       "true",
+				"Gotenberg-Webhook-Url": "http://localhost/webhook",
+			},
+			expectErr:     true,
+			expectHttpErr: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := newTestJobs(t)
+			c, _, _ := newTestEchoContext(t, tc.headers)
+
+			nextCalled := false
+			handler := jobsMiddleware(mod).Handler(func(c echo.Context) error {
+				nextCalled = true
+				return nil
+			})
+
+			err := handler(c)
+
+			if tc.expectNext != nextCalled {
+				t.Errorf("expected next to be called: %t, got %t", tc.expectNext, nextCalled)
+			}
+
+			if tc.expectErr && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectErr && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpErr := errors.As(err, &httpErr)
+			if tc.expectHttpErr && !isHttpErr {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestJobsMiddleware_Succeeded(t *testing.T) {
+	mod := newTestJobs(t)
+	c, rec, ctx := newTestEchoContext(t, map[string]string{"Gotenberg-Async": "true"})
+
+	handler := jobsMiddleware(mod).Handler(func(c echo.Context) error {
+		outputPath := filepath.Join(ctx.DirPath(), "foo.pdf")
+		err := os.WriteFile(outputPath, []byte("%PDF-1.7"), 0o600)
+		if err != nil {
+			return err
+		}
+		return ctx.AddOutputPaths(outputPath)
+	})
+
+	err := handler(c)
+	if !errors.Is(err, api.ErrAsyncProcess) {
+		t.Fatalf("expected %v, got %v", api.ErrAsyncProcess, err)
+	}
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, rec.Code)
+	}
+
+	var resp jobResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &resp)
+	if err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+
+	if resp.Status != statusQueued {
+		t.Errorf("expected status '%s', got '%s'", statusQueued, resp.Status)
+	}
+
+	if rec.Header().Get(echo.HeaderLocation) != "/jobs/"+resp.Id {
+		t.Errorf("expected location '/jobs/%s', got '%s'", resp.Id, rec.Header().Get(echo.HeaderLocation))
+	}
+
+	waitForJobs(t, mod)
+
+	j, ok := mod.store.get(resp.Id)
+	if !ok {
+		t.Fatal("expected job to exist")
+	}
+
+	if j.status != statusSucceeded {
+		t.Fatalf("expected status '%s', got '%s'", statusSucceeded, j.status)
+	}
+
+	if filepath.Dir(j.resultPath) != mod.resultsDir {
+		t.Errorf("expected result in '%s', got '%s'", mod.resultsDir, j.resultPath)
+	}
+
+	_, err = os.Stat(j.resultPath)
+	if err != nil {
+		t.Errorf("expected result file to exist: %v", err)
+	}
+
+	if !strings.HasPrefix(j.resultDigest, "sha-256=:") {
+		t.Errorf("expected a SHA-256 content digest, got '%s'", j.resultDigest)
+	}
+}
+
+func TestJobsMiddleware_Failed(t *testing.T) {
+	mod := newTestJobs(t)
+	c, _, _ := newTestEchoContext(t, map[string]string{"Gotenberg-Async": "true"})
+
+	handler := jobsMiddleware(mod).Handler(func(c echo.Context) error {
+		return api.WrapError(errors.New("foo"), api.NewSentinelHttpError(http.StatusBadRequest, "foo"))
+	})
+
+	err := handler(c)
+	if !errors.Is(err, api.ErrAsyncProcess) {
+		t.Fatalf("expected %v, got %v", api.ErrAsyncProcess, err)
+	}
+
+	waitForJobs(t, mod)
+
+	var id string
+	for key := range mod.store.jobs {
+		id = key
+	}
+
+	j, ok := mod.store.get(id)
+	if !ok {
+		t.Fatal("expected job to exist")
+	}
+
+	if j.status != statusFailed {
+		t.Fatalf("expected status '%s', got '%s'", statusFailed, j.status)
+	}
+
+	if j.errStatus != http.StatusBadRequest || j.errMessage != "foo" {
+		t.Errorf("expected error %d 'foo', got %d '%s'", http.StatusBadRequest, j.errStatus, j.errMessage)
+	}
+}
@@ -0,0 +1,131 @@
+package jobs
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// jobError is the JSON representation of a failed job's error. Status and
+// message are the same as a synchronous request would have returned.
+type jobError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// jobResponse is the JSON representation of a job.
+type jobResponse struct {
+	Id          string     `json:"id"`
+	Status      status     `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	Error       *jobError  `json:"error,omitempty"`
+}
+
+func newJobResponse(j job, retention time.Duration) jobResponse {
+	resp := jobResponse{
+		Id:        j.id,
+		Status:    j.status,
+		CreatedAt: j.createdAt.UTC(),
+	}
+
+	if !j.startedAt.IsZero() {
+		startedAt := j.startedAt.UTC()
+		resp.StartedAt = &startedAt
+	}
+
+	if j.done() {
+		completedAt := j.completedAt.UTC()
+		expiresAt := completedAt.Add(retention)
+		resp.CompletedAt = &completedAt
+		resp.ExpiresAt = &expiresAt
+	}
+
+	if j.status == statusFailed {
+		resp.Error = &jobError{
+			Status:  j.errStatus,
+			Message: j.errMessage,
+		}
+	}
+
+	return resp
+}
+
+// jobNotFoundError returns the error for unknown or expired jobs.
+func jobNotFoundError(id string) error {
+	return api.WrapError(
+		fmt.Errorf("job '%s' not found", id),
+		api.NewSentinelHttpError(http.StatusNotFound, fmt.Sprintf("Job '%s' does not exist or has expired", id)),
+	)
+}
+
+// jobStatusRoute returns an [api.Route] which returns the status of a job.
+func jobStatusRoute(mod *Jobs) api.Route {
+	return api.Route{
+		Method: http.MethodGet,
+		Path:   "/jobs/:id",
+		Handler: func(c echo.Context) error {
+			id := c.Param("id")
+
+			j, ok := mod.store.get(id)
+			if !ok {
+				return jobNotFoundError(id)
+			}
+
+			return c.JSON(http.StatusOK, newJobResponse(j, mod.retention))
+		},
+	}
+}
+
+// jobResultRoute returns an [api.Route] which streams the result of a
+// succeeded job.
+func jobResultRoute(mod *Jobs) api.Route {
+	return api.Route{
+		Method: http.MethodGet,
+		Path:   "/jobs/:id/result",
+		Handler: func(c echo.Context) error {
+			id := c.Param("id")
+
+			j, ok := mod.store.get(id)
+			if !ok {
+				return jobNotFoundError(id)
+			}
+
+			if j.status != statusSucceeded {
+				return api.WrapError(
+					fmt.Errorf("job '%s' is %s", id, j.status),
+					api.NewSentinelHttpError(http.StatusConflict, fmt.Sprintf("Job '%s' has no result: its status is '%s'", id, j.status)),
+				)
+			}
+
+			// A range of the result would not match its digest.
+			if j.resultDigest != "" && c.Request().Header.Get("Range") == "" {
+				c.Response().Header().Set(api.ContentDigestHeader, j.resultDigest)
+			}
+
+			if j.resultContentType != "" {
+				c.Response().Header().Set(echo.HeaderContentType, j.resultContentType)
+			}
+
+			var err error
+			if strings.HasPrefix(j.resultContentType, "multipart/") {
+				// The parts carry their own filenames.
+				err = c.File(j.resultPath)
+			} else {
+				err = c.Attachment(j.resultPath, j.resultFilename)
+			}
+			if err != nil {
+				return fmt.Errorf("send result: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
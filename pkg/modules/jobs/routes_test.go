@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestJobResultRoute(t *testing.T) {
+	mod := newTestJobs(t)
+
+	resultPath := filepath.Join(mod.resultsDir, "foo.pdf")
+	err := os.WriteFile(resultPath, []byte("%PDF-1.7"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	j := mod.store.create()
+	mod.store.succeed(j.id, resultPath, "foo.pdf", "", "sha-256=:foo:")
+
+	for _, tc := range []struct {
+		scenario     string
+		header       http.Header
+		expectStatus int
+		expectDigest string
+	}{
+		{scenario: "full result", expectStatus: http.StatusOK, expectDigest: "sha-256=:foo:"},
+		{scenario: "range of the result", header: http.Header{"Range": {"bytes=0-3"}}, expectStatus: http.StatusPartialContent},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			e := echo.New()
+			route := jobResultRoute(mod)
+			e.Add(route.Method, route.Path, route.Handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+j.id+"/result", nil)
+			for key, values := range tc.header {
+				req.Header[key] = values
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectStatus, rec.Code)
+			}
+
+			if digest := rec.Header().Get(api.ContentDigestHeader); digest != tc.expectDigest {
+				t.Errorf("expected content digest '%s', got '%s'", tc.expectDigest, digest)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// status is the lifecycle state of a job.
+type status string
+
+const (
+	statusQueued    status = "queued"
+	statusRunning   status = "running"
+	statusSucceeded status = "succeeded"
+	statusFailed    status = "failed"
+)
+
+// job gathers the state of an asynchronous request.
+type job struct {
+	id                string
+	status            status
+	createdAt         time.Time
+	startedAt         time.Time
+	completedAt       time.Time
+	resultPath        string
+	resultFilename    string
+	resultContentType string
+	resultDigest      string
+	errStatus         int
+	errMessage        string
+}
+
+// done tells if the job has reached a final state.
+func (j job) done() bool {
+	return j.status == statusSucceeded || j.status == statusFailed
+}
+
+// expired tells if a done job has outlived the retention at the given time.
+func (j job) expired(now time.Time, retention time.Duration) bool {
+	return j.done() && !now.Before(j.completedAt.Add(retention))
+}
+
+// store keeps the jobs in memory. Its methods return copies so that callers
+// never share state with the goroutine running the job.
+type store struct {
+	mu        sync.RWMutex
+	jobs      map[string]*job
+	retention time.Duration
+}
+
+func newStore(retention time.Duration) *store {
+	return &store{
+		jobs:      make(map[string]*job),
+		retention: retention,
+	}
+}
+
+// create registers a new queued job and returns it.
+func (s *store) create() job {
+	j := &job{
+		id:        uuid.NewString(),
+		status:    statusQueued,
+		createdAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[j.id] = j
+
+	return *j
+}
+
+// get returns the job with the given ID. Expired jobs are reported as
+// missing, even if the janitor has not removed them yet.
+func (s *store) get(id string) (job, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	j, ok := s.jobs[id]
+	if !ok || j.expired(time.Now(), s.retention) {
+		return job{}, false
+	}
+
+	return *j, true
+}
+
+// start marks the job as running.
+func (s *store) start(id string) {
+	s.update(id, func(j *job) {
+		j.status = statusRunning
+		j.startedAt = time.Now()
+	})
+}
+
+// succeed marks the job as succeeded and records where its result lives,
+// with its "Content-Digest" header value. The content type is empty unless
+// the result gathers many output files.
+func (s *store) succeed(id, resultPath, resultFilename, resultContentType, resultDigest string) {
+	s.update(id, func(j *job) {
+		j.status = statusSucceeded
+		j.completedAt = time.Now()
+		j.resultPath = resultPath
+		j.resultFilename = resultFilename
+		j.resultContentType = resultContentType
+		j.resultDigest = resultDigest
+	})
+}
+
+// fail marks the job as failed with the given HTTP status and message.
+func (s *store) fail(id string, errStatus int, errMessage string) {
+	s.update(id, func(j *job) {
+		j.status = statusFailed
+		j.completedAt = time.Now()
+		j.errStatus = errStatus
+		j.errMessage = errMessage
+	})
+}
+
+func (s *store) update(id string, fn func(j *job)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+
+	fn(j)
+}
+
+// purge removes the expired jobs at the given time and returns them, so that
+// the caller may clean up their results.
+func (s *store) purge(now time.Time) []job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged []job
+	for id, j := range s.jobs {
+		if j.expired(now, s.retention) {
+			purged = append(purged, *j)
+			delete(s.jobs, id)
+		}
+	}
+
+	return purged
+}
@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStore_Lifecycle(t *testing.T) {
+	s := newStore(time.Hour)
+
+	j := s.create()
+	if j.status != statusQueued {
+		t.Fatalf("expected status '%s', got '%s'", statusQueued, j.status)
+	}
+
+	s.start(j.id)
+	got, ok := s.get(j.id)
+	if !ok {
+		t.Fatal("expected job to exist")
+	}
+	if got.status != statusRunning {
+		t.Errorf("expected status '%s', got '%s'", statusRunning, got.status)
+	}
+	if got.startedAt.IsZero() {
+		t.Error("expected a start time")
+	}
+
+	s.succeed(j.id, "/tmp/foo.pdf", "foo.pdf", "", "")
+	got, _ = s.get(j.id)
+	if got.status != statusSucceeded {
+		t.Errorf("expected status '%s', got '%s'", statusSucceeded, got.status)
+	}
+	if got.resultPath != "/tmp/foo.pdf" || got.resultFilename != "foo.pdf" {
+		t.Errorf("expected result '/tmp/foo.pdf' named 'foo.pdf', got '%s' named '%s'", got.resultPath, got.resultFilename)
+	}
+
+	_, ok = s.get("unknown")
+	if ok {
+		t.Error("expected unknown job to not exist")
+	}
+}
+
+func TestStore_Purge(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		complete     func(s *store, id string)
+		after        time.Duration
+		expectPurged bool
+	}{
+		{
+			scenario:     "running job",
+			complete:     func(s *store, id string) { s.start(id) },
+			after:        2 * time.Hour,
+			expectPurged: false,
+		},
+		{
+			scenario:     "succeeded job within retention",
+			complete:     func(s *store, id string) { s.succeed(id, "/tmp/foo.pdf", "foo.pdf", "", "") },
+			after:        time.Minute,
+			expectPurged: false,
+		},
+		{
+			scenario:     "succeeded job after retention",
+			complete:     func(s *store, id string) { s.succeed(id, "/tmp/foo.pdf", "foo.pdf", "", "") },
+			after:        2 * time.Hour,
+			expectPurged: true,
+		},
+		{
+			scenario:     "failed job after retention",
+			complete:     func(s *store, id string) { s.fail(id, http.StatusBadRequest, "foo") },
+			after:        2 * time.Hour,
+			expectPurged: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			s := newStore(time.Hour)
+			j := s.create()
+			tc.complete(s, j.id)
+
+			purged := s.purge(time.Now().Add(tc.after))
+
+			if tc.expectPurged && len(purged) != 1 {
+				t.Fatalf("expected 1 purged job, got %d", len(purged))
+			}
+
+			if !tc.expectPurged && len(purged) != 0 {
+				t.Fatalf("expected no purged job, got %d", len(purged))
+			}
+
+			_, ok := s.jobs[j.id]
+			if tc.expectPurged == ok {
+				t.Errorf("expected job presence to be %t, got %t", !tc.expectPurged, ok)
+			}
+		})
+	}
+}
@@ -432,6 +432,23 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 		return fmt.Errorf("create libreoffice.requests.queue_size gauge: %w", err)
 	}
 
+	_, err = meter.Int64ObservableGauge(
+		"libreoffice.requests.queue_size_by_priority",
+		metric.WithDescription("Current number of LibreOffice conversion requests waiting to be treated, per priority class"),
+		metric.WithUnit("{request}"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			for _, priority := range gotenberg.Priorities() {
+				o.Observe(a.supervisor.ReqQueueSizeByPriority(priority), metric.WithAttributes(
+					attribute.String("priority", priority.String()),
+				))
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("create libreoffice.requests.queue_size_by_priority gauge: %w", err)
+	}
+
 	_, err = meter.Int64ObservableCounter(
 		"libreoffice.process.restarts.total",
 		metric.WithDescription("Current number of LibreOffice restarts"),
@@ -626,6 +643,18 @@ func (a *Api) Metrics() ([]gotenberg.Metric, error) {
 				return float64(a.supervisor.ReqQueueSize())
 			},
 		},
+		{
+			Name:        "libreoffice_requests_queue_size_by_priority",
+			Description: "Current number of LibreOffice conversion requests waiting to be treated, per priority class.",
+			Label:       "priority",
+			ReadSeries: func() map[string]float64 {
+				sizes := make(map[string]float64)
+				for _, priority := range gotenberg.Priorities() {
+					sizes[priority.String()] = float64(a.supervisor.ReqQueueSizeByPriority(priority))
+				}
+				return sizes
+			},
+		},
 		{
 			Name:        "libreoffice_restarts_count",
 			Description: "Current number of LibreOffice restarts.",
@@ -680,6 +709,16 @@ func (a *Api) Ready() error {
 	}
 }
 
+// QueuedCount returns the number of queued conversions.
+func (a *Api) QueuedCount() int64 {
+	return a.supervisor.ReqQueueSize()
+}
+
+// ActiveCount returns the number of active conversions.
+func (a *Api) ActiveCount() int64 {
+	return a.supervisor.ActiveTasksCount()
+}
+
 // LibreOffice returns a [Uno] for interacting with LibreOffice.
 func (a *Api) LibreOffice() (Uno, error) {
 	return a, nil
@@ -744,6 +783,8 @@ func (a *Api) Pdf(ctx context.Context, logger *slog.Logger, inputPath, outputPat
 		}
 
 		if err == nil {
+			gotenberg.RecordEngine(ctx, "libreoffice-api")
+
 			stat, statErr := os.Stat(outputPath)
 			if statErr == nil {
 				a.pdfOutputSizeCounter.Record(ctx, stat.Size(), attrs)
@@ -957,6 +998,7 @@ var (
 	_ gotenberg.Debuggable      = (*Api)(nil)
 	_ gotenberg.MetricsProvider = (*Api)(nil)
 	_ api.HealthChecker         = (*Api)(nil)
+	_ api.QueueCounter          = (*Api)(nil)
 	_ Uno                       = (*Api)(nil)
 	_ Provider                  = (*Api)(nil)
 )
@@ -8,6 +8,7 @@ import (
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 	libeofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pipeline"
 )
 
 func init() {
@@ -79,9 +80,21 @@ func (mod *LibreOffice) Routes() ([]api.Route, error) {
 	}, nil
 }
 
+// PipelineSteps returns the steps for the pipeline module.
+func (mod *LibreOffice) PipelineSteps() ([]pipeline.Step, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []pipeline.Step{
+		convertStep(mod.api),
+	}, nil
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module      = (*LibreOffice)(nil)
 	_ gotenberg.Provisioner = (*LibreOffice)(nil)
 	_ api.Router            = (*LibreOffice)(nil)
+	_ pipeline.StepProvider = (*LibreOffice)(nil)
 )
@@ -20,6 +20,219 @@ import (
 // filename.
 const unattributableFailureMessage = "LibreOffice failed to convert the document '%s'. This is usually a resource issue: increase the container's memory and CPU, or reduce the document's size. The request is valid and may be retried."
 
+// formDataLibreOfficeOptions creates [libreofficeapi.Options] from the form
+// data. Fallback to the default value if the considered key is not present.
+func formDataLibreOfficeOptions(form *api.FormData) libreofficeapi.Options {
+	defaultOptions := libreofficeapi.DefaultOptions()
+	options := defaultOptions
+
+	form.
+		String("password", &options.Password, defaultOptions.Password).
+		Bool("landscape", &options.Landscape, defaultOptions.Landscape).
+		String("nativePageRanges", &options.PageRanges, defaultOptions.PageRanges).
+		Bool("updateIndexes", &options.UpdateIndexes, defaultOptions.UpdateIndexes).
+		Bool("exportFormFields", &options.ExportFormFields, defaultOptions.ExportFormFields).
+		Bool("allowDuplicateFieldNames", &options.AllowDuplicateFieldNames, defaultOptions.AllowDuplicateFieldNames).
+		Bool("exportBookmarks", &options.ExportBookmarks, defaultOptions.ExportBookmarks).
+		Bool("exportBookmarksToPdfDestination", &options.ExportBookmarksToPdfDestination, defaultOptions.ExportBookmarksToPdfDestination).
+		Bool("exportPlaceholders", &options.ExportPlaceholders, defaultOptions.ExportPlaceholders).
+		Bool("exportNotes", &options.ExportNotes, defaultOptions.ExportNotes).
+		Bool("exportNotesPages", &options.ExportNotesPages, defaultOptions.ExportNotesPages).
+		Bool("exportOnlyNotesPages", &options.ExportOnlyNotesPages, defaultOptions.ExportOnlyNotesPages).
+		Bool("exportNotesInMargin", &options.ExportNotesInMargin, defaultOptions.ExportNotesInMargin).
+		Bool("convertOooTargetToPdfTarget", &options.ConvertOooTargetToPdfTarget, defaultOptions.ConvertOooTargetToPdfTarget).
+		Bool("exportLinksRelativeFsys", &options.ExportLinksRelativeFsys, defaultOptions.ExportLinksRelativeFsys).
+		Bool("exportHiddenSlides", &options.ExportHiddenSlides, defaultOptions.ExportHiddenSlides).
+		Bool("skipEmptyPages", &options.SkipEmptyPages, defaultOptions.SkipEmptyPages).
+		Bool("addOriginalDocumentAsStream", &options.AddOriginalDocumentAsStream, defaultOptions.AddOriginalDocumentAsStream).
+		Bool("singlePageSheets", &options.SinglePageSheets, defaultOptions.SinglePageSheets).
+		Custom("initialView", func(value string) error {
+			if value == "" {
+				options.InitialView = defaultOptions.InitialView
+				return nil
+			}
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains([]int{0, 1, 2}, intValue) {
+				return errors.New("value is not 0, 1 or 2")
+			}
+			options.InitialView = intValue
+			return nil
+		}).
+		Custom("initialPage", func(value string) error {
+			if value == "" {
+				options.InitialPage = defaultOptions.InitialPage
+				return nil
+			}
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			if intValue < 1 {
+				return errors.New("value is inferior to 1")
+			}
+			options.InitialPage = intValue
+			return nil
+		}).
+		Custom("magnification", func(value string) error {
+			if value == "" {
+				options.Magnification = defaultOptions.Magnification
+				return nil
+			}
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains([]int{0, 1, 2, 3, 4}, intValue) {
+				return errors.New("value is not 0, 1, 2, 3 or 4")
+			}
+			options.Magnification = intValue
+			return nil
+		}).
+		Custom("zoom", func(value string) error {
+			if value == "" {
+				options.Zoom = defaultOptions.Zoom
+				return nil
+			}
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			if intValue < 1 {
+				return errors.New("value is inferior to 1")
+			}
+			options.Zoom = intValue
+			return nil
+		}).
+		Custom("pageLayout", func(value string) error {
+			if value == "" {
+				options.PageLayout = defaultOptions.PageLayout
+				return nil
+			}
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains([]int{0, 1, 2, 3}, intValue) {
+				return errors.New("value is not 0, 1, 2 or 3")
+			}
+			options.PageLayout = intValue
+			return nil
+		}).
+		Bool("firstPageOnLeft", &options.FirstPageOnLeft, defaultOptions.FirstPageOnLeft).
+		Bool("resizeWindowToInitialPage", &options.ResizeWindowToInitialPage, defaultOptions.ResizeWindowToInitialPage).
+		Bool("centerWindow", &options.CenterWindow, defaultOptions.CenterWindow).
+		Bool("openInFullScreenMode", &options.OpenInFullScreenMode, defaultOptions.OpenInFullScreenMode).
+		Bool("displayPDFDocumentTitle", &options.DisplayPDFDocumentTitle, defaultOptions.DisplayPDFDocumentTitle).
+		Bool("hideViewerMenubar", &options.HideViewerMenubar, defaultOptions.HideViewerMenubar).
+		Bool("hideViewerToolbar", &options.HideViewerToolbar, defaultOptions.HideViewerToolbar).
+		Bool("hideViewerWindowControls", &options.HideViewerWindowControls, defaultOptions.HideViewerWindowControls).
+		Bool("useTransitionEffects", &options.UseTransitionEffects, defaultOptions.UseTransitionEffects).
+		Custom("openBookmarkLevels", func(value string) error {
+			if value == "" {
+				options.OpenBookmarkLevels = defaultOptions.OpenBookmarkLevels
+				return nil
+			}
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			if intValue != -1 && (intValue < 1 || intValue > 10) {
+				return errors.New("value is not -1 or between 1 and 10")
+			}
+			options.OpenBookmarkLevels = intValue
+			return nil
+		}).
+		Bool("losslessImageCompression", &options.LosslessImageCompression, defaultOptions.LosslessImageCompression).
+		Custom("quality", func(value string) error {
+			if value == "" {
+				options.Quality = defaultOptions.Quality
+				return nil
+			}
+
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+
+			if intValue < 1 {
+				return errors.New("value is inferior to 1")
+			}
+
+			if intValue > 100 {
+				return errors.New("value is superior to 100")
+			}
+
+			options.Quality = intValue
+			return nil
+		}).
+		Bool("reduceImageResolution", &options.ReduceImageResolution, defaultOptions.ReduceImageResolution).
+		Custom("maxImageResolution", func(value string) error {
+			if value == "" {
+				options.MaxImageResolution = defaultOptions.MaxImageResolution
+				return nil
+			}
+
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+
+			if !slices.Contains([]int{75, 150, 300, 600, 1200}, intValue) {
+				return errors.New("value is not 75, 150, 300, 600 or 1200")
+			}
+
+			options.MaxImageResolution = intValue
+			return nil
+		}).
+		String("nativeWatermarkText", &options.NativeWatermarkText, defaultOptions.NativeWatermarkText).
+		Custom("nativeWatermarkColor", func(value string) error {
+			if value == "" {
+				options.NativeWatermarkColor = defaultOptions.NativeWatermarkColor
+				return nil
+			}
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			options.NativeWatermarkColor = intValue
+			return nil
+		}).
+		Custom("nativeWatermarkFontHeight", func(value string) error {
+			if value == "" {
+				options.NativeWatermarkFontHeight = defaultOptions.NativeWatermarkFontHeight
+				return nil
+			}
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			if intValue < 0 {
+				return errors.New("value is inferior to 0")
+			}
+			options.NativeWatermarkFontHeight = intValue
+			return nil
+		}).
+		Custom("nativeWatermarkRotateAngle", func(value string) error {
+			if value == "" {
+				options.NativeWatermarkRotateAngle = defaultOptions.NativeWatermarkRotateAngle
+				return nil
+			}
+			intValue, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			options.NativeWatermarkRotateAngle = intValue
+			return nil
+		}).
+		String("nativeWatermarkFontName", &options.NativeWatermarkFontName, defaultOptions.NativeWatermarkFontName).
+		String("nativeTiledWatermarkText", &options.NativeTiledWatermarkText, defaultOptions.NativeTiledWatermarkText)
+
+	return options
+}
+
 // convertRoute returns an [api.Route] which can convert LibreOffice documents
 // to PDF.
 func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) api.Route {
@@ -29,7 +242,6 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 		IsMultipart: true,
 		Handler: func(c echo.Context) error {
 			ctx := c.Get("context").(*api.Context)
-			defaultOptions := libreofficeapi.DefaultOptions()
 
 			form := ctx.FormData()
 			splitMode := pdfengines.FormDataPdfSplitMode(form, false)
@@ -54,261 +266,17 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 
 			zeroValuedSplitMode := gotenberg.SplitMode{}
 
+			options := formDataLibreOfficeOptions(form)
+
 			var (
-				inputPaths                      []string
-				password                        string
-				landscape                       bool
-				nativePageRanges                string
-				updateIndexes                   bool
-				exportFormFields                bool
-				allowDuplicateFieldNames        bool
-				exportBookmarks                 bool
-				exportBookmarksToPdfDestination bool
-				exportPlaceholders              bool
-				exportNotes                     bool
-				exportNotesPages                bool
-				exportOnlyNotesPages            bool
-				exportNotesInMargin             bool
-				convertOooTargetToPdfTarget     bool
-				exportLinksRelativeFsys         bool
-				exportHiddenSlides              bool
-				skipEmptyPages                  bool
-				addOriginalDocumentAsStream     bool
-				singlePageSheets                bool
-				losslessImageCompression        bool
-				quality                         int
-				reduceImageResolution           bool
-				maxImageResolution              int
-				nativeWatermarkText             string
-				nativeWatermarkColor            int
-				initialView                     int
-				initialPage                     int
-				magnification                   int
-				zoom                            int
-				pageLayout                      int
-				firstPageOnLeft                 bool
-				resizeWindowToInitialPage       bool
-				centerWindow                    bool
-				openInFullScreenMode            bool
-				displayPDFDocumentTitle         bool
-				hideViewerMenubar               bool
-				hideViewerToolbar               bool
-				hideViewerWindowControls        bool
-				useTransitionEffects            bool
-				openBookmarkLevels              int
-				nativeWatermarkFontHeight       int
-				nativeWatermarkRotateAngle      int
-				nativeWatermarkFontName         string
-				nativeTiledWatermarkText        string
-				nativePdfFormats                bool
-				merge                           bool
-				flatten                         bool
+				inputPaths       []string
+				nativePdfFormats bool
+				merge            bool
+				flatten          bool
 			)
 
 			err := form.
 				MandatoryPaths(libreOffice.Extensions(), &inputPaths).
-				String("password", &password, defaultOptions.Password).
-				Bool("landscape", &landscape, defaultOptions.Landscape).
-				String("nativePageRanges", &nativePageRanges, defaultOptions.PageRanges).
-				Bool("updateIndexes", &updateIndexes, defaultOptions.UpdateIndexes).
-				Bool("exportFormFields", &exportFormFields, defaultOptions.ExportFormFields).
-				Bool("allowDuplicateFieldNames", &allowDuplicateFieldNames, defaultOptions.AllowDuplicateFieldNames).
-				Bool("exportBookmarks", &exportBookmarks, defaultOptions.ExportBookmarks).
-				Bool("exportBookmarksToPdfDestination", &exportBookmarksToPdfDestination, defaultOptions.ExportBookmarksToPdfDestination).
-				Bool("exportPlaceholders", &exportPlaceholders, defaultOptions.ExportPlaceholders).
-				Bool("exportNotes", &exportNotes, defaultOptions.ExportNotes).
-				Bool("exportNotesPages", &exportNotesPages, defaultOptions.ExportNotesPages).
-				Bool("exportOnlyNotesPages", &exportOnlyNotesPages, defaultOptions.ExportOnlyNotesPages).
-				Bool("exportNotesInMargin", &exportNotesInMargin, defaultOptions.ExportNotesInMargin).
-				Bool("convertOooTargetToPdfTarget", &convertOooTargetToPdfTarget, defaultOptions.ConvertOooTargetToPdfTarget).
-				Bool("exportLinksRelativeFsys", &exportLinksRelativeFsys, defaultOptions.ExportLinksRelativeFsys).
-				Bool("exportHiddenSlides", &exportHiddenSlides, defaultOptions.ExportHiddenSlides).
-				Bool("skipEmptyPages", &skipEmptyPages, defaultOptions.SkipEmptyPages).
-				Bool("addOriginalDocumentAsStream", &addOriginalDocumentAsStream, defaultOptions.AddOriginalDocumentAsStream).
-				Bool("singlePageSheets", &singlePageSheets, defaultOptions.SinglePageSheets).
-				Custom("initialView", func(value string) error {
-					if value == "" {
-						initialView = defaultOptions.InitialView
-						return nil
-					}
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-					if !slices.Contains([]int{0, 1, 2}, intValue) {
-						return errors.New("value is not 0, 1 or 2")
-					}
-					initialView = intValue
-					return nil
-				}).
-				Custom("initialPage", func(value string) error {
-					if value == "" {
-						initialPage = defaultOptions.InitialPage
-						return nil
-					}
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-					if intValue < 1 {
-						return errors.New("value is inferior to 1")
-					}
-					initialPage = intValue
-					return nil
-				}).
-				Custom("magnification", func(value string) error {
-					if value == "" {
-						magnification = defaultOptions.Magnification
-						return nil
-					}
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-					if !slices.Contains([]int{0, 1, 2, 3, 4}, intValue) {
-						return errors.New("value is not 0, 1, 2, 3 or 4")
-					}
-					magnification = intValue
-					return nil
-				}).
-				Custom("zoom", func(value string) error {
-					if value == "" {
-						zoom = defaultOptions.Zoom
-						return nil
-					}
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-					if intValue < 1 {
-						return errors.New("value is inferior to 1")
-					}
-					zoom = intValue
-					return nil
-				}).
-				Custom("pageLayout", func(value string) error {
-					if value == "" {
-						pageLayout = defaultOptions.PageLayout
-						return nil
-					}
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-					if !slices.Contains([]int{0, 1, 2, 3}, intValue) {
-						return errors.New("value is not 0, 1, 2 or 3")
-					}
-					pageLayout = intValue
-					return nil
-				}).
-				Bool("firstPageOnLeft", &firstPageOnLeft, defaultOptions.FirstPageOnLeft).
-				Bool("resizeWindowToInitialPage", &resizeWindowToInitialPage, defaultOptions.ResizeWindowToInitialPage).
-				Bool("centerWindow", &centerWindow, defaultOptions.CenterWindow).
-				Bool("openInFullScreenMode", &openInFullScreenMode, defaultOptions.OpenInFullScreenMode).
-				Bool("displayPDFDocumentTitle", &displayPDFDocumentTitle, defaultOptions.DisplayPDFDocumentTitle).
-				Bool("hideViewerMenubar", &hideViewerMenubar, defaultOptions.HideViewerMenubar).
-				Bool("hideViewerToolbar", &hideViewerToolbar, defaultOptions.HideViewerToolbar).
-				Bool("hideViewerWindowControls", &hideViewerWindowControls, defaultOptions.HideViewerWindowControls).
-				Bool("useTransitionEffects", &useTransitionEffects, defaultOptions.UseTransitionEffects).
-				Custom("openBookmarkLevels", func(value string) error {
-					if value == "" {
-						openBookmarkLevels = defaultOptions.OpenBookmarkLevels
-						return nil
-					}
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-					if intValue != -1 && (intValue < 1 || intValue > 10) {
-						return errors.New("value is not -1 or between 1 and 10")
-					}
-					openBookmarkLevels = intValue
-					return nil
-				}).
-				Bool("losslessImageCompression", &losslessImageCompression, defaultOptions.LosslessImageCompression).
-				Custom("quality", func(value string) error {
-					if value == "" {
-						quality = defaultOptions.Quality
-						return nil
-					}
-
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-
-					if intValue < 1 {
-						return errors.New("value is inferior to 1")
-					}
-
-					if intValue > 100 {
-						return errors.New("value is superior to 100")
-					}
-
-					quality = intValue
-					return nil
-				}).
-				Bool("reduceImageResolution", &reduceImageResolution, defaultOptions.ReduceImageResolution).
-				Custom("maxImageResolution", func(value string) error {
-					if value == "" {
-						maxImageResolution = defaultOptions.MaxImageResolution
-						return nil
-					}
-
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-
-					if !slices.Contains([]int{75, 150, 300, 600, 1200}, intValue) {
-						return errors.New("value is not 75, 150, 300, 600 or 1200")
-					}
-
-					maxImageResolution = intValue
-					return nil
-				}).
-				String("nativeWatermarkText", &nativeWatermarkText, defaultOptions.NativeWatermarkText).
-				Custom("nativeWatermarkColor", func(value string) error {
-					if value == "" {
-						nativeWatermarkColor = defaultOptions.NativeWatermarkColor
-						return nil
-					}
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-					nativeWatermarkColor = intValue
-					return nil
-				}).
-				Custom("nativeWatermarkFontHeight", func(value string) error {
-					if value == "" {
-						nativeWatermarkFontHeight = defaultOptions.NativeWatermarkFontHeight
-						return nil
-					}
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-					if intValue < 0 {
-						return errors.New("value is inferior to 0")
-					}
-					nativeWatermarkFontHeight = intValue
-					return nil
-				}).
-				Custom("nativeWatermarkRotateAngle", func(value string) error {
-					if value == "" {
-						nativeWatermarkRotateAngle = defaultOptions.NativeWatermarkRotateAngle
-						return nil
-					}
-					intValue, err := strconv.Atoi(value)
-					if err != nil {
-						return err
-					}
-					nativeWatermarkRotateAngle = intValue
-					return nil
-				}).
-				String("nativeWatermarkFontName", &nativeWatermarkFontName, defaultOptions.NativeWatermarkFontName).
-				String("nativeTiledWatermarkText", &nativeTiledWatermarkText, defaultOptions.NativeTiledWatermarkText).
 				Bool("nativePdfFormats", &nativePdfFormats, true).
 				Bool("merge", &merge, false).
 				Bool("flatten", &flatten, false).
@@ -348,123 +316,16 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 			hasPostProcessing := len(watermarks) > 0 || len(stamps) > 0 || angle != 0 ||
 				len(embedPaths) > 0 || len(metadata) > 0 || flatten || facturX.ConformanceLevel != ""
 
-			outputPaths := make([]string, len(inputPaths))
-			for i, inputPath := range inputPaths {
-				outputPaths[i] = ctx.GeneratePath(".pdf")
-				options := libreofficeapi.Options{
-					Password:                        password,
-					Landscape:                       landscape,
-					PageRanges:                      nativePageRanges,
-					UpdateIndexes:                   updateIndexes,
-					ExportFormFields:                exportFormFields,
-					AllowDuplicateFieldNames:        allowDuplicateFieldNames,
-					ExportBookmarks:                 exportBookmarks,
-					ExportBookmarksToPdfDestination: exportBookmarksToPdfDestination,
-					ExportPlaceholders:              exportPlaceholders,
-					ExportNotes:                     exportNotes,
-					ExportNotesPages:                exportNotesPages,
-					ExportOnlyNotesPages:            exportOnlyNotesPages,
-					ExportNotesInMargin:             exportNotesInMargin,
-					ConvertOooTargetToPdfTarget:     convertOooTargetToPdfTarget,
-					ExportLinksRelativeFsys:         exportLinksRelativeFsys,
-					ExportHiddenSlides:              exportHiddenSlides,
-					SkipEmptyPages:                  skipEmptyPages,
-					AddOriginalDocumentAsStream:     addOriginalDocumentAsStream,
-					SinglePageSheets:                singlePageSheets,
-					InitialView:                     initialView,
-					InitialPage:                     initialPage,
-					Magnification:                   magnification,
-					Zoom:                            zoom,
-					PageLayout:                      pageLayout,
-					FirstPageOnLeft:                 firstPageOnLeft,
-					ResizeWindowToInitialPage:       resizeWindowToInitialPage,
-					CenterWindow:                    centerWindow,
-					OpenInFullScreenMode:            openInFullScreenMode,
-					DisplayPDFDocumentTitle:         displayPDFDocumentTitle,
-					HideViewerMenubar:               hideViewerMenubar,
-					HideViewerToolbar:               hideViewerToolbar,
-					HideViewerWindowControls:        hideViewerWindowControls,
-					UseTransitionEffects:            useTransitionEffects,
-					OpenBookmarkLevels:              openBookmarkLevels,
-					LosslessImageCompression:        losslessImageCompression,
-					Quality:                         quality,
-					ReduceImageResolution:           reduceImageResolution,
-					MaxImageResolution:              maxImageResolution,
-					NativeWatermarkText:             nativeWatermarkText,
-					NativeWatermarkColor:            nativeWatermarkColor,
-					NativeWatermarkFontHeight:       nativeWatermarkFontHeight,
-					NativeWatermarkRotateAngle:      nativeWatermarkRotateAngle,
-					NativeWatermarkFontName:         nativeWatermarkFontName,
-					NativeTiledWatermarkText:        nativeTiledWatermarkText,
-				}
-
-				if nativePdfFormats && splitMode == zeroValuedSplitMode && !hasPostProcessing {
-					// Only natively apply given PDF formats if we're not
-					// splitting the PDF later and no post-processing features
-					// are enabled (as they would degrade compliance).
-					options.PdfFormats = pdfFormats
-				}
-
-				err = libreOffice.Pdf(ctx, ctx.Log(), inputPath, outputPaths[i], options)
-				if err != nil {
-					if errors.Is(err, libreofficeapi.ErrInvalidPdfFormats) {
-						return api.WrapError(
-							fmt.Errorf("convert to PDF: %w", err),
-							api.NewSentinelHttpError(
-								http.StatusBadRequest,
-								fmt.Sprintf("The PDF format '%s' is not supported. Valid formats include PDF/A-1b, PDF/A-2b, PDF/A-3b, and PDF/UA.", pdfFormats.PdfA),
-							),
-						)
-					}
-
-					filename := ctx.OriginalFilename(inputPath)
-
-					if errors.Is(err, libreofficeapi.ErrIoException) || errors.Is(err, libreofficeapi.ErrIllegalArgumentException) {
-						return api.WrapError(
-							fmt.Errorf("convert to PDF: %w", err),
-							api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("LibreOffice could not read the document '%s'. Ensure the file is not corrupted and that its extension matches its actual format.", filename)),
-						)
-					}
-
-					if errors.Is(err, libreofficeapi.ErrCannotConvertException) {
-						return api.WrapError(
-							fmt.Errorf("convert to PDF: %w", err),
-							api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("LibreOffice read the document '%s' but could not convert it to PDF. The document may be corrupted or rely on an unsupported feature.", filename)),
-						)
-					}
-
-					// Exit codes 5 and 6 name the UNO exception class that was
-					// caught, not a cause: both cover a client mistake and a
-					// LibreOffice crash. Blame the client only when one of its
-					// inputs is actually implicated, since the server is the
-					// only remaining explanation otherwise. Password evidence
-					// outranks page ranges: a password failure aborts on import,
-					// before the export filter applies any page range.
-					// See https://github.com/gotenberg/gotenberg/issues/1588.
-					if errors.Is(err, libreofficeapi.ErrUnoException) || errors.Is(err, libreofficeapi.ErrRuntimeException) {
-						protection := libreofficeapi.DetectPasswordProtection(inputPath)
-
-						var sentinel api.SentinelHttpError
-						switch {
-						case protection == libreofficeapi.PasswordProtectionRequired && options.Password == "":
-							sentinel = api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' is password-protected. Provide its password in the 'password' form field.", filename))
-						case protection == libreofficeapi.PasswordProtectionRequired:
-							sentinel = api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The password for the document '%s' is incorrect. Check the 'password' form field.", filename))
-						case protection == libreofficeapi.PasswordProtectionNone && options.Password != "":
-							sentinel = api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' is not password-protected. Remove the 'password' form field.", filename))
-						case options.Password != "":
-							sentinel = api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("LibreOffice could not open the document '%s' with the given password. Check the 'password' form field, and omit it if the document is not password-protected.", filename))
-						case errors.Is(err, libreofficeapi.ErrUnoException) && options.PageRanges != "":
-							sentinel = api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("LibreOffice could not apply the page ranges '%s' to the document '%s'. Check the 'nativePageRanges' form field; valid values look like '1-4', '2' or '1,3,5-7'.", options.PageRanges, filename))
-						default:
-							sentinel = api.NewSentinelHttpError(http.StatusInternalServerError, fmt.Sprintf(unattributableFailureMessage, filename))
-						}
-
-						return api.WrapError(fmt.Errorf("convert to PDF: %w", err), sentinel)
-					}
+			if nativePdfFormats && splitMode == zeroValuedSplitMode && !hasPostProcessing {
+				// Only natively apply given PDF formats if we're not splitting
+				// the PDF later and no post-processing features are enabled
+				// (as they would degrade compliance).
+				options.PdfFormats = pdfFormats
+			}
 
-					return fmt.Errorf("convert to PDF: %w", err)
-				}
+			outputPaths, err := convertDocuments(ctx, libreOffice, inputPaths, options)
+			if err != nil {
+				return err
 			}
 
 			if merge {
@@ -601,3 +462,74 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 		},
 	}
 }
+
+// convertDocuments converts the documents to PDF with LibreOffice, and returns
+// the output paths.
+func convertDocuments(ctx *api.Context, libreOffice libreofficeapi.Uno, inputPaths []string, options libreofficeapi.Options) ([]string, error) {
+	outputPaths := make([]string, len(inputPaths))
+	for i, inputPath := range inputPaths {
+		outputPaths[i] = ctx.GeneratePath(".pdf")
+		err := libreOffice.Pdf(ctx, ctx.Log(), inputPath, outputPaths[i], options)
+		if err != nil {
+			if errors.Is(err, libreofficeapi.ErrInvalidPdfFormats) {
+				return nil, api.WrapError(
+					fmt.Errorf("convert to PDF: %w", err),
+					api.NewSentinelHttpError(
+						http.StatusBadRequest,
+						fmt.Sprintf("The PDF format '%s' is not supported. Valid formats include PDF/A-1b, PDF/A-2b, PDF/A-3b, and PDF/UA.", options.PdfFormats.PdfA),
+					),
+				)
+			}
+
+			filename := ctx.OriginalFilename(inputPath)
+
+			if errors.Is(err, libreofficeapi.ErrIoException) || errors.Is(err, libreofficeapi.ErrIllegalArgumentException) {
+				return nil, api.WrapError(
+					fmt.Errorf("convert to PDF: %w", err),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("LibreOffice could not read the document '%s'. Ensure the file is not corrupted and that its extension matches its actual format.", filename)),
+				)
+			}
+
+			if errors.Is(err, libreofficeapi.ErrCannotConvertException) {
+				return nil, api.WrapError(
+					fmt.Errorf("convert to PDF: %w", err),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("LibreOffice read the document '%s' but could not convert it to PDF. The document may be corrupted or rely on an unsupported feature.", filename)),
+				)
+			}
+
+			// Exit codes 5 and 6 name the UNO exception class that was
+			// caught, not a cause: both cover a client mistake and a
+			// LibreOffice crash. Blame the client only when one of its
+			// inputs is actually implicated, since the server is the
+			// only remaining explanation otherwise. Password evidence
+			// outranks page ranges: a password failure aborts on import,
+			// before the export filter applies any page range.
+			// See https://github.com/gotenberg/gotenberg/issues/1588.
+			if errors.Is(err, libreofficeapi.ErrUnoException) || errors.Is(err, libreofficeapi.ErrRuntimeException) {
+				protection := libreofficeapi.DetectPasswordProtection(inputPath)
+
+				var sentinel api.SentinelHttpError
+				switch {
+				case protection == libreofficeapi.PasswordProtectionRequired && options.Password == "":
+					sentinel = api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' is password-protected. Provide its password in the 'password' form field.", filename))
+				case protection == libreofficeapi.PasswordProtectionRequired:
+					sentinel = api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The password for the document '%s' is incorrect. Check the 'password' form field.", filename))
+				case protection == libreofficeapi.PasswordProtectionNone && options.Password != "":
+					sentinel = api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' is not password-protected. Remove the 'password' form field.", filename))
+				case options.Password != "":
+					sentinel = api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("LibreOffice could not open the document '%s' with the given password. Check the 'password' form field, and omit it if the document is not password-protected.", filename))
+				case errors.Is(err, libreofficeapi.ErrUnoException) && options.PageRanges != "":
+					sentinel = api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("LibreOffice could not apply the page ranges '%s' to the document '%s'. Check the 'nativePageRanges' form field; valid values look like '1-4', '2' or '1,3,5-7'.", options.PageRanges, filename))
+				default:
+					sentinel = api.NewSentinelHttpError(http.StatusInternalServerError, fmt.Sprintf(unattributableFailureMessage, filename))
+				}
+
+				return nil, api.WrapError(fmt.Errorf("convert to PDF: %w", err), sentinel)
+			}
+
+			return nil, fmt.Errorf("convert to PDF: %w", err)
+		}
+	}
+
+	return outputPaths, nil
+}
@@ -0,0 +1,51 @@
+package libreoffice
+
+import (
+	"fmt"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pipeline"
+)
+
+// convertStep returns a [pipeline.Step] which converts the request's
+// documents to PDF with LibreOffice.
+func convertStep(libreOffice libreofficeapi.Uno) pipeline.Step {
+	return pipeline.Step{
+		Name:    "libreoffice/convert",
+		Convert: true,
+		Run: func(ctx *api.Context, _ []string) ([]string, error) {
+			form := ctx.FormData()
+			options := formDataLibreOfficeOptions(form)
+
+			var inputPaths []string
+			err := form.
+				MandatoryPaths(libreOffice.Extensions(), &inputPaths).
+				Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			outputPaths, err := convertDocuments(ctx, libreOffice, inputPaths, options)
+			if err != nil {
+				return nil, err
+			}
+
+			// document.docx -> document.docx.pdf, so that the next steps and
+			// the .zip archive keep the original names.
+			for i, inputPath := range inputPaths {
+				originalName := ctx.OriginalFilename(inputPath)
+				outputPath := ctx.GeneratePathFromFilename(originalName + ".pdf")
+
+				err = ctx.Rename(outputPaths[i], outputPath)
+				if err != nil {
+					return nil, fmt.Errorf("rename output path: %w", err)
+				}
+
+				outputPaths[i] = outputPath
+			}
+
+			return outputPaths, nil
+		},
+	}
+}
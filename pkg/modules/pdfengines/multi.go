@@ -114,6 +114,7 @@ func runWithFallback[T any](
 		select {
 		case result := <-resultChan:
 			if result.err == nil {
+				gotenberg.RecordEngine(ctx, engineName(engine))
 				span.SetAttributes(
 					attribute.String("gotenberg.pdf_engine.selected", engineName(engine)),
 					attribute.Int("gotenberg.pdf_engine.attempts", attempt+1),
@@ -10,6 +10,7 @@ import (
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pipeline"
 )
 
 func init() {
@@ -367,6 +368,34 @@ func (mod *PdfEngines) Routes() ([]api.Route, error) {
 	}, nil
 }
 
+// PipelineSteps returns the steps for the pipeline module.
+func (mod *PdfEngines) PipelineSteps() ([]pipeline.Step, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	engine, err := mod.PdfEngine()
+	if err != nil {
+		return nil, fmt.Errorf("get pdf mod: %w", err)
+	}
+
+	return []pipeline.Step{
+		mergeStep(engine),
+		splitStep(engine),
+		flattenStep(engine),
+		optimizeStep(engine),
+		convertStep(engine),
+		writeMetadataStep(engine),
+		writeBookmarksStep(engine),
+		encryptStep(engine),
+		embedStep(engine),
+		watermarkStep(engine),
+		stampStep(engine),
+		rotateStep(engine),
+		facturXStep(engine),
+	}, nil
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module            = (*PdfEngines)(nil)
@@ -375,4 +404,5 @@ var (
 	_ gotenberg.SystemLogger      = (*PdfEngines)(nil)
 	_ gotenberg.PdfEngineProvider = (*PdfEngines)(nil)
 	_ api.Router                  = (*PdfEngines)(nil)
+	_ pipeline.StepProvider       = (*PdfEngines)(nil)
 )
@@ -442,16 +442,14 @@ func WriteMetadataStub(ctx *api.Context, engine gotenberg.PdfEngine, metadata ma
 	return nil
 }
 
-// documentTitle returns the input PDF's Title metadata entry, falling back to
-// the original filename without its extension when the entry is absent, blank,
-// or cannot be read. It labels the per-document entries the merge route's
-// titleBookmarks feature generates.
-func documentTitle(ctx *api.Context, engine gotenberg.PdfEngine, inputPath, filename string) string {
-	fallback := strings.TrimSuffix(filename, filepath.Ext(filename))
-
+// DocumentTitle returns the input PDF's Title metadata entry, falling back to
+// the given value when the entry is absent, blank, or cannot be read. It
+// labels the per-document entries the merge route's titleBookmarks feature
+// generates.
+func DocumentTitle(ctx *api.Context, engine gotenberg.PdfEngine, inputPath, fallback string) string {
 	metadata, err := engine.ReadMetadata(ctx, ctx.Log(), inputPath)
 	if err != nil {
-		ctx.Log().WarnContext(ctx, fmt.Sprintf("read metadata of '%s' for title bookmark, using filename: %s", filename, err))
+		ctx.Log().WarnContext(ctx, fmt.Sprintf("read metadata for title bookmark, using '%s': %s", fallback, err))
 		return fallback
 	}
 
@@ -463,7 +461,9 @@ func documentTitle(ctx *api.Context, engine gotenberg.PdfEngine, inputPath, file
 	return title
 }
 
-func shiftBookmarks(bookmarks []gotenberg.Bookmark, offset int) []gotenberg.Bookmark {
+// ShiftBookmarks returns the bookmarks with their pages shifted by the given
+// offset, e.g., the page count of the PDFs merged before.
+func ShiftBookmarks(bookmarks []gotenberg.Bookmark, offset int) []gotenberg.Bookmark {
 	if offset == 0 {
 		return bookmarks
 	}
@@ -472,7 +472,7 @@ func shiftBookmarks(bookmarks []gotenberg.Bookmark, offset int) []gotenberg.Book
 		shifted[i] = gotenberg.Bookmark{
 			Title:    b.Title,
 			Page:     b.Page + offset,
-			Children: shiftBookmarks(b.Children, offset),
+			Children: ShiftBookmarks(b.Children, offset),
 		}
 	}
 	return shifted
@@ -1116,11 +1116,11 @@ func mergeRoute(engine gotenberg.PdfEngine) api.Route {
 							fileBookmarks = fb
 						}
 
-						fileBookmarks = shiftBookmarks(fileBookmarks, offset)
+						fileBookmarks = ShiftBookmarks(fileBookmarks, offset)
 
 						if titleBookmarks {
 							finalBookmarks = append(finalBookmarks, gotenberg.Bookmark{
-								Title:    documentTitle(ctx, engine, inputPath, filename),
+								Title:    DocumentTitle(ctx, engine, inputPath, strings.TrimSuffix(filename, filepath.Ext(filename))),
 								Page:     offset + 1,
 								Children: fileBookmarks,
 							})
@@ -0,0 +1,396 @@
+package pdfengines
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pipeline"
+)
+
+// mergeStep returns a [pipeline.Step] which merges the PDFs into one.
+func mergeStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/merge",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			outputPath, err := MergeStub(ctx, engine, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("merge PDFs: %w", err)
+			}
+
+			return []string{outputPath}, nil
+		},
+	}
+}
+
+// splitStep returns a [pipeline.Step] which splits the PDFs.
+func splitStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/split",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			mode := FormDataPdfSplitMode(form, true)
+
+			err := form.Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			outputPaths, err := SplitPdfStub(ctx, engine, mode, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("split PDFs: %w", err)
+			}
+
+			return outputPaths, nil
+		},
+	}
+}
+
+// flattenStep returns a [pipeline.Step] which flattens the PDFs.
+func flattenStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/flatten",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			err := FlattenStub(ctx, engine, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("flatten PDFs: %w", err)
+			}
+
+			return inputPaths, nil
+		},
+	}
+}
+
+// optimizeStep returns a [pipeline.Step] which optimizes the images of the
+// PDFs.
+func optimizeStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/optimize",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			// Like the route, this step optimizes unconditionally.
+			_, imageQuality := FormDataPdfOptimize(form)
+
+			err := form.Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			err = OptimizeStub(ctx, engine, true, imageQuality, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("optimize PDF images: %w", err)
+			}
+
+			return inputPaths, nil
+		},
+	}
+}
+
+// convertStep returns a [pipeline.Step] which converts the PDFs to PDF/A
+// and/or PDF/UA.
+func convertStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/convert",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			pdfFormats := FormDataPdfFormats(form)
+
+			err := form.Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			zeroValued := gotenberg.PdfFormats{}
+			if pdfFormats == zeroValued {
+				return nil, api.WrapError(
+					errors.New("no PDF formats"),
+					api.NewSentinelHttpError(
+						http.StatusBadRequest,
+						"Invalid form data: either 'pdfa' or 'pdfua' form fields must be provided",
+					),
+				)
+			}
+
+			outputPaths, err := ConvertStub(ctx, engine, pdfFormats, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("convert PDFs: %w", err)
+			}
+
+			// Keep the original filenames for the next steps.
+			for i, inputPath := range inputPaths {
+				err = ctx.Rename(outputPaths[i], inputPath)
+				if err != nil {
+					return nil, fmt.Errorf("rename output path: %w", err)
+				}
+				outputPaths[i] = inputPath
+			}
+
+			return outputPaths, nil
+		},
+	}
+}
+
+// writeMetadataStep returns a [pipeline.Step] which writes the metadata of the
+// PDFs.
+func writeMetadataStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/metadata/write",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			metadata := FormDataPdfMetadata(form, true)
+
+			err := form.Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			err = WriteMetadataStub(ctx, engine, metadata, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("write metadata: %w", err)
+			}
+
+			return inputPaths, nil
+		},
+	}
+}
+
+// writeBookmarksStep returns a [pipeline.Step] which writes the bookmarks of
+// the PDFs.
+func writeBookmarksStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/bookmarks/write",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			bookmarks := FormDataPdfBookmarks(form, true)
+
+			err := form.Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			err = WriteBookmarksStub(ctx, engine, bookmarks, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("write bookmarks: %w", err)
+			}
+
+			return inputPaths, nil
+		},
+	}
+}
+
+// encryptStep returns a [pipeline.Step] which adds password protection to the
+// PDFs.
+func encryptStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/encrypt",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			encrypt := FormDataPdfEncrypt(form)
+
+			err := form.Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			if encrypt.UserPassword == "" && encrypt.OwnerPassword == "" {
+				return nil, api.WrapError(
+					errors.New("no password provided"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: a 'userPassword' or 'ownerPassword' is required"),
+				)
+			}
+
+			err = EncryptPdfStub(ctx, engine, encrypt, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("encrypt PDFs: %w", err)
+			}
+
+			return inputPaths, nil
+		},
+	}
+}
+
+// embedStep returns a [pipeline.Step] which embeds the request's "embeds"
+// files into the PDFs.
+func embedStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/embed",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			embedPaths := FormDataPdfEmbeds(form)
+			embedsMetadata := FormDataPdfEmbedsMetadata(form)
+
+			err := form.Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			err = EmbedFilesStub(ctx, engine, embedPaths, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("embed files into PDFs: %w", err)
+			}
+
+			err = EmbedFilesMetadataStub(ctx, engine, embedsMetadata, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("set embeds metadata: %w", err)
+			}
+
+			return inputPaths, nil
+		},
+	}
+}
+
+// watermarkStep returns a [pipeline.Step] which adds watermarks to the PDFs.
+func watermarkStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/watermark",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			watermarks, err := FormDataPdfWatermarks(form)
+			if err != nil {
+				return nil, fmt.Errorf("form data watermarks: %w", err)
+			}
+
+			var watermarkFiles []string
+			err = form.
+				Watermarks(&watermarkFiles).
+				Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			if len(watermarks) == 0 {
+				return nil, api.WrapError(
+					errors.New("no watermark provided"),
+					api.NewSentinelHttpError(
+						http.StatusBadRequest,
+						"Invalid form data: form field 'watermarkSource' is required",
+					),
+				)
+			}
+
+			err = BindWatermarkFiles(watermarks, watermarkFiles)
+			if err != nil {
+				return nil, fmt.Errorf("bind watermark files: %w", err)
+			}
+
+			err = WatermarkStub(ctx, engine, watermarks, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("watermark PDFs: %w", err)
+			}
+
+			return inputPaths, nil
+		},
+	}
+}
+
+// stampStep returns a [pipeline.Step] which adds stamps to the PDFs.
+func stampStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/stamp",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			stamps, err := FormDataPdfStamps(form)
+			if err != nil {
+				return nil, fmt.Errorf("form data stamps: %w", err)
+			}
+
+			var stampFiles []string
+			err = form.
+				Stamps(&stampFiles).
+				Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			if len(stamps) == 0 {
+				return nil, api.WrapError(
+					errors.New("no stamp provided"),
+					api.NewSentinelHttpError(
+						http.StatusBadRequest,
+						"Invalid form data: form field 'stampSource' is required",
+					),
+				)
+			}
+
+			err = BindStampFiles(stamps, stampFiles)
+			if err != nil {
+				return nil, fmt.Errorf("bind stamp files: %w", err)
+			}
+
+			err = StampStub(ctx, engine, stamps, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("stamp PDFs: %w", err)
+			}
+
+			return inputPaths, nil
+		},
+	}
+}
+
+// rotateStep returns a [pipeline.Step] which rotates pages of the PDFs.
+func rotateStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/rotate",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			angle, pages := FormDataPdfRotate(form, true)
+
+			err := form.Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			err = RotateStub(ctx, engine, angle, pages, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("rotate PDFs: %w", err)
+			}
+
+			return inputPaths, nil
+		},
+	}
+}
+
+// facturXStep returns a [pipeline.Step] which turns the PDFs into Factur-X
+// documents, with the request's "facturxXml" file.
+func facturXStep(engine gotenberg.PdfEngine) pipeline.Step {
+	return pipeline.Step{
+		Name: "pdfengines/factur-x",
+		Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+			form := ctx.FormData()
+			pdfFormats := FormDataPdfFormats(form)
+			facturX, facturxXmlPath := FormDataPdfFacturX(form)
+
+			err := form.Validate()
+			if err != nil {
+				return nil, fmt.Errorf("validate form data: %w", err)
+			}
+
+			if facturX.ConformanceLevel == "" || facturxXmlPath == "" {
+				return nil, api.WrapError(
+					errors.New("facturxConformanceLevel and facturxXml are required"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: 'facturxConformanceLevel' and 'facturxXml' are both required"),
+				)
+			}
+
+			err = ValidateFacturXCompat(facturX, facturxXmlPath, pdfFormats)
+			if err != nil {
+				return nil, err
+			}
+
+			pdfFormats = FacturXPdfFormats(ctx, engine, facturX, pdfFormats, false, inputPaths)
+			outputPaths, err := ConvertStub(ctx, engine, pdfFormats, inputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("convert PDFs: %w", err)
+			}
+
+			err = ApplyFacturXStub(ctx, engine, facturX, facturxXmlPath, outputPaths)
+			if err != nil {
+				return nil, fmt.Errorf("apply Factur-X: %w", err)
+			}
+
+			return outputPaths, nil
+		},
+	}
+}
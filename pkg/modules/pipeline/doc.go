@@ -0,0 +1,4 @@
+// Package pipeline provides a route which runs several steps, e.g., a
+// conversion followed by a merge and an encryption, within a single request.
+// Other modules provide the steps.
+package pipeline
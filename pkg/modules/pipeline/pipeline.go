@@ -0,0 +1,128 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Pipeline))
+}
+
+// Pipeline is a module that provides a route for running the steps of other
+// modules one after another, within the same working directory.
+type Pipeline struct {
+	steps         []Step
+	disableRoutes bool
+}
+
+// StepProvider is a module interface that adds steps to the [Pipeline].
+type StepProvider interface {
+	PipelineSteps() ([]Step, error)
+}
+
+// Step is a step of a pipeline.
+type Step struct {
+	// Name is the name of the step in the pipeline definition, e.g.,
+	// "pdfengines/merge".
+	// Required.
+	Name string
+
+	// Convert tells if the step converts the request's files to PDF. Such a
+	// step ignores its input paths, and may only be the first step.
+	// Optional.
+	Convert bool
+
+	// Run processes the input paths and returns the output paths. The
+	// [api.Context.FormData] of the given context reads the step's options
+	// instead of the request's form fields.
+	// Required.
+	Run func(ctx *api.Context, inputPaths []string) ([]string, error)
+}
+
+// Descriptor returns a [Pipeline]'s module descriptor.
+func (mod *Pipeline) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "pipeline",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("pipeline", flag.ExitOnError)
+			fs.Bool("pipeline-disable-routes", false, "Disable the route")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Pipeline) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Pipeline) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disableRoutes = flags.MustBool("pipeline-disable-routes")
+
+	if mod.disableRoutes {
+		return nil
+	}
+
+	mods, err := ctx.Modules(new(StepProvider))
+	if err != nil {
+		return fmt.Errorf("get step providers: %w", err)
+	}
+
+	for _, provider := range mods {
+		steps, err := provider.(StepProvider).PipelineSteps()
+		if err != nil {
+			return fmt.Errorf("get steps: %w", err)
+		}
+
+		mod.steps = append(mod.steps, steps...)
+	}
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Pipeline) Validate() error {
+	stepsMap := make(map[string]string, len(mod.steps))
+
+	for _, step := range mod.steps {
+		if step.Name == "" {
+			return errors.New("step with empty name cannot be registered")
+		}
+
+		if step.Run == nil {
+			return fmt.Errorf("step '%s' has a nil run function", step.Name)
+		}
+
+		if _, ok := stepsMap[step.Name]; ok {
+			return fmt.Errorf("step '%s' is already registered", step.Name)
+		}
+
+		stepsMap[step.Name] = step.Name
+	}
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *Pipeline) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		pipelineRoute(mod.steps),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Pipeline)(nil)
+	_ gotenberg.Provisioner = (*Pipeline)(nil)
+	_ gotenberg.Validator   = (*Pipeline)(nil)
+	_ api.Router            = (*Pipeline)(nil)
+)
@@ -0,0 +1,52 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestPipeline_Validate(t *testing.T) {
+	run := func(_ *api.Context, inputPaths []string) ([]string, error) {
+		return inputPaths, nil
+	}
+
+	for _, tc := range []struct {
+		scenario  string
+		steps     []Step
+		expectErr bool
+	}{
+		{
+			scenario:  "step with empty name",
+			steps:     []Step{{Run: run}},
+			expectErr: true,
+		},
+		{
+			scenario:  "step without run function",
+			steps:     []Step{{Name: "foo"}},
+			expectErr: true,
+		},
+		{
+			scenario:  "duplicate steps",
+			steps:     []Step{{Name: "foo", Run: run}, {Name: "foo", Run: run}},
+			expectErr: true,
+		},
+		{
+			scenario: "success",
+			steps:    []Step{{Name: "foo", Run: run}, {Name: "bar", Run: run}},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Pipeline{steps: tc.steps}
+			err := mod.Validate()
+
+			if tc.expectErr && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectErr && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// stepDefinition is an entry of the "steps" form field.
+type stepDefinition struct {
+	// Name is the name of a registered [Step].
+	Name string `json:"name"`
+
+	// Options are the step's form fields, as a JSON object (see
+	// [api.JsonFormValues]).
+	Options json.RawMessage `json:"options"`
+}
+
+// stepError wraps the error of a step so that the client knows which step
+// failed. It keeps the status code of the original error.
+func stepError(index int, name string, err error) error {
+	status, message := api.ParseError(err)
+
+	return api.WrapError(
+		fmt.Errorf("step %d '%s': %w", index, name, err),
+		api.NewSentinelHttpError(status, fmt.Sprintf("Step %d ('%s') failed: %s", index, name, message)),
+	)
+}
+
+// invalidStepError returns the error of an invalid step definition.
+func invalidStepError(index int, message string) error {
+	return api.WrapError(
+		fmt.Errorf("invalid step %d: %s", index, message),
+		api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: step %d is invalid: %s", index, message)),
+	)
+}
+
+// stepsSchema returns the JSON schema of the "steps" form field.
+func stepsSchema(names []string) json.RawMessage {
+	schema, err := json.Marshal(map[string]any{
+		"type":        "array",
+		"description": "Steps to run, in order. Only the first step may be a conversion step.",
+		"items": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{
+					"type": "string",
+					"enum": names,
+				},
+				"options": map[string]any{
+					"type":        "object",
+					"description": "Form fields of the step, as for the route of the same name, e.g., {\"landscape\":true}.",
+				},
+			},
+			"required": []string{"name"},
+		},
+	})
+	if err != nil {
+		// Cannot happen with the types above.
+		panic(fmt.Sprintf("marshal steps schema: %s", err))
+	}
+
+	return schema
+}
+
+// pipelineRoute returns an [api.Route] which runs the given steps, in order,
+// within the same working directory. It is not cached, as a step may convert
+// remote content, e.g., a URL.
+func pipelineRoute(steps []Step) api.Route {
+	stepsByName := make(map[string]Step, len(steps))
+	names := make([]string, 0, len(steps))
+	var convertNames []string
+	for _, step := range steps {
+		stepsByName[step.Name] = step
+		names = append(names, step.Name)
+		if step.Convert {
+			convertNames = append(convertNames, fmt.Sprintf("'%s'", step.Name))
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(convertNames)
+
+	// The conversion steps read the files of their own route.
+	filesDescription := "PDF files to process (*.pdf)."
+	if len(convertNames) > 0 {
+		filesDescription = fmt.Sprintf("PDF files to process (*.pdf) or, if the first step is a conversion step (%s), the files of the route of the same name, e.g., HTML or Office documents.", strings.Join(convertNames, ", "))
+	}
+
+	return api.Route{
+		Method:           http.MethodPost,
+		Path:             "/forms/pipeline",
+		IsMultipart:      true,
+		DisableCache:     true,
+		FormFieldSchemas: map[string]json.RawMessage{"steps": stepsSchema(names)},
+		FilesDescription: filesDescription,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			var (
+				definitions []stepDefinition
+				inputPaths  []string
+			)
+
+			err := ctx.FormData().
+				MandatoryCustom("steps", func(value string) error {
+					err := json.Unmarshal([]byte(value), &definitions)
+					if err != nil {
+						return fmt.Errorf("unmarshal steps: %w", err)
+					}
+
+					if len(definitions) == 0 {
+						return errors.New("no step")
+					}
+
+					return nil
+				}).
+				Paths([]string{".pdf"}, &inputPaths).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// Check the whole pipeline before running anything.
+			values := make([]map[string][]string, len(definitions))
+			for i, definition := range definitions {
+				step, ok := stepsByName[definition.Name]
+				if !ok {
+					return invalidStepError(i, fmt.Sprintf("unknown step '%s'", definition.Name))
+				}
+
+				if step.Convert && i > 0 {
+					return invalidStepError(i, fmt.Sprintf("'%s' may only be the first step", definition.Name))
+				}
+
+				values[i] = make(map[string][]string)
+				if len(definition.Options) > 0 {
+					values[i], err = api.JsonFormValues(definition.Options)
+					if err != nil {
+						return invalidStepError(i, "'options' must be a JSON object")
+					}
+				}
+			}
+
+			if !stepsByName[definitions[0].Name].Convert && len(inputPaths) == 0 {
+				return api.WrapError(
+					errors.New("no PDF to process"),
+					api.NewSentinelHttpError(
+						http.StatusBadRequest,
+						"Invalid form data: no form file found for extensions: [.pdf]; either upload PDFs or start with a conversion step",
+					),
+				)
+			}
+
+			paths := inputPaths
+			for i, definition := range definitions {
+				ctx.Log().DebugContext(ctx, fmt.Sprintf("run step %d '%s' on %d file(s)", i, definition.Name, len(paths)))
+
+				paths, err = stepsByName[definition.Name].Run(ctx.WithFormValues(values[i]), paths)
+				if err != nil {
+					return stepError(i, definition.Name, err)
+				}
+
+				if len(paths) == 0 {
+					return stepError(i, definition.Name, errors.New("no output file"))
+				}
+			}
+
+			err = ctx.AddOutputPaths(paths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
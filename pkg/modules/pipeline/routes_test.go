@@ -0,0 +1,222 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestPipelineRoute(t *testing.T) {
+	steps := []Step{
+		{
+			Name:    "foo/convert",
+			Convert: true,
+			Run: func(ctx *api.Context, _ []string) ([]string, error) {
+				var bar string
+				err := ctx.FormData().
+					MandatoryString("bar", &bar).
+					Validate()
+				if err != nil {
+					return nil, err
+				}
+
+				return []string{"/" + bar + ".pdf"}, nil
+			},
+		},
+		{
+			Name: "foo/append",
+			Run: func(ctx *api.Context, inputPaths []string) ([]string, error) {
+				var suffix string
+				err := ctx.FormData().
+					String("suffix", &suffix, "_foo").
+					Validate()
+				if err != nil {
+					return nil, err
+				}
+
+				outputPaths := make([]string, len(inputPaths))
+				for i, inputPath := range inputPaths {
+					outputPaths[i] = inputPath + suffix
+				}
+
+				return outputPaths, nil
+			},
+		},
+		{
+			Name: "foo/fail",
+			Run: func(_ *api.Context, _ []string) ([]string, error) {
+				return nil, api.WrapError(
+					errors.New("foo"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Foo"),
+				)
+			},
+		},
+		{
+			Name: "foo/empty",
+			Run: func(_ *api.Context, _ []string) ([]string, error) {
+				return nil, nil
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		scenario          string
+		steps             string
+		files             map[string]string
+		expectStatus      int
+		expectMessage     string
+		expectOutputPaths []string
+	}{
+		{
+			scenario:      "missing steps",
+			files:         map[string]string{"foo.pdf": "/foo.pdf"},
+			expectStatus:  http.StatusBadRequest,
+			expectMessage: "Invalid form data: form field 'steps' is required",
+		},
+		{
+			scenario:      "empty steps",
+			steps:         `[]`,
+			files:         map[string]string{"foo.pdf": "/foo.pdf"},
+			expectStatus:  http.StatusBadRequest,
+			expectMessage: "Invalid form data: form field 'steps' is invalid (got '[]', resulting to no step)",
+		},
+		{
+			scenario:      "unknown step",
+			steps:         `[{"name":"foo/append"},{"name":"foo/bar"}]`,
+			files:         map[string]string{"foo.pdf": "/foo.pdf"},
+			expectStatus:  http.StatusBadRequest,
+			expectMessage: "Invalid form data: step 1 is invalid: unknown step 'foo/bar'",
+		},
+		{
+			scenario:      "conversion step not first",
+			steps:         `[{"name":"foo/append"},{"name":"foo/convert"}]`,
+			files:         map[string]string{"foo.pdf": "/foo.pdf"},
+			expectStatus:  http.StatusBadRequest,
+			expectMessage: "Invalid form data: step 1 is invalid: 'foo/convert' may only be the first step",
+		},
+		{
+			scenario:      "invalid options",
+			steps:         `[{"name":"foo/append","options":["foo"]}]`,
+			files:         map[string]string{"foo.pdf": "/foo.pdf"},
+			expectStatus:  http.StatusBadRequest,
+			expectMessage: "Invalid form data: step 0 is invalid: 'options' must be a JSON object",
+		},
+		{
+			scenario:      "no PDF without conversion step",
+			steps:         `[{"name":"foo/append"}]`,
+			expectStatus:  http.StatusBadRequest,
+			expectMessage: "Invalid form data: no form file found for extensions: [.pdf]; either upload PDFs or start with a conversion step",
+		},
+		{
+			scenario:      "step error",
+			steps:         `[{"name":"foo/append"},{"name":"foo/fail"}]`,
+			files:         map[string]string{"foo.pdf": "/foo.pdf"},
+			expectStatus:  http.StatusBadRequest,
+			expectMessage: "Step 1 ('foo/fail') failed: Foo",
+		},
+		{
+			scenario:      "step form data error",
+			steps:         `[{"name":"foo/convert"}]`,
+			expectStatus:  http.StatusBadRequest,
+			expectMessage: "Step 0 ('foo/convert') failed: Invalid form data: form field 'bar' is required",
+		},
+		{
+			scenario:      "step without output",
+			steps:         `[{"name":"foo/empty"}]`,
+			files:         map[string]string{"foo.pdf": "/foo.pdf"},
+			expectStatus:  http.StatusInternalServerError,
+			expectMessage: "Step 0 ('foo/empty') failed: " + http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			scenario:          "success",
+			steps:             `[{"name":"foo/convert","options":{"bar":"baz"}},{"name":"foo/append"},{"name":"foo/append","options":{"suffix":"_qux"}}]`,
+			expectOutputPaths: []string{"/baz.pdf_foo_qux"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetFiles(tc.files)
+			ctx.SetLogger(slog.New(slog.DiscardHandler))
+			if tc.steps != "" {
+				ctx.SetValues(map[string][]string{"steps": {tc.steps}})
+			}
+
+			c := echo.New().NewContext(
+				httptest.NewRequest(http.MethodPost, "/forms/pipeline", nil),
+				httptest.NewRecorder(),
+			)
+			c.Set("context", ctx.Context)
+
+			err := pipelineRoute(steps).Handler(c)
+
+			if tc.expectStatus == 0 {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				if !reflect.DeepEqual(ctx.OutputPaths(), tc.expectOutputPaths) {
+					t.Errorf("expected output paths %+v, but got %+v", tc.expectOutputPaths, ctx.OutputPaths())
+				}
+
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			status, message := api.ParseError(err)
+			if status != tc.expectStatus {
+				t.Errorf("expected status %d, but got %d (message: %s)", tc.expectStatus, status, message)
+			}
+
+			if message != tc.expectMessage {
+				t.Errorf("expected message '%s', but got '%s'", tc.expectMessage, message)
+			}
+		})
+	}
+}
+
+func TestPipelineRoute_Description(t *testing.T) {
+	route := pipelineRoute([]Step{
+		{Name: "foo/merge"},
+		{Name: "foo/convert", Convert: true},
+	})
+
+	var schema struct {
+		Type  string `json:"type"`
+		Items struct {
+			Properties struct {
+				Name struct {
+					Enum []string `json:"enum"`
+				} `json:"name"`
+			} `json:"properties"`
+		} `json:"items"`
+	}
+	err := json.Unmarshal(route.FormFieldSchemas["steps"], &schema)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if schema.Type != "array" {
+		t.Errorf("expected 'steps' to be an array, but got '%s'", schema.Type)
+	}
+
+	expectNames := []string{"foo/convert", "foo/merge"}
+	if !reflect.DeepEqual(schema.Items.Properties.Name.Enum, expectNames) {
+		t.Errorf("expected step names %+v, but got %+v", expectNames, schema.Items.Properties.Name.Enum)
+	}
+
+	if !strings.Contains(route.FilesDescription, "'foo/convert'") {
+		t.Errorf("expected the files description to mention the conversion steps, but got '%s'", route.FilesDescription)
+	}
+}
@@ -120,10 +120,14 @@ func (mod *Prometheus) Validate() error {
 			return errors.New("metric name cannot be empty")
 		}
 
-		if metric.Read == nil {
+		if metric.Label == "" && metric.Read == nil {
 			return fmt.Errorf("metric '%s' has nil read method", metric.Name)
 		}
 
+		if metric.Label != "" && metric.ReadSeries == nil {
+			return fmt.Errorf("metric '%s' has nil read series method", metric.Name)
+		}
+
 		if _, ok := metricsMap[metric.Name]; ok {
 			return fmt.Errorf("metric '%s' is already registered", metric.Name)
 		}
@@ -142,6 +146,47 @@ func (mod *Prometheus) Start() error {
 	}
 
 	for _, metric := range mod.metrics {
+		if metric.Label != "" {
+			gaugeVec := prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace: mod.namespace,
+					Name:      metric.Name,
+					Help:      metric.Description,
+				},
+				[]string{metric.Label},
+			)
+
+			mod.registry.MustRegister(gaugeVec)
+
+			go func(gaugeVec *prometheus.GaugeVec, metric gotenberg.Metric) {
+				for {
+					series := metric.ReadSeries()
+					// Drop the series which no longer exist.
+					gaugeVec.Reset()
+					for labelValue, value := range series {
+						gaugeVec.WithLabelValues(labelValue).Set(value)
+					}
+					time.Sleep(mod.interval)
+				}
+			}(gaugeVec, metric)
+
+			continue
+		}
+
+		if metric.Counter {
+			// Read on each scrape.
+			mod.registry.MustRegister(prometheus.NewCounterFunc(
+				prometheus.CounterOpts{
+					Namespace: mod.namespace,
+					Name:      metric.Name,
+					Help:      metric.Description,
+				},
+				metric.Read,
+			))
+
+			continue
+		}
+
 		gauge := prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: mod.namespace,
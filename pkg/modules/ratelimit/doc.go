@@ -0,0 +1,3 @@
+// Package ratelimit adds middleware for limiting the request rate and the
+// number of in-flight conversions per client.
+package ratelimit
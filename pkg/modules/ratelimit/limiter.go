@@ -0,0 +1,180 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout is the duration after which an idle client is forgotten. By
+// then, its token bucket is full again anyway, unless the rate is very low.
+const idleTimeout = time.Duration(10) * time.Minute
+
+const (
+	// maxSeries bounds the number of series of the authenticated identities:
+	// beyond, new identities share the [otherSeries] series.
+	maxSeries = 100
+
+	// anonymousSeries is the series of the callers identified by their IP
+	// only, whose number has no bound.
+	anonymousSeries = "anonymous"
+
+	// otherSeries is the series of the identities beyond [maxSeries].
+	otherSeries = "other"
+)
+
+// client holds the state of a client.
+type client struct {
+	bucket   *rate.Limiter
+	inFlight int
+	lastSeen time.Time
+}
+
+// series holds the counters of a metrics series.
+type series struct {
+	accepted uint64
+	rejected uint64
+	inFlight int
+}
+
+// limiter limits, per client, the request rate with a token bucket and the
+// number of in-flight conversions.
+type limiter struct {
+	requestsPerSecond float64
+	burst             int
+	maxConcurrent     int
+
+	mu        sync.Mutex
+	clients   map[string]*client
+	lastSweep time.Time
+	now       func() time.Time
+
+	// Unlike the clients, the series are never forgotten, as their counters
+	// only ever increase.
+	series     map[string]*series
+	identities int
+}
+
+// newLimiter returns a [limiter]. A zero requestsPerSecond or maxConcurrent
+// means no limit.
+func newLimiter(requestsPerSecond float64, burst, maxConcurrent int) *limiter {
+	return &limiter{
+		requestsPerSecond: requestsPerSecond,
+		burst:             burst,
+		maxConcurrent:     maxConcurrent,
+		clients:           make(map[string]*client),
+		series:            make(map[string]*series),
+		now:               time.Now,
+	}
+}
+
+// acquire tries to start a conversion for the given client, counted in the
+// metrics series with the given label. If the client exceeds one of its
+// limits, it returns false and the duration after which the client may
+// retry. Otherwise, the caller must call the release function once the
+// conversion is over.
+func (l *limiter) acquire(identity, label string) (func(), time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	s := l.seriesOf(label)
+
+	c, ok := l.clients[identity]
+	if !ok {
+		c = &client{}
+		if l.requestsPerSecond > 0 {
+			c.bucket = rate.NewLimiter(rate.Limit(l.requestsPerSecond), l.burst)
+		}
+		l.clients[identity] = c
+	}
+	c.lastSeen = now
+
+	if l.maxConcurrent > 0 && c.inFlight >= l.maxConcurrent {
+		s.rejected++
+		// There is no telling when a conversion ends.
+		return nil, time.Second, false
+	}
+
+	if c.bucket != nil {
+		reservation := c.bucket.ReserveN(now, 1)
+		delay := reservation.DelayFrom(now)
+		if delay > 0 {
+			reservation.CancelAt(now)
+			s.rejected++
+			return nil, delay, false
+		}
+	}
+
+	s.accepted++
+	s.inFlight++
+	c.inFlight++
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			s.inFlight--
+			c.inFlight--
+			c.lastSeen = l.now()
+		})
+	}
+
+	return release, 0, true
+}
+
+// sweep forgets the idle clients, at most once per idle timeout. The caller
+// must hold the lock.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTimeout {
+		return
+	}
+	l.lastSweep = now
+
+	for identity, c := range l.clients {
+		if c.inFlight == 0 && now.Sub(c.lastSeen) >= idleTimeout {
+			delete(l.clients, identity)
+		}
+	}
+}
+
+// seriesOf returns the series with the given label, or the [otherSeries]
+// one if there are already [maxSeries] series of authenticated identities.
+// The caller must hold the lock.
+func (l *limiter) seriesOf(label string) *series {
+	s, ok := l.series[label]
+	if ok {
+		return s
+	}
+
+	if label != anonymousSeries && label != otherSeries {
+		if l.identities >= maxSeries {
+			return l.seriesOf(otherSeries)
+		}
+
+		l.identities++
+	}
+
+	s = &series{}
+	l.series[label] = s
+
+	return s
+}
+
+// read returns a value per series.
+func (l *limiter) read(value func(s *series) float64) map[string]float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	values := make(map[string]float64, len(l.series))
+	for label, s := range l.series {
+		values[label] = value(s)
+	}
+
+	return values
+}
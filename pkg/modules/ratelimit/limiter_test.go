@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLimiter_RequestsPerSecond(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(1, 2, 0)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		release, _, ok := l.acquire("foo", "foo")
+		if !ok {
+			t.Fatalf("expected request %d to be accepted", i)
+		}
+		release()
+	}
+
+	_, retryAfter, ok := l.acquire("foo", "foo")
+	if ok {
+		t.Fatal("expected the request beyond the burst to be rejected")
+	}
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("expected a retry after in ]0s, 1s], but got %s", retryAfter)
+	}
+
+	_, _, ok = l.acquire("bar", "bar")
+	if !ok {
+		t.Error("expected another client to be accepted")
+	}
+
+	now = now.Add(time.Second)
+	_, _, ok = l.acquire("foo", "foo")
+	if !ok {
+		t.Error("expected the request to be accepted once a token is back")
+	}
+
+	if rejected := l.series["foo"].rejected; rejected != 1 {
+		t.Errorf("expected 1 rejected request for 'foo', but got %d", rejected)
+	}
+}
+
+func TestLimiter_MaxConcurrent(t *testing.T) {
+	l := newLimiter(0, 0, 1)
+
+	release, _, ok := l.acquire("foo", "foo")
+	if !ok {
+		t.Fatal("expected the first conversion to be accepted")
+	}
+
+	_, retryAfter, ok := l.acquire("foo", "foo")
+	if ok {
+		t.Fatal("expected the second concurrent conversion to be rejected")
+	}
+	if retryAfter != time.Second {
+		t.Errorf("expected a retry after of 1s, but got %s", retryAfter)
+	}
+
+	if inFlight := l.series["foo"].inFlight; inFlight != 1 {
+		t.Errorf("expected 1 in-flight conversion for 'foo', but got %d", inFlight)
+	}
+
+	release()
+	// Releasing twice is a no-op.
+	release()
+
+	_, _, ok = l.acquire("foo", "foo")
+	if !ok {
+		t.Error("expected a conversion to be accepted after the release")
+	}
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(1, 1, 0)
+	l.now = func() time.Time { return now }
+
+	release, _, _ := l.acquire("foo", "foo")
+	release()
+	_, _, _ = l.acquire("bar", "bar")
+
+	now = now.Add(idleTimeout)
+	_, _, _ = l.acquire("baz", "baz")
+
+	if _, ok := l.clients["foo"]; ok {
+		t.Error("expected idle client 'foo' to be forgotten")
+	}
+	if _, ok := l.clients["bar"]; !ok {
+		t.Error("expected client 'bar' with an in-flight conversion to be kept")
+	}
+
+	// The series of the forgotten clients remain.
+	if accepted := l.series["foo"].accepted; accepted != 1 {
+		t.Errorf("expected 1 accepted request for 'foo', but got %d", accepted)
+	}
+}
+
+func TestLimiter_Series(t *testing.T) {
+	l := newLimiter(0, 0, 1)
+
+	for i := 0; i < maxSeries+10; i++ {
+		identity := fmt.Sprintf("foo%d", i)
+		_, _, ok := l.acquire(identity, identity)
+		if !ok {
+			t.Fatalf("expected the conversion of '%s' to be accepted", identity)
+		}
+	}
+
+	// IP-only callers share a series.
+	_, _, _ = l.acquire("192.0.2.1", anonymousSeries)
+	_, _, _ = l.acquire("192.0.2.2", anonymousSeries)
+
+	accepted := l.read(func(s *series) float64 { return float64(s.accepted) })
+
+	if len(accepted) != maxSeries+2 {
+		t.Errorf("expected %d series, but got %d", maxSeries+2, len(accepted))
+	}
+
+	if accepted[otherSeries] != 10 {
+		t.Errorf("expected 10 accepted requests in the '%s' series, but got %v", otherSeries, accepted[otherSeries])
+	}
+
+	if accepted[anonymousSeries] != 2 {
+		t.Errorf("expected 2 accepted requests in the '%s' series, but got %v", anonymousSeries, accepted[anonymousSeries])
+	}
+
+	if accepted["foo0"] != 1 {
+		t.Errorf("expected 1 accepted request in the 'foo0' series, but got %v", accepted["foo0"])
+	}
+}
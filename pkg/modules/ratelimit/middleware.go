@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// clientIdentity returns the identity of the caller: the authenticated one if
+// any (basic auth username, OIDC subject or API key name), then the subject
+// of the client certificate, and finally the IP of the peer. Unlike the
+// "X-Forwarded-For" and "X-Real-IP" headers, the latter is not up to the
+// client.
+//
+// It also returns the label of the caller's metrics series: its identity if
+// authenticated, otherwise [anonymousSeries].
+func clientIdentity(c echo.Context) (string, string) {
+	if identity := api.CallerIdentity(c); identity != "" {
+		return identity, identity
+	}
+
+	host, _, err := net.SplitHostPort(c.Request().RemoteAddr)
+	if err != nil {
+		return c.Request().RemoteAddr, anonymousSeries
+	}
+
+	return host, anonymousSeries
+}
+
+func rateLimitMiddleware(l *limiter) api.Middleware {
+	return api.Middleware{
+		// Reject before parsing the request body, so that a rejected
+		// request costs nothing.
+		Stack:    api.PreMultipartStack,
+		Priority: api.VeryHighPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					identity, label := clientIdentity(c)
+
+					release, retryAfter, ok := l.acquire(identity, label)
+					if !ok {
+						c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+						return api.WrapError(
+							fmt.Errorf("client '%s' exceeds its rate limit or concurrency quota", identity),
+							api.NewSentinelHttpError(http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests)),
+						)
+					}
+
+					// Call the next middleware in the chain.
+					err := next(c)
+
+					ctx, ok := c.Get("context").(*api.Context)
+					if !ok {
+						// The request failed before its context exists.
+						release()
+						return err
+					}
+
+					// The context is done once the conversion is over, which
+					// is already the case unless it goes on in the
+					// background.
+					done := ctx.Done()
+					select {
+					case <-done:
+						release()
+					default:
+						go func() {
+							<-done
+							release()
+						}()
+					}
+
+					return err
+				}
+			}
+		}(),
+	}
+}
@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func newTestEchoContext(t *testing.T, identity string) (echo.Context, *api.ContextMock, context.CancelFunc) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/forms/foo", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(cancel)
+
+	ctx := &api.ContextMock{Context: &api.Context{Context: reqCtx}}
+	ctx.SetLogger(slog.New(slog.DiscardHandler))
+	ctx.SetEchoContext(c)
+
+	c.Set("context", ctx.Context)
+	if identity != "" {
+		c.Set("identity", identity)
+	}
+
+	return c, ctx, cancel
+}
+
+func TestClientIdentity(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		values      map[string]string
+		header      http.Header
+		expect      string
+		expectLabel string
+	}{
+		{"identity", map[string]string{"identity": "foo", "clientCertSubject": "CN=bar"}, nil, "foo", "foo"},
+		{"client certificate subject", map[string]string{"clientCertSubject": "CN=bar"}, nil, "CN=bar", "CN=bar"},
+		{"client IP", nil, nil, "192.0.2.1", anonymousSeries},
+		{"spoofed client IP", nil, http.Header{"X-Forwarded-For": {"198.51.100.1"}, "X-Real-Ip": {"198.51.100.2"}}, "192.0.2.1", anonymousSeries},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			for key, values := range tc.header {
+				req.Header[key] = values
+			}
+
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			for key, value := range tc.values {
+				c.Set(key, value)
+			}
+
+			identity, label := clientIdentity(c)
+			if identity != tc.expect {
+				t.Errorf("expected identity '%s', but got '%s'", tc.expect, identity)
+			}
+			if label != tc.expectLabel {
+				t.Errorf("expected label '%s', but got '%s'", tc.expectLabel, label)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	l := newLimiter(0, 0, 1)
+	handler := rateLimitMiddleware(l).Handler
+
+	// Stands for the context middleware, which cancels the context once a
+	// synchronous conversion is over.
+	sync := func(cancel context.CancelFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			cancel()
+			return nil
+		}
+	}
+
+	// A synchronous conversion releases its slot once done.
+	c, _, cancel := newTestEchoContext(t, "foo")
+	err := handler(sync(cancel))(c)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	// So does a request which fails before its context exists.
+	c, _, _ = newTestEchoContext(t, "foo")
+	err = handler(func(c echo.Context) error {
+		c.Set("context", nil)
+		return errors.New("foo")
+	})(c)
+	if err == nil {
+		t.Fatal("expected an error but got none")
+	}
+
+	// An asynchronous conversion keeps its slot until its context is done.
+	c, _, asyncCancel := newTestEchoContext(t, "foo")
+	err = handler(func(c echo.Context) error { return nil })(c)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	c, _, cancel = newTestEchoContext(t, "foo")
+	err = handler(sync(cancel))(c)
+	if err == nil {
+		t.Fatal("expected an error while the asynchronous conversion is in-flight")
+	}
+
+	status, _ := api.ParseError(err)
+	if status != http.StatusTooManyRequests {
+		t.Errorf("expected status %d, but got %d", http.StatusTooManyRequests, status)
+	}
+
+	if got := c.Response().Header().Get("Retry-After"); got != "1" {
+		t.Errorf("expected Retry-After '1', but got '%s'", got)
+	}
+
+	// Another client is not affected.
+	c, _, cancel = newTestEchoContext(t, "bar")
+	err = handler(sync(cancel))(c)
+	if err != nil {
+		t.Fatalf("expected no error for another client but got: %v", err)
+	}
+
+	asyncCancel()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		c, _, cancel = newTestEchoContext(t, "foo")
+		err = handler(sync(cancel))(c)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the slot to be released once the asynchronous conversion is done")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(RateLimit))
+}
+
+// RateLimit is a module that provides a middleware for limiting, per client,
+// the request rate and the number of in-flight conversions of the
+// "multipart/form-data" routes.
+type RateLimit struct {
+	requestsPerSecond float64
+	burst             int
+	maxConcurrent     int
+	limiter           *limiter
+}
+
+// Descriptor returns a [RateLimit]'s module descriptor.
+func (mod *RateLimit) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "ratelimit",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("ratelimit", flag.ExitOnError)
+			fs.Float64("ratelimit-requests-per-second", 0, "Set the number of conversion requests per second allowed per client - 0 means no limit")
+			fs.Int("ratelimit-burst", 10, "Set the number of conversion requests a client may send at once above its rate")
+			fs.Int("ratelimit-max-concurrent", 0, "Set the number of in-flight conversions allowed per client - 0 means no limit")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(RateLimit) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *RateLimit) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.requestsPerSecond = flags.MustFloat64("ratelimit-requests-per-second")
+	mod.burst = flags.MustInt("ratelimit-burst")
+	mod.maxConcurrent = flags.MustInt("ratelimit-max-concurrent")
+	mod.limiter = newLimiter(mod.requestsPerSecond, mod.burst, mod.maxConcurrent)
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *RateLimit) Validate() error {
+	var err error
+
+	if mod.requestsPerSecond < 0 || math.IsNaN(mod.requestsPerSecond) || math.IsInf(mod.requestsPerSecond, 0) {
+		err = errors.Join(err, errors.New("requests per second must be a positive number"))
+	}
+
+	if mod.requestsPerSecond > 0 && mod.burst < 1 {
+		err = errors.Join(err, errors.New("burst must be at least 1"))
+	}
+
+	if mod.maxConcurrent < 0 {
+		err = errors.Join(err, errors.New("max concurrent must be positive"))
+	}
+
+	return err
+}
+
+// Middlewares returns the middleware.
+func (mod *RateLimit) Middlewares() ([]api.Middleware, error) {
+	if !mod.enabled() {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		rateLimitMiddleware(mod.limiter),
+	}, nil
+}
+
+// Metrics returns the metrics, with a series per identity. Only the
+// authenticated callers (basic auth username, OIDC subject, API key name or
+// client certificate subject) have a series of their own, at most 100; the
+// callers identified by their IP share the "anonymous" series, and the
+// identities beyond the bound the "other" series.
+func (mod *RateLimit) Metrics() ([]gotenberg.Metric, error) {
+	if !mod.enabled() {
+		return nil, nil
+	}
+
+	return []gotenberg.Metric{
+		{
+			Name:        "ratelimit_accepted_requests",
+			Description: "Number of conversion requests accepted per identity.",
+			Label:       "identity",
+			ReadSeries: func() map[string]float64 {
+				return mod.limiter.read(func(s *series) float64 { return float64(s.accepted) })
+			},
+		},
+		{
+			Name:        "ratelimit_rejected_requests",
+			Description: "Number of conversion requests rejected per identity for exceeding a limit.",
+			Label:       "identity",
+			ReadSeries: func() map[string]float64 {
+				return mod.limiter.read(func(s *series) float64 { return float64(s.rejected) })
+			},
+		},
+		{
+			Name:        "ratelimit_in_flight_conversions",
+			Description: "Current number of in-flight conversions per identity.",
+			Label:       "identity",
+			ReadSeries: func() map[string]float64 {
+				return mod.limiter.read(func(s *series) float64 { return float64(s.inFlight) })
+			},
+		},
+	}, nil
+}
+
+// enabled tells if there is at least one limit.
+func (mod *RateLimit) enabled() bool {
+	return mod.requestsPerSecond > 0 || mod.maxConcurrent > 0
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module          = (*RateLimit)(nil)
+	_ gotenberg.Provisioner     = (*RateLimit)(nil)
+	_ gotenberg.Validator       = (*RateLimit)(nil)
+	_ gotenberg.MetricsProvider = (*RateLimit)(nil)
+	_ api.MiddlewareProvider    = (*RateLimit)(nil)
+)
@@ -76,8 +76,22 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 						echo.HeaderContentLength:   strconv.FormatInt(fileStat.Size(), 10),
 						params.correlationIdHeader: params.correlationId,
 					}
+					// Many output files, e.g., a ZIP archive or a
+					// "multipart/mixed" body.
+					contentType := params.ctx.OutputContentType(params.outputPath)
+					if contentType != "" {
+						headers[echo.HeaderContentType] = contentType
+					}
+					digest, err := params.ctx.OutputDigest(params.outputPath)
+					if err != nil {
+						params.ctx.Log().Error(fmt.Sprintf("compute content digest of output file: %s", err))
+						params.handleError(err)
+						return
+					}
+					headers[api.ContentDigestHeader] = digest
+
 					_, ok := params.extraHttpHeaders[echo.HeaderContentDisposition]
-					if !ok {
+					if !ok && !strings.HasPrefix(contentType, "multipart/") {
 						headers[echo.HeaderContentDisposition] = fmt.Sprintf("attachment; filename=%q", params.ctx.OutputFilename(params.outputPath))
 					}
 
@@ -105,6 +119,13 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 					ctx := c.Get("context").(*api.Context)
 					cancel := c.Get("cancel").(context.CancelFunc)
 
+					if ctx.HasUploadTo() {
+						return api.WrapError(
+							errors.New("both webhook and upload to modes requested"),
+							api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The 'Gotenberg-Webhook-Url' header and the '%s' form field are mutually exclusive", api.UploadToFormField)),
+						)
+					}
+
 					// Do we have a webhook error URL and/or an events URL?
 					// At least one must be provided.
 					webhookErrorUrl := c.Request().Header.Get("Gotenberg-Webhook-Error-Url")
@@ -128,18 +149,20 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 
 					// Let's check if the webhook URLs are acceptable according to our
 					// allowed/denied lists, and against the IP-class options.
-					// See [gotenberg.FilterOutboundURL].
+					// See [gotenberg.FilterOutboundURL]. The request keeps the
+					// same policy, even if it changes on reload in the meantime.
+					policy := w.policy.Load()
 					ipOpts := []gotenberg.DecideOption{
-						gotenberg.WithDenyPrivateIPs(w.denyPrivateIPs),
-						gotenberg.WithDenyPublicIPs(w.denyPublicIPs),
+						gotenberg.WithDenyPrivateIPs(policy.denyPrivateIPs),
+						gotenberg.WithDenyPublicIPs(policy.denyPublicIPs),
 					}
-					err := gotenberg.FilterOutboundURL(ctx, webhookUrl, w.allowList, w.denyList, deadline, ipOpts...)
+					err := gotenberg.FilterOutboundURL(ctx, webhookUrl, policy.allowList, policy.denyList, deadline, ipOpts...)
 					if err != nil {
 						return fmt.Errorf("filter webhook URL: %w", err)
 					}
 
 					if webhookErrorUrl != "" {
-						err = gotenberg.FilterOutboundURL(ctx, webhookErrorUrl, w.errorAllowList, w.errorDenyList, deadline, ipOpts...)
+						err = gotenberg.FilterOutboundURL(ctx, webhookErrorUrl, policy.errorAllowList, policy.errorDenyList, deadline, ipOpts...)
 						if err != nil {
 							return fmt.Errorf("filter webhook error URL: %w", err)
 						}
@@ -202,7 +225,7 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 
 					// Filter the events URL if provided.
 					if webhookEventsUrl != "" {
-						err = gotenberg.FilterOutboundURL(ctx, webhookEventsUrl, w.allowList, w.denyList, deadline, ipOpts...)
+						err = gotenberg.FilterOutboundURL(ctx, webhookEventsUrl, policy.allowList, policy.denyList, deadline, ipOpts...)
 						if err != nil {
 							return fmt.Errorf("filter webhook events URL: %w", err)
 						}
@@ -224,7 +247,7 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 						startTime:        startTime,
 
 						client: &retryablehttp.Client{
-							HTTPClient:   gotenberg.NewOutboundHttpClient(w.clientTimeout, w.allowList, w.denyList, w.enableEnvironmentProxy, ipOpts...),
+							HTTPClient:   gotenberg.NewOutboundHttpClient(w.clientTimeout, policy.allowList, policy.denyList, w.enableEnvironmentProxy, ipOpts...),
 							RetryMax:     w.maxRetry,
 							RetryWaitMin: w.retryMinWait,
 							RetryWaitMax: w.retryMaxWait,
@@ -237,17 +260,12 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 
 					// This method parses an "asynchronous" error and sends a
 					// request to the webhook error URL with a JSON body
-					// containing the status and the error message.
+					// containing the status, the error message and the
+					// screenshot attached to the error, if any.
+					var asyncErr error
 					handleError := func(err error) {
-						status, message := api.ParseError(err)
-
-						body := struct {
-							Status  int    `json:"status"`
-							Message string `json:"message"`
-						}{
-							Status:  status,
-							Message: message,
-						}
+						asyncErr = err
+						body := api.NewErrorBody(err)
 
 						b, err := json.Marshal(body)
 						if err != nil {
@@ -270,8 +288,8 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 							"correlationId": correlationId,
 							"timestamp":     time.Now().UTC().Format(time.RFC3339Nano),
 							"error": map[string]any{
-								"status":  status,
-								"message": message,
+								"status":  body.Status,
+								"message": body.Message,
 							},
 						})
 					}
@@ -314,7 +332,7 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 						return c.NoContent(http.StatusNoContent)
 					}
 
-					cancel = detachAsyncContext(ctx, cancel)
+					cancel = api.DetachAsyncContext(ctx, cancel, "webhook.Async")
 
 					// As a webhook URL has been given, we handle the request in a
 					// goroutine and return immediately.
@@ -328,11 +346,14 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 					// Snapshot the keys downstream reads onto a detached
 					// wrapper before spawning the goroutine so pool reuse
 					// cannot reach into our async work.
-					detached := newPoolSafeContext(c, "logger", "context", "correlationId", "correlationIdHeader", "startTime")
+					detached := api.NewPoolSafeContext(c, "logger", "context", "correlationId", "correlationIdHeader", "startTime", "identity", "clientCertSubject", "outputFilename")
 
 					w.asyncCount.Add(1)
 					go func() {
 						defer cancel()
+						defer func() {
+							ctx.AsyncDone(asyncErr)
+						}()
 						defer w.asyncCount.Add(-1)
 
 						// Defense in depth: any panic that escapes the
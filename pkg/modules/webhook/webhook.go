@@ -20,12 +20,7 @@ func init() {
 // to any destinations in an asynchronous fashion.
 type Webhook struct {
 	enableSyncMode         bool
-	allowList              []*regexp2.Regexp
-	denyList               []*regexp2.Regexp
-	errorAllowList         []*regexp2.Regexp
-	errorDenyList          []*regexp2.Regexp
-	denyPrivateIPs         bool
-	denyPublicIPs          bool
+	policy                 atomic.Pointer[outboundPolicy]
 	enableEnvironmentProxy bool
 	maxRetry               int
 	retryMinWait           time.Duration
@@ -35,6 +30,28 @@ type Webhook struct {
 	disable                bool
 }
 
+// outboundPolicy gathers the allowed/denied lists and the IP-class options
+// that the webhook URLs must pass. It may change on reload.
+type outboundPolicy struct {
+	allowList      []*regexp2.Regexp
+	denyList       []*regexp2.Regexp
+	errorAllowList []*regexp2.Regexp
+	errorDenyList  []*regexp2.Regexp
+	denyPrivateIPs bool
+	denyPublicIPs  bool
+}
+
+func newOutboundPolicy(flags gotenberg.ParsedFlags) *outboundPolicy {
+	return &outboundPolicy{
+		allowList:      flags.MustRegexpSlice("webhook-allow-list"),
+		denyList:       flags.MustRegexpSlice("webhook-deny-list"),
+		errorAllowList: flags.MustDeprecatedRegexpSlice("webhook-error-allow-list", "webhook-allow-list"),
+		errorDenyList:  flags.MustDeprecatedRegexpSlice("webhook-error-deny-list", "webhook-deny-list"),
+		denyPrivateIPs: flags.MustBool("webhook-deny-private-ips"),
+		denyPublicIPs:  flags.MustBool("webhook-deny-public-ips"),
+	}
+}
+
 // Descriptor returns an [Webhook]'s module descriptor.
 func (w *Webhook) Descriptor() gotenberg.ModuleDescriptor {
 	return gotenberg.ModuleDescriptor{
@@ -75,12 +92,7 @@ func (w *Webhook) Descriptor() gotenberg.ModuleDescriptor {
 func (w *Webhook) Provision(ctx *gotenberg.Context) error {
 	flags := ctx.ParsedFlags()
 	w.enableSyncMode = flags.MustBool("webhook-enable-sync-mode")
-	w.allowList = flags.MustRegexpSlice("webhook-allow-list")
-	w.denyList = flags.MustRegexpSlice("webhook-deny-list")
-	w.errorAllowList = flags.MustDeprecatedRegexpSlice("webhook-error-allow-list", "webhook-allow-list")
-	w.errorDenyList = flags.MustDeprecatedRegexpSlice("webhook-error-deny-list", "webhook-deny-list")
-	w.denyPrivateIPs = flags.MustBool("webhook-deny-private-ips")
-	w.denyPublicIPs = flags.MustBool("webhook-deny-public-ips")
+	w.policy.Store(newOutboundPolicy(flags))
 	w.enableEnvironmentProxy = flags.MustBool("webhook-enable-environment-proxy")
 	w.maxRetry = flags.MustInt("webhook-max-retry")
 	w.retryMinWait = flags.MustDuration("webhook-retry-min-wait")
@@ -122,6 +134,14 @@ func (w *Webhook) Validate() error {
 	return nil
 }
 
+// Reload applies the new allowed/denied lists and IP-class options. In-flight
+// requests keep the ones they started with.
+func (w *Webhook) Reload(flags gotenberg.ParsedFlags) error {
+	w.policy.Store(newOutboundPolicy(flags))
+
+	return nil
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module        = (*Webhook)(nil)
@@ -129,4 +149,5 @@ var (
 	_ gotenberg.Validator     = (*Webhook)(nil)
 	_ api.MiddlewareProvider  = (*Webhook)(nil)
 	_ api.AsynchronousCounter = (*Webhook)(nil)
+	_ gotenberg.Reloader      = (*Webhook)(nil)
 )
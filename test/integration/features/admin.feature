@@ -0,0 +1,72 @@
+@admin
+Feature: /admin
+
+  Scenario: POST /admin/drain
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_ENABLE_ADMIN_ROUTES | true |
+    When I make a "POST" request to Gotenberg at the "/admin/drain" endpoint
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/json"
+    Then the response body should match JSON:
+      """
+      {
+        "draining": true,
+        "queued": 0,
+        "active": 0,
+        "async": 0
+      }
+      """
+    When I make a "GET" request to Gotenberg at the "/health" endpoint
+    Then the response status code should be 200
+    When I make a "GET" request to Gotenberg at the "/ready" endpoint
+    Then the response status code should be 503
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files | testdata/page-1-html/index.html | file |
+    Then the response status code should be 503
+    Then the response body should match string:
+      """
+      The instance is draining and does not accept new conversions.
+      """
+    When I make a "GET" request to Gotenberg at the "/admin/drain" endpoint
+    Then the response status code should be 200
+    Then the response body should match JSON:
+      """
+      {
+        "draining": true,
+        "queued": 0,
+        "active": 0,
+        "async": 0
+      }
+      """
+    When I make a "POST" request to Gotenberg at the "/admin/undrain" endpoint
+    Then the response status code should be 200
+    Then the response body should match JSON:
+      """
+      {
+        "draining": false,
+        "queued": 0,
+        "active": 0,
+        "async": 0
+      }
+      """
+    When I make a "GET" request to Gotenberg at the "/ready" endpoint
+    Then the response status code should be 200
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files | testdata/page-1-html/index.html | file |
+    Then the response status code should be 200
+
+  Scenario: POST /admin/drain (Disabled)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/admin/drain" endpoint
+    Then the response status code should be 404
+    When I make a "GET" request to Gotenberg at the "/ready" endpoint
+    Then the response status code should be 404
+
+  Scenario: POST /admin/drain (Basic Auth)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_ENABLE_ADMIN_ROUTES           | true |
+      | API_ENABLE_BASIC_AUTH             | true |
+      | GOTENBERG_API_BASIC_AUTH_USERNAME | foo  |
+      | GOTENBERG_API_BASIC_AUTH_PASSWORD | bar  |
+    When I make a "POST" request to Gotenberg at the "/admin/drain" endpoint
+    Then the response status code should be 401
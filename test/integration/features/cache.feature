@@ -0,0 +1,45 @@
+@cache
+Feature: Result Cache
+
+  Scenario: POST /forms/chromium/convert/html (Miss, then Hit)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_ENABLE_CACHE | true |
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files | testdata/page-1-html/index.html | file |
+    Then the response status code should be 200
+    Then the response header "Gotenberg-Cache" should be "miss"
+    Then there should be 1 PDF(s) in the response
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files | testdata/page-1-html/index.html | file |
+    Then the response status code should be 200
+    Then the response header "Gotenberg-Cache" should be "hit"
+    Then there should be 1 PDF(s) in the response
+
+  Scenario: POST /forms/chromium/convert/html (Other Form Fields)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_ENABLE_CACHE | true |
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files | testdata/page-1-html/index.html | file |
+    Then the response status code should be 200
+    Then the response header "Gotenberg-Cache" should be "miss"
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files     | testdata/page-1-html/index.html | file  |
+      | landscape | true                            | field |
+    Then the response status code should be 200
+    Then the response header "Gotenberg-Cache" should be "miss"
+
+  Scenario: POST /forms/chromium/convert/html (Disabled)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files | testdata/page-1-html/index.html | file |
+    Then the response status code should be 200
+    Then the response header "Gotenberg-Cache" should be ""
+
+  Scenario: POST /forms/chromium/convert/url (Not Cached)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_ENABLE_CACHE | true |
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/url" endpoint with the following form data and header(s):
+      | url | http://host.docker.internal:%d/html/testdata/page-1-html/index.html | field |
+    Then the response status code should be 200
+    Then the response header "Gotenberg-Cache" should be ""
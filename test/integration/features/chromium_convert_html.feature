@@ -604,6 +604,43 @@ Feature: /forms/chromium/convert/html
       JavaScript is enabled.
       """
 
+  Scenario: POST /forms/chromium/convert/html (Preload Scripts)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files                     | testdata/preload-scripts-html/index.html | file   |
+      | preloadScripts            | window.preloaded = "Preloaded inline";   | field  |
+      | Gotenberg-Output-Filename | foo                                      | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+    Then the "foo.pdf" PDF should have the following content at page 1:
+      """
+      Preloaded inline
+      """
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files                     | testdata/preload-scripts-html/index.html  | file   |
+      | preloadScripts            | testdata/preload-scripts-html/preload.js  | file   |
+      | Gotenberg-Output-Filename | foo                                       | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+    Then the "foo.pdf" PDF should have the following content at page 1:
+      """
+      Preloaded from a file
+      """
+
+  Scenario: POST /forms/chromium/convert/html (Preload Scripts - JavaScript Disabled)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | CHROMIUM_DISABLE_JAVASCRIPT | true |
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files                     | testdata/preload-scripts-html/index.html | file   |
+      | preloadScripts            | window.preloaded = "Preloaded inline";   | field  |
+      | Gotenberg-Output-Filename | foo                                      | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+    Then the "foo.pdf" PDF should have the following content at page 1:
+      """
+      Not preloaded
+      """
+
   Scenario: POST /forms/chromium/convert/html (Fail On Resource HTTP Status Codes)
     Given I have a default Gotenberg container
     When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
@@ -226,6 +226,50 @@ Feature: /forms/chromium/convert/url
     Then the server request cookie "cookie_1" should be "foo"
     Then the server request cookie "cookie_2" should be ""
 
+  Scenario: POST /forms/chromium/convert/url (Extra Stylesheets)
+    Given I have a default Gotenberg container
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/url" endpoint with the following form data and header(s):
+      | url                       | http://host.docker.internal:%d/html/testdata/page-1-html/index.html | field  |
+      | extraStylesheets          | h1::before { content: "Styled "; }                                  | field  |
+      | extraStylesheets          | testdata/extra-stylesheets/print.css                                | file   |
+      | Gotenberg-Output-Filename | foo                                                                 | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+    Then the "foo.pdf" PDF should have the following content at page 1:
+      """
+      Styled Page 1 from a file
+      """
+
+  Scenario: POST /forms/chromium/convert/url (Hide Selectors)
+    Given I have a default Gotenberg container
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/url" endpoint with the following form data and header(s):
+      | url                       | http://host.docker.internal:%d/html/testdata/page-1-html/index.html | field  |
+      | hideSelectors             | h1                                                                  | field  |
+      | Gotenberg-Output-Filename | foo                                                                 | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+    Then the "foo.pdf" PDF should NOT have the following content at page 1:
+      """
+      Page 1
+      """
+
+  Scenario: POST /forms/chromium/convert/url (Diagnostics)
+    Given I have a default Gotenberg container
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/url" endpoint with the following form data and header(s):
+      | url                       | http://host.docker.internal:%d/html/testdata/page-1-html/index.html | field  |
+      | diagnostics               | true                                                                | field  |
+      | Gotenberg-Output-Filename | foo                                                                 | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/zip"
+    Then there should be 1 PDF(s) in the response
+    Then there should be the following file(s) in the response:
+      | foo.pdf         |
+      | diagnostics.har |
+      | console.json    |
+
   Scenario: POST /forms/chromium/convert/url (Wait Delay)
     Given I have a default Gotenberg container
     Given I have a static server
@@ -660,6 +704,24 @@ Feature: /forms/chromium/convert/url
       /favicon.ico - 404: Not Found
       """
 
+  Scenario: POST /forms/chromium/convert/url (Fail On Resource HTTP Status Codes With Failure Screenshot)
+    Given I have a default Gotenberg container
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/url" endpoint with the following form data and header(s):
+      | url                           | http://host.docker.internal:%d/html/testdata/feature-rich-html-remote/index.html | field |
+      | failOnResourceHttpStatusCodes | [499,599]                                                                        | field |
+      | failureScreenshot             | true                                                                             | field |
+    Then the response status code should be 409
+    Then the response header "Content-Type" should be "application/json"
+    Then the response body should contain string:
+      """
+      "message":"Invalid HTTP status code from resources:
+      """
+    Then the response body should contain string:
+      """
+      "screenshot":"iVBORw0KGgo
+      """
+
   Scenario: POST /forms/chromium/convert/url (Fail On Resource Loading Failed)
     Given I have a default Gotenberg container
     Given I have a static server
@@ -0,0 +1,86 @@
+@chromium
+@chromium-convert-urls
+Feature: /forms/chromium/convert/urls
+
+  Scenario: POST /forms/chromium/convert/urls (Default)
+    Given I have a default Gotenberg container
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/urls" endpoint with the following form data and header(s):
+      | urls                      | [{"url":"http://host.docker.internal:%d/html/testdata/pages-3-html/index.html"},{"url":"http://host.docker.internal:%d/html/testdata/page-1-html/index.html"}] | field  |
+      | Gotenberg-Output-Filename | foo                                                                                                                                                             | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+    Then there should be 1 PDF(s) in the response
+    Then there should be the following file(s) in the response:
+      | foo.pdf |
+    Then the "foo.pdf" PDF should have 4 page(s)
+    Then the "foo.pdf" PDF should have the following content at page 4:
+      """
+      Page 1
+      """
+    When I make a "POST" request to Gotenberg at the "/forms/pdfengines/bookmarks/read" endpoint with the following form data and header(s):
+      | files | teststore/foo.pdf | file |
+    Then the response status code should be 200
+    Then the response body should match JSON:
+      """
+      {
+        "foo.pdf": [
+          {
+            "title": "Pages 3",
+            "page": 1
+          },
+          {
+            "title": "Page 1",
+            "page": 4
+          }
+        ]
+      }
+      """
+
+  Scenario: POST /forms/chromium/convert/urls (Without Title Bookmarks)
+    Given I have a default Gotenberg container
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/urls" endpoint with the following form data and header(s):
+      | urls                      | [{"url":"http://host.docker.internal:%d/html/testdata/page-1-html/index.html"},{"url":"http://host.docker.internal:%d/html/testdata/page-1-html/index.html"}] | field  |
+      | titleBookmarks            | false                                                                                                                                                          | field  |
+      | Gotenberg-Output-Filename | foo                                                                                                                                                            | header |
+    Then the response status code should be 200
+    Then the "foo.pdf" PDF should have 2 page(s)
+    When I make a "POST" request to Gotenberg at the "/forms/pdfengines/bookmarks/read" endpoint with the following form data and header(s):
+      | files | teststore/foo.pdf | file |
+    Then the response status code should be 200
+    Then the response body should match JSON:
+      """
+      {
+        "foo.pdf": []
+      }
+      """
+
+  Scenario: POST /forms/chromium/convert/urls (Per-URL Options)
+    Given I have a default Gotenberg container
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/urls" endpoint with the following form data and header(s):
+      | urls | [{"url":"http://host.docker.internal:%d/html/testdata/page-1-html/index.html"},{"url":"http://host.docker.internal:%d/html/testdata/page-1-html/index.html","waitForExpression":"undefined"}] | field |
+    Then the response status code should be 400
+    Then the response body should match string:
+      """
+      The expression 'undefined' (waitForExpression) returned an exception or undefined
+      """
+
+  Scenario: POST /forms/chromium/convert/urls (Bad Request)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/urls" endpoint with the following form data and header(s):
+      | urls | [] | field |
+    Then the response status code should be 400
+    Then the response header "Content-Type" should be "text/plain; charset=UTF-8"
+    Then the response body should match string:
+      """
+      Invalid form data: form field 'urls' is invalid (got '[]', resulting to no URL, expected at least one)
+      """
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/urls" endpoint with the following form data and header(s):
+      | urls | [{"url":"file:///etc/passwd"}] | field |
+    Then the response status code should be 400
+    Then the response body should match string:
+      """
+      file:// URLs are not accepted on this route. Use the /convert/html or /convert/markdown routes to render local HTML
+      """
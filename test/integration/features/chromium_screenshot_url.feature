@@ -37,6 +37,57 @@ Feature: /forms/chromium/screenshot/url
     Then there should be the following file(s) in the response:
       | foo.webp |
 
+  Scenario: POST /forms/chromium/screenshot/url (Full Page)
+    Given I have a default Gotenberg container
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/screenshot/url" endpoint with the following form data and header(s):
+      | url                       | http://host.docker.internal:%d/html/testdata/pages-12-html/index.html | field  |
+      | fullPage                  | true                                                                  | field  |
+      | Gotenberg-Output-Filename | foo                                                                   | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "image/png"
+    Then there should be the following file(s) in the response:
+      | foo.png |
+
+  Scenario: POST /forms/chromium/screenshot/url (Bad Request - Full Page WebP)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/screenshot/url" endpoint with the following form data and header(s):
+      | url      | https://gotenberg.dev | field |
+      | format   | webp                  | field |
+      | fullPage | true                  | field |
+    Then the response status code should be 400
+    Then the response header "Content-Type" should be "text/plain; charset=UTF-8"
+    Then the response body should match string:
+      """
+      Invalid form data: form field 'fullPage' is invalid (got 'true', resulting to webp format not supported, expected either 'png' or 'jpeg' format)
+      """
+
+  Scenario: POST /forms/chromium/screenshot/url (Viewports)
+    Given I have a default Gotenberg container
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/screenshot/url" endpoint with the following form data and header(s):
+      | url                       | http://host.docker.internal:%d/html/testdata/page-1-html/index.html                                        | field  |
+      | viewports                 | [{"width":375,"height":812,"deviceScaleFactor":3},{"width":768,"height":1024},{"width":1440,"height":900}] | field  |
+      | Gotenberg-Output-Filename | foo                                                                                                        | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/zip"
+    Then there should be the following file(s) in the response:
+      | 375x812@3x.png  |
+      | 768x1024@1x.png |
+      | 1440x900@1x.png |
+
+  Scenario: POST /forms/chromium/screenshot/url (Bad Request - Viewports)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/screenshot/url" endpoint with the following form data and header(s):
+      | url       | https://gotenberg.dev      | field |
+      | viewports | [{"width":375,"height":0}] | field |
+    Then the response status code should be 400
+    Then the response header "Content-Type" should be "text/plain; charset=UTF-8"
+    Then the response body should match string:
+      """
+      Invalid form data: form field 'viewports' is invalid (got '[{"width":375,"height":0}]', resulting to viewport 0: height must be positive)
+      """
+
   Scenario: POST /forms/chromium/screenshot/url (Bad Request - Missing URL)
     Given I have a default Gotenberg container
     When I make a "POST" request to Gotenberg at the "/forms/chromium/screenshot/url" endpoint with the following form data and header(s):
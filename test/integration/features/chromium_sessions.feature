@@ -0,0 +1,64 @@
+@chromium
+@chromium-sessions
+Feature: /forms/chromium/sessions
+
+  Scenario: POST /forms/chromium/sessions (Disabled)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/sessions" endpoint with the following form data and header(s):
+      | name | foo              | field |
+      | url  | https://foo.com/ | field |
+    Then the response status code should be 404
+
+  Scenario: POST /forms/chromium/sessions (Restore)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | CHROMIUM_ENABLE_SESSIONS | true |
+    Given I have a static server
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/sessions" endpoint with the following form data and header(s):
+      | name    | foo                                                                              | field |
+      | url     | http://host.docker.internal:%d/html/testdata/page-1-html/index.html              | field |
+      | cookies | [{"name":"cookie_1","value":"foo","domain":"host.docker.internal:%d"}]           | field |
+      | steps   | [{"action":"waitForSelector","selector":"body"},{"action":"wait","delay":"10ms"}] | field |
+    Then the response status code should be 201
+    Then the response header "Content-Type" should be "application/json"
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/url" endpoint with the following form data and header(s):
+      | url     | http://host.docker.internal:%d/html/testdata/page-1-html/index.html | field |
+      | session | foo                                                                 | field |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+    Then the server request cookie "cookie_1" should be "foo"
+    When I make a "DELETE" request to Gotenberg at the "/chromium/sessions/foo" endpoint
+    Then the response status code should be 204
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/url" endpoint with the following form data and header(s):
+      | url     | http://host.docker.internal:%d/html/testdata/page-1-html/index.html | field |
+      | session | foo                                                                 | field |
+    Then the response status code should be 400
+    Then the response header "Content-Type" should be "text/plain; charset=UTF-8"
+    Then the response body should match string:
+      """
+      The session 'foo' (session) does not exist or has expired
+      """
+    When I make a "DELETE" request to Gotenberg at the "/chromium/sessions/foo" endpoint
+    Then the response status code should be 404
+
+  Scenario: POST /forms/chromium/sessions (Bad Request)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | CHROMIUM_ENABLE_SESSIONS | true |
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/sessions" endpoint with the following form data and header(s):
+      | name  | foo/bar                        | field |
+      | url   | https://foo.com/               | field |
+      | steps | [{"action":"hover"}]           | field |
+      | ttl   | 1h                             | field |
+    Then the response status code should be 400
+    Then the response header "Content-Type" should be "text/plain; charset=UTF-8"
+    Then the response body should contain string:
+      """
+      form field 'name' is invalid
+      """
+    Then the response body should contain string:
+      """
+      form field 'steps' is invalid
+      """
+    Then the response body should contain string:
+      """
+      form field 'ttl' is invalid
+      """
@@ -22,14 +22,17 @@ Feature: /debug
           "api",
           "chromium",
           "exiftool",
+          "jobs",
           "libreoffice",
           "libreoffice-api",
           "libreoffice-pdfengine",
           "pdfcpu",
           "pdfengines",
           "pdftk",
+          "pipeline",
           "prometheus",
           "qpdf",
+          "ratelimit",
           "webhook"
         ],
         "modules_additional_data": {
@@ -53,30 +56,59 @@ Feature: /debug
           }
         },
         "flags": {
+          "api-audit-log": "",
+          "api-audit-log-max-backups": "5",
+          "api-audit-log-max-size": "100MB",
           "api-bind-ip": "",
           "api-body-limit": "",
+          "api-cache-dir": "",
+          "api-cache-max-size": "1GB",
+          "api-cache-ttl": "1h0m0s",
           "api-correlation-id-header": "Gotenberg-Trace",
           "api-disable-download-from": "false",
           "api-disable-health-check-logging": "false",
           "api-disable-debug-route-telemetry": "true",
           "api-disable-health-check-route-telemetry": "true",
+          "api-disable-openapi-route-telemetry": "true",
+          "api-disable-openapi-routes": "false",
           "api-disable-root-route-telemetry": "true",
+          "api-disable-upload-to": "false",
           "api-disable-version-route-telemetry": "true",
           "api-download-from-allow-list": "[.+]",
           "api-download-from-deny-list": "[]",
           "api-download-from-deny-private-ips": "false",
           "api-download-from-deny-public-ips": "false",
           "api-download-from-max-retry": "4",
+          "api-enable-admin-routes": "false",
+          "api-enable-api-key-auth": "false",
           "api-enable-basic-auth": "false",
+          "api-enable-cache": "false",
           "api-enable-debug-route": "true",
+          "api-enable-idempotency": "false",
+          "api-enable-uploads": "false",
+          "api-high-priority-allow-list": "[]",
+          "api-high-priority-deny-list": "[]",
+          "api-idempotency-dir": "",
+          "api-idempotency-ttl": "24h0m0s",
+          "api-keys-file": "",
           "api-port": "3000",
           "api-port-from-env": "",
           "api-root-path": "/",
           "api-start-timeout": "30s",
           "api-timeout": "30s",
           "api-tls-cert-file": "",
+          "api-tls-client-allow-list": "[]",
+          "api-tls-client-auth": "require",
+          "api-tls-client-ca-file": "",
           "api-tls-key-file": "",
           "api-trace-header": "Gotenberg-Trace",
+          "api-upload-to-allow-list": "[.+]",
+          "api-upload-to-deny-list": "[]",
+          "api-upload-to-deny-private-ips": "false",
+          "api-upload-to-deny-public-ips": "false",
+          "api-upload-to-max-retry": "4",
+          "api-uploads-max-count": "1000",
+          "api-uploads-ttl": "1h0m0s",
           "chromium-allow-file-access-from-files": "false",
           "chromium-allow-insecure-localhost": "false",
           "chromium-allow-list": "[.+]",
@@ -90,17 +122,23 @@ Feature: /debug
           "chromium-disable-javascript": "false",
           "chromium-disable-routes": "false",
           "chromium-disable-web-security": "false",
+          "chromium-enable-sessions": "false",
           "chromium-host-resolver-rules": "",
           "chromium-ignore-certificate-errors": "false",
           "chromium-idle-shutdown-timeout": "0s",
           "chromium-incognito": "false",
           "chromium-max-concurrency": "6",
           "chromium-max-queue-size": "0",
+          "chromium-max-sessions": "100",
           "chromium-proxy-server": "",
           "chromium-restart-after": "100",
+          "chromium-session-ttl": "30m0s",
           "chromium-start-timeout": "20s",
+          "config": "",
           "gotenberg-build-debug-data": "true",
           "gotenberg-graceful-shutdown-duration": "30s",
+          "jobs-disable": "false",
+          "jobs-result-retention": "1h0m0s",
           "libreoffice-auto-start": "false",
           "libreoffice-disable-routes": "false",
           "libreoffice-idle-shutdown-timeout": "0s",
@@ -123,12 +161,16 @@ Feature: /debug
           "pdfengines-split-engines": "[pdfcpu,qpdf,pdftk]",
           "pdfengines-write-bookmarks-engines": "[pdfcpu]",
           "pdfengines-write-metadata-engines": "[exiftool]",
+          "pipeline-disable-routes": "false",
           "prometheus-collect-interval": "1s",
           "prometheus-disable-collect": "false",
           "prometheus-disable-route-logging": "false",
           "prometheus-disable-route-telemetry": "true",
           "prometheus-namespace": "gotenberg",
           "prometheus-metrics-path": "/prometheus/metrics",
+          "ratelimit-burst": "10",
+          "ratelimit-max-concurrent": "0",
+          "ratelimit-requests-per-second": "0",
           "webhook-allow-list": "[.+]",
           "webhook-client-timeout": "30s",
           "webhook-deny-list": "[]",
@@ -140,6 +182,9 @@ Feature: /debug
           "webhook-max-retry": "4",
           "webhook-retry-max-wait": "30s",
           "webhook-retry-min-wait": "1s"
+        },
+        "flags_sources": {
+          "api-enable-debug-route": "env"
         }
       }
       """
@@ -161,14 +206,17 @@ Feature: /debug
           "api",
           "chromium",
           "exiftool",
+          "jobs",
           "libreoffice",
           "libreoffice-api",
           "libreoffice-pdfengine",
           "pdfcpu",
           "pdfengines",
           "pdftk",
+          "pipeline",
           "prometheus",
           "qpdf",
+          "ratelimit",
           "webhook"
         ],
         "modules_additional_data": {
@@ -192,30 +240,59 @@ Feature: /debug
           }
         },
         "flags": {
+          "api-audit-log": "",
+          "api-audit-log-max-backups": "5",
+          "api-audit-log-max-size": "100MB",
           "api-bind-ip": "",
           "api-body-limit": "",
+          "api-cache-dir": "",
+          "api-cache-max-size": "1GB",
+          "api-cache-ttl": "1h0m0s",
           "api-correlation-id-header": "Gotenberg-Trace",
           "api-disable-download-from": "false",
           "api-disable-health-check-logging": "false",
           "api-disable-debug-route-telemetry": "true",
           "api-disable-health-check-route-telemetry": "true",
+          "api-disable-openapi-route-telemetry": "true",
+          "api-disable-openapi-routes": "false",
           "api-disable-root-route-telemetry": "true",
+          "api-disable-upload-to": "false",
           "api-disable-version-route-telemetry": "true",
           "api-download-from-allow-list": "[.+]",
           "api-download-from-deny-list": "[]",
           "api-download-from-deny-private-ips": "false",
           "api-download-from-deny-public-ips": "false",
           "api-download-from-max-retry": "4",
+          "api-enable-admin-routes": "false",
+          "api-enable-api-key-auth": "false",
           "api-enable-basic-auth": "false",
+          "api-enable-cache": "false",
           "api-enable-debug-route": "true",
+          "api-enable-idempotency": "false",
+          "api-enable-uploads": "false",
+          "api-high-priority-allow-list": "[]",
+          "api-high-priority-deny-list": "[]",
+          "api-idempotency-dir": "",
+          "api-idempotency-ttl": "24h0m0s",
+          "api-keys-file": "",
           "api-port": "3000",
           "api-port-from-env": "",
           "api-root-path": "/",
           "api-start-timeout": "30s",
           "api-timeout": "30s",
           "api-tls-cert-file": "",
+          "api-tls-client-allow-list": "[]",
+          "api-tls-client-auth": "require",
+          "api-tls-client-ca-file": "",
           "api-tls-key-file": "",
           "api-trace-header": "Gotenberg-Trace",
+          "api-upload-to-allow-list": "[.+]",
+          "api-upload-to-deny-list": "[]",
+          "api-upload-to-deny-private-ips": "false",
+          "api-upload-to-deny-public-ips": "false",
+          "api-upload-to-max-retry": "4",
+          "api-uploads-max-count": "1000",
+          "api-uploads-ttl": "1h0m0s",
           "chromium-allow-file-access-from-files": "false",
           "chromium-allow-insecure-localhost": "false",
           "chromium-allow-list": "[.+]",
@@ -229,17 +306,23 @@ Feature: /debug
           "chromium-disable-javascript": "false",
           "chromium-disable-routes": "false",
           "chromium-disable-web-security": "false",
+          "chromium-enable-sessions": "false",
           "chromium-host-resolver-rules": "",
           "chromium-ignore-certificate-errors": "false",
           "chromium-idle-shutdown-timeout": "0s",
           "chromium-incognito": "false",
           "chromium-max-queue-size": "0",
+          "chromium-max-sessions": "100",
           "chromium-max-concurrency": "6",
           "chromium-proxy-server": "",
           "chromium-restart-after": "100",
+          "chromium-session-ttl": "30m0s",
           "chromium-start-timeout": "20s",
+          "config": "",
           "gotenberg-build-debug-data": "true",
           "gotenberg-graceful-shutdown-duration": "30s",
+          "jobs-disable": "false",
+          "jobs-result-retention": "1h0m0s",
           "libreoffice-auto-start": "false",
           "libreoffice-disable-routes": "false",
           "libreoffice-idle-shutdown-timeout": "0s",
@@ -262,12 +345,16 @@ Feature: /debug
           "pdfengines-split-engines": "[pdfcpu,qpdf,pdftk]",
           "pdfengines-write-bookmarks-engines": "[pdfcpu]",
           "pdfengines-write-metadata-engines": "[exiftool]",
+          "pipeline-disable-routes": "false",
           "prometheus-collect-interval": "1s",
           "prometheus-disable-collect": "false",
           "prometheus-disable-route-logging": "false",
           "prometheus-disable-route-telemetry": "true",
           "prometheus-namespace": "gotenberg",
           "prometheus-metrics-path": "/prometheus/metrics",
+          "ratelimit-burst": "10",
+          "ratelimit-max-concurrent": "0",
+          "ratelimit-requests-per-second": "0",
           "webhook-allow-list": "[.+]",
           "webhook-client-timeout": "30s",
           "webhook-deny-list": "[]",
@@ -279,6 +366,9 @@ Feature: /debug
           "webhook-max-retry": "4",
           "webhook-retry-max-wait": "30s",
           "webhook-retry-min-wait": "1s"
+        },
+        "flags_sources": {
+          "api-enable-debug-route": "env"
         }
       }
       """
@@ -0,0 +1,47 @@
+@idempotency
+Feature: Idempotency
+
+  Scenario: POST /forms/chromium/convert/html (Replay)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_ENABLE_IDEMPOTENCY | true |
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files           | testdata/page-1-html/index.html | file   |
+      | Idempotency-Key | foo                             | header |
+    Then the response status code should be 200
+    Then the response header "Idempotent-Replayed" should be ""
+    Then there should be 1 PDF(s) in the response
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files           | testdata/page-1-html/index.html | file   |
+      | Idempotency-Key | foo                             | header |
+    Then the response status code should be 200
+    Then the response header "Idempotent-Replayed" should be "true"
+    Then there should be 1 PDF(s) in the response
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files           | testdata/page-1-html/index.html | file   |
+      | Idempotency-Key | bar                             | header |
+    Then the response status code should be 200
+    Then the response header "Idempotent-Replayed" should be ""
+
+  Scenario: POST /forms/chromium/convert/html (Failure Not Stored)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_ENABLE_IDEMPOTENCY | true |
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | Idempotency-Key | foo | header |
+    Then the response status code should be 400
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files           | testdata/page-1-html/index.html | file   |
+      | Idempotency-Key | foo                             | header |
+    Then the response status code should be 200
+    Then the response header "Idempotent-Replayed" should be ""
+
+  Scenario: POST /forms/chromium/convert/html (Disabled)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files           | testdata/page-1-html/index.html | file   |
+      | Idempotency-Key | foo                             | header |
+    Then the response status code should be 200
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files           | testdata/page-1-html/index.html | file   |
+      | Idempotency-Key | foo                             | header |
+    Then the response status code should be 200
+    Then the response header "Idempotent-Replayed" should be ""
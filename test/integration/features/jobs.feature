@@ -0,0 +1,86 @@
+@jobs
+Feature: /jobs
+
+  Scenario: Succeeded
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/pdfengines/flatten" endpoint with the following form data and header(s):
+      | files                     | testdata/page_1.pdf | file   |
+      | Gotenberg-Output-Filename | foo                 | header |
+      | Gotenberg-Async           | true                | header |
+    Then the response status code should be 202
+    Then the response body should match JSON:
+      """
+      {
+        "id": "ignore",
+        "status": "queued",
+        "createdAt": "ignore"
+      }
+      """
+    When I wait for the asynchronous job to complete
+    Then the response status code should be 200
+    Then the response body should match JSON:
+      """
+      {
+        "id": "ignore",
+        "status": "succeeded",
+        "createdAt": "ignore",
+        "startedAt": "ignore",
+        "completedAt": "ignore",
+        "expiresAt": "ignore"
+      }
+      """
+    When I make a "GET" request to Gotenberg at the "/jobs/{jobId}/result" endpoint
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+    Then there should be the following file(s) in the response:
+      | foo.pdf |
+
+  Scenario: Failed
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/pdfengines/rotate" endpoint with the following form data and header(s):
+      | files           | testdata/page_1.pdf | file   |
+      | rotateAngle     | 45                  | field  |
+      | Gotenberg-Async | true                | header |
+    Then the response status code should be 202
+    When I wait for the asynchronous job to complete
+    Then the response body should match JSON:
+      """
+      {
+        "id": "ignore",
+        "status": "failed",
+        "createdAt": "ignore",
+        "startedAt": "ignore",
+        "completedAt": "ignore",
+        "expiresAt": "ignore",
+        "error": {
+          "status": 400,
+          "message": "ignore"
+        }
+      }
+      """
+    When I make a "GET" request to Gotenberg at the "/jobs/{jobId}/result" endpoint
+    Then the response status code should be 409
+
+  Scenario: Unknown Job
+    Given I have a default Gotenberg container
+    When I make a "GET" request to Gotenberg at the "/jobs/foo" endpoint
+    Then the response status code should be 404
+
+  Scenario: Webhook Conflict
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/pdfengines/flatten" endpoint with the following form data and header(s):
+      | files                       | testdata/page_1.pdf                   | file   |
+      | Gotenberg-Async             | true                                  | header |
+      | Gotenberg-Webhook-Url       | http://host.docker.internal/webhook   | header |
+      | Gotenberg-Webhook-Error-Url | http://host.docker.internal/webhook/e | header |
+    Then the response status code should be 400
+    Then the response body should match string:
+      """
+      The 'Gotenberg-Async' and 'Gotenberg-Webhook-Url' headers are mutually exclusive
+      """
+
+  Scenario: Disabled
+    Given I have a Gotenberg container with the following environment variable(s):
+      | JOBS_DISABLE | true |
+    When I make a "GET" request to Gotenberg at the "/jobs/foo" endpoint
+    Then the response status code should be 404
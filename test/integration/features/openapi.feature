@@ -0,0 +1,54 @@
+@openapi
+Feature: /openapi.json and /docs
+
+  Scenario: GET /openapi.json
+    Given I have a default Gotenberg container
+    When I make a "GET" request to Gotenberg at the "/openapi.json" endpoint
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/json"
+    Then the response body should contain string:
+      """
+      "/forms/chromium/convert/url"
+      """
+    Then the response body should contain string:
+      """
+      "failOnResourceHttpStatusCodes"
+      """
+    Then the response body should contain string:
+      """
+      "nativeTiledWatermarkText"
+      """
+
+  Scenario: GET /docs
+    Given I have a default Gotenberg container
+    When I make a "GET" request to Gotenberg at the "/docs" endpoint
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "text/html; charset=UTF-8"
+    Then the response body should contain string:
+      """
+      /forms/libreoffice/convert
+      """
+
+  Scenario: GET /openapi.json (Disabled)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_DISABLE_OPENAPI_ROUTES | true |
+    When I make a "GET" request to Gotenberg at the "/openapi.json" endpoint
+    Then the response status code should be 404
+
+  Scenario: GET /openapi.json (Basic Auth)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_ENABLE_BASIC_AUTH             | true |
+      | GOTENBERG_API_BASIC_AUTH_USERNAME | foo  |
+      | GOTENBERG_API_BASIC_AUTH_PASSWORD | bar  |
+    When I make a "GET" request to Gotenberg at the "/openapi.json" endpoint
+    Then the response status code should be 401
+
+  Scenario: GET /foo/openapi.json (Root Path)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_ROOT_PATH | /foo/ |
+    When I make a "GET" request to Gotenberg at the "/foo/openapi.json" endpoint
+    Then the response status code should be 200
+    Then the response body should contain string:
+      """
+      "/foo/forms/chromium/convert/url"
+      """
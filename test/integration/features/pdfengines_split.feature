@@ -15,6 +15,7 @@ Feature: /forms/pdfengines/split
     Then there should be the following file(s) in the response:
       | pages_3_0.pdf |
       | pages_3_1.pdf |
+      | manifest.json |
     Then the "pages_3_0.pdf" PDF should have 2 page(s)
     Then the "pages_3_1.pdf" PDF should have 1 page(s)
     Then the "pages_3_0.pdf" PDF should have the following content at page 1:
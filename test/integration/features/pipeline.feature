@@ -0,0 +1,90 @@
+@pipeline
+Feature: /forms/pipeline
+
+  Scenario: POST /forms/pipeline (Chromium HTML to PDF, then encrypt)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/pipeline" endpoint with the following form data and header(s):
+      | files                     | testdata/page-1-html/index.html                                                                   | file   |
+      | steps                     | [{"name":"chromium/convert/html"},{"name":"pdfengines/encrypt","options":{"userPassword":"foo"}}] | field  |
+      | Gotenberg-Output-Filename | foo                                                                                               | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+    Then there should be 1 PDF(s) in the response
+    Then there should be the following file(s) in the response:
+      | foo.pdf |
+    Then the response PDF(s) should be encrypted
+
+  Scenario: POST /forms/pipeline (LibreOffice, then merge)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/pipeline" endpoint with the following form data and header(s):
+      | files                     | testdata/page_1.docx                                         | file   |
+      | files                     | testdata/page_2.docx                                         | file   |
+      | steps                     | [{"name":"libreoffice/convert"},{"name":"pdfengines/merge"}] | field  |
+      | Gotenberg-Output-Filename | foo                                                          | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+    Then there should be 1 PDF(s) in the response
+    Then there should be the following file(s) in the response:
+      | foo.pdf |
+    Then the "foo.pdf" PDF should have 2 page(s)
+
+  Scenario: POST /forms/pipeline (PDFs, then split and watermark)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/pipeline" endpoint with the following form data and header(s):
+      | files                     | testdata/pages_3.pdf                                                                                                                                                                 | file   |
+      | steps                     | [{"name":"pdfengines/split","options":{"splitMode":"intervals","splitSpan":"1"}},{"name":"pdfengines/watermark","options":{"watermarkSource":"text","watermarkExpression":"DRAFT"}}] | field  |
+      | Gotenberg-Output-Filename | foo                                                                                                                                                                                  | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/zip"
+    Then there should be 3 PDF(s) in the response
+
+  Scenario: POST /forms/pipeline (Unknown Step)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/pipeline" endpoint with the following form data and header(s):
+      | files | testdata/page_1.pdf                          | file  |
+      | steps | [{"name":"pdfengines/merge"},{"name":"foo"}] | field |
+    Then the response status code should be 400
+    Then the response body should match string:
+      """
+      Invalid form data: step 1 is invalid: unknown step 'foo'
+      """
+
+  Scenario: POST /forms/pipeline (Conversion Not First)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/pipeline" endpoint with the following form data and header(s):
+      | files | testdata/page_1.pdf                                            | file  |
+      | steps | [{"name":"pdfengines/flatten"},{"name":"libreoffice/convert"}] | field |
+    Then the response status code should be 400
+    Then the response body should match string:
+      """
+      Invalid form data: step 1 is invalid: 'libreoffice/convert' may only be the first step
+      """
+
+  Scenario: POST /forms/pipeline (Step Failure)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/pipeline" endpoint with the following form data and header(s):
+      | files | testdata/page_1.pdf                                           | file  |
+      | steps | [{"name":"pdfengines/flatten"},{"name":"pdfengines/encrypt"}] | field |
+    Then the response status code should be 400
+    Then the response body should match string:
+      """
+      Step 1 ('pdfengines/encrypt') failed: Invalid form data: a 'userPassword' or 'ownerPassword' is required
+      """
+
+  Scenario: POST /forms/pipeline (No Steps)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/pipeline" endpoint with the following form data and header(s):
+      | files | testdata/page_1.pdf | file |
+    Then the response status code should be 400
+    Then the response body should match string:
+      """
+      Invalid form data: form field 'steps' is required
+      """
+
+  Scenario: POST /forms/pipeline (Routes Disabled)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | PIPELINE_DISABLE_ROUTES | true |
+    When I make a "POST" request to Gotenberg at the "/forms/pipeline" endpoint with the following form data and header(s):
+      | files | testdata/page_1.pdf             | file  |
+      | steps | [{"name":"pdfengines/flatten"}] | field |
+    Then the response status code should be 404
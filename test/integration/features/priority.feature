@@ -0,0 +1,33 @@
+@priority
+Feature: Priority
+
+  Scenario: POST /forms/chromium/convert/html (High Priority)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files              | testdata/page-1-html/index.html | file   |
+      | Gotenberg-Priority | high                            | header |
+    Then the response status code should be 200
+    Then the response header "Content-Type" should be "application/pdf"
+
+  Scenario: POST /forms/chromium/convert/html (Invalid Priority)
+    Given I have a default Gotenberg container
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files              | testdata/page-1-html/index.html | file   |
+      | Gotenberg-Priority | urgent                          | header |
+    Then the response status code should be 400
+    Then the response body should match string:
+      """
+      Invalid 'Gotenberg-Priority' header value: want 'high', 'normal' or 'low'
+      """
+
+  Scenario: POST /forms/chromium/convert/html (High Priority Not Allowed)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | API_HIGH_PRIORITY_ALLOW_LIST | ^previews$ |
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files              | testdata/page-1-html/index.html | file   |
+      | Gotenberg-Priority | high                            | header |
+    Then the response status code should be 403
+    Then the response body should match string:
+      """
+      The high priority is not allowed for this client.
+      """
@@ -16,12 +16,22 @@ Feature: /prometheus/metrics
       # HELP gotenberg_chromium_requests_queue_size Current number of Chromium conversion requests waiting to be treated.
       # TYPE gotenberg_chromium_requests_queue_size gauge
       gotenberg_chromium_requests_queue_size 0
+      # HELP gotenberg_chromium_requests_queue_size_by_priority Current number of Chromium conversion requests waiting to be treated, per priority class.
+      # TYPE gotenberg_chromium_requests_queue_size_by_priority gauge
+      gotenberg_chromium_requests_queue_size_by_priority{priority="high"} 0
+      gotenberg_chromium_requests_queue_size_by_priority{priority="low"} 0
+      gotenberg_chromium_requests_queue_size_by_priority{priority="normal"} 0
       # HELP gotenberg_chromium_restarts_count Current number of Chromium restarts.
       # TYPE gotenberg_chromium_restarts_count gauge
       gotenberg_chromium_restarts_count 0
       # HELP gotenberg_libreoffice_requests_queue_size Current number of LibreOffice conversion requests waiting to be treated.
       # TYPE gotenberg_libreoffice_requests_queue_size gauge
       gotenberg_libreoffice_requests_queue_size 0
+      # HELP gotenberg_libreoffice_requests_queue_size_by_priority Current number of LibreOffice conversion requests waiting to be treated, per priority class.
+      # TYPE gotenberg_libreoffice_requests_queue_size_by_priority gauge
+      gotenberg_libreoffice_requests_queue_size_by_priority{priority="high"} 0
+      gotenberg_libreoffice_requests_queue_size_by_priority{priority="low"} 0
+      gotenberg_libreoffice_requests_queue_size_by_priority{priority="normal"} 0
       # HELP gotenberg_libreoffice_restarts_count Current number of LibreOffice restarts.
       # TYPE gotenberg_libreoffice_restarts_count gauge
       gotenberg_libreoffice_restarts_count 0
@@ -42,12 +52,22 @@ Feature: /prometheus/metrics
       # HELP gotenberg_chromium_requests_queue_size Current number of Chromium conversion requests waiting to be treated.
       # TYPE gotenberg_chromium_requests_queue_size gauge
       gotenberg_chromium_requests_queue_size 0
+      # HELP gotenberg_chromium_requests_queue_size_by_priority Current number of Chromium conversion requests waiting to be treated, per priority class.
+      # TYPE gotenberg_chromium_requests_queue_size_by_priority gauge
+      gotenberg_chromium_requests_queue_size_by_priority{priority="high"} 0
+      gotenberg_chromium_requests_queue_size_by_priority{priority="low"} 0
+      gotenberg_chromium_requests_queue_size_by_priority{priority="normal"} 0
       # HELP gotenberg_chromium_restarts_count Current number of Chromium restarts.
       # TYPE gotenberg_chromium_restarts_count gauge
       gotenberg_chromium_restarts_count 0
       # HELP gotenberg_libreoffice_requests_queue_size Current number of LibreOffice conversion requests waiting to be treated.
       # TYPE gotenberg_libreoffice_requests_queue_size gauge
       gotenberg_libreoffice_requests_queue_size 0
+      # HELP gotenberg_libreoffice_requests_queue_size_by_priority Current number of LibreOffice conversion requests waiting to be treated, per priority class.
+      # TYPE gotenberg_libreoffice_requests_queue_size_by_priority gauge
+      gotenberg_libreoffice_requests_queue_size_by_priority{priority="high"} 0
+      gotenberg_libreoffice_requests_queue_size_by_priority{priority="low"} 0
+      gotenberg_libreoffice_requests_queue_size_by_priority{priority="normal"} 0
       # HELP gotenberg_libreoffice_restarts_count Current number of LibreOffice restarts.
       # TYPE gotenberg_libreoffice_restarts_count gauge
       gotenberg_libreoffice_restarts_count 0
@@ -67,12 +87,22 @@ Feature: /prometheus/metrics
       # HELP foo_chromium_requests_queue_size Current number of Chromium conversion requests waiting to be treated.
       # TYPE foo_chromium_requests_queue_size gauge
       foo_chromium_requests_queue_size 0
+      # HELP foo_chromium_requests_queue_size_by_priority Current number of Chromium conversion requests waiting to be treated, per priority class.
+      # TYPE foo_chromium_requests_queue_size_by_priority gauge
+      foo_chromium_requests_queue_size_by_priority{priority="high"} 0
+      foo_chromium_requests_queue_size_by_priority{priority="low"} 0
+      foo_chromium_requests_queue_size_by_priority{priority="normal"} 0
       # HELP foo_chromium_restarts_count Current number of Chromium restarts.
       # TYPE foo_chromium_restarts_count gauge
       foo_chromium_restarts_count 0
       # HELP foo_libreoffice_requests_queue_size Current number of LibreOffice conversion requests waiting to be treated.
       # TYPE foo_libreoffice_requests_queue_size gauge
       foo_libreoffice_requests_queue_size 0
+      # HELP foo_libreoffice_requests_queue_size_by_priority Current number of LibreOffice conversion requests waiting to be treated, per priority class.
+      # TYPE foo_libreoffice_requests_queue_size_by_priority gauge
+      foo_libreoffice_requests_queue_size_by_priority{priority="high"} 0
+      foo_libreoffice_requests_queue_size_by_priority{priority="low"} 0
+      foo_libreoffice_requests_queue_size_by_priority{priority="normal"} 0
       # HELP foo_libreoffice_restarts_count Current number of LibreOffice restarts.
       # TYPE foo_libreoffice_restarts_count gauge
       foo_libreoffice_restarts_count 0
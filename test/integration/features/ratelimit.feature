@@ -0,0 +1,19 @@
+@ratelimit
+Feature: Rate Limit
+
+  Scenario: POST /forms/chromium/convert/html (Requests Per Second)
+    Given I have a Gotenberg container with the following environment variable(s):
+      | RATELIMIT_REQUESTS_PER_SECOND | 0.001 |
+      | RATELIMIT_BURST               | 1     |
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files | testdata/page-1-html/index.html | file |
+    Then the response status code should be 200
+    When I make a "POST" request to Gotenberg at the "/forms/chromium/convert/html" endpoint with the following form data and header(s):
+      | files | testdata/page-1-html/index.html | file |
+    Then the response status code should be 429
+    Then the response header "Retry-After" should be "1000"
+    Then the response body should match string:
+      """
+      Too Many Requests
+      """
+
@@ -89,6 +89,7 @@ func applyDefaultEnv(env map[string]string) map[string]string {
 	defaults := map[string]string{
 		"CHROMIUM_ALLOW_LIST":          integrationAllowList,
 		"API_DOWNLOAD_FROM_ALLOW_LIST": integrationAllowList,
+		"API_UPLOAD_TO_ALLOW_LIST":     integrationAllowList,
 		"WEBHOOK_ALLOW_LIST":           integrationAllowList,
 	}
 	for k, v := range defaults {
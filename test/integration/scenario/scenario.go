@@ -94,11 +94,13 @@ type scenario struct {
 	gotenbergContainerNetwork *testcontainers.DockerNetwork
 	server                    *server
 	hostPort                  int
+	jobId                     string
 }
 
 func (s *scenario) reset(ctx context.Context) error {
 	s.resp = httptest.NewRecorder()
 	s.concurrentResps = nil
+	s.jobId = ""
 
 	err := os.RemoveAll(s.workdir)
 	if err != nil {
@@ -177,6 +179,8 @@ func (s *scenario) iMakeARequestToGotenbergWithTheFollowingHeaders(ctx context.C
 		}
 	}
 
+	endpoint = strings.ReplaceAll(endpoint, "{jobId}", s.jobId)
+
 	resp, err := doRequest(method, fmt.Sprintf("%s%s", base, endpoint), headers, nil)
 	if err != nil {
 		return fmt.Errorf("do request: %w", err)
@@ -219,7 +223,7 @@ func (s *scenario) iMakeARequestToGotenbergWithTheFollowingFormDataAndHeaders(ct
 
 		switch kind {
 		case "field":
-			if name == "downloadFrom" || name == "url" || name == "cookies" {
+			if name == "downloadFrom" || name == "url" || name == "cookies" || name == "urls" {
 				fields[name] = append(fields[name], strings.ReplaceAll(value, "%d", fmt.Sprintf("%d", s.hostPort)))
 				continue
 			}
@@ -535,6 +539,45 @@ func (s *scenario) iWaitForTheAsynchronousRequestToWebhook(ctx context.Context)
 	}
 }
 
+func (s *scenario) iWaitForTheAsynchronousJobToComplete(ctx context.Context) error {
+	var job struct {
+		Id     string `json:"id"`
+		Status string `json:"status"`
+	}
+	err := json.Unmarshal(s.resp.Body.Bytes(), &job)
+	if err != nil {
+		return fmt.Errorf("unmarshal job: %w", err)
+	}
+	if job.Id == "" {
+		return errors.New("no job ID in response")
+	}
+	s.jobId = job.Id
+
+	for {
+		err = s.iMakeARequestToGotenberg(ctx, http.MethodGet, "/jobs/{jobId}")
+		if err != nil {
+			return fmt.Errorf("get job status: %w", err)
+		}
+		if s.resp.Code != http.StatusOK {
+			return fmt.Errorf("expected job status code %d, got %d", http.StatusOK, s.resp.Code)
+		}
+
+		err = json.Unmarshal(s.resp.Body.Bytes(), &job)
+		if err != nil {
+			return fmt.Errorf("unmarshal job: %w", err)
+		}
+		if job.Status == "succeeded" || job.Status == "failed" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
 func (s *scenario) theGotenbergContainerShouldLogTheFollowingEntries(ctx context.Context, should string, entriesTable *godog.Table) error {
 	if s.gotenbergContainer == nil {
 		return errors.New("no Gotenberg container")
@@ -1640,11 +1683,12 @@ func InitializeScenario(ctx *godog.ScenarioContext) {
 	ctx.Given(`^I have a default Gotenberg container$`, s.iHaveADefaultGotenbergContainer)
 	ctx.Given(`^I have a Gotenberg container with the following environment variable\(s\):$`, s.iHaveAGotenbergContainerWithTheFollowingEnvironmentVariables)
 	ctx.Given(`^I have a (webhook|static) server$`, s.iHaveAServer)
-	ctx.When(`^I make a "(GET|HEAD)" request to Gotenberg at the "([^"]*)" endpoint$`, s.iMakeARequestToGotenberg)
+	ctx.When(`^I make a "(GET|HEAD|POST|DELETE)" request to Gotenberg at the "([^"]*)" endpoint$`, s.iMakeARequestToGotenberg)
 	ctx.When(`^I make a "(GET|HEAD)" request to Gotenberg at the "([^"]*)" endpoint with the following header\(s\):$`, s.iMakeARequestToGotenbergWithTheFollowingHeaders)
 	ctx.When(`^I make a "(POST)" request to Gotenberg at the "([^"]*)" endpoint with the following form data and header\(s\):$`, s.iMakeARequestToGotenbergWithTheFollowingFormDataAndHeaders)
 	ctx.When(`^I make (\d+) concurrent "(POST)" requests to Gotenberg at the "([^"]*)" endpoint with the following form data and header\(s\):$`, s.iMakeConcurrentRequestsToGotenberg)
 	ctx.When(`^I wait for the asynchronous request to the webhook$`, s.iWaitForTheAsynchronousRequestToWebhook)
+	ctx.When(`^I wait for the asynchronous job to complete$`, s.iWaitForTheAsynchronousJobToComplete)
 	ctx.Then(`^the Gotenberg container (should|should NOT) log the following entries:$`, s.theGotenbergContainerShouldLogTheFollowingEntries)
 	ctx.Then(`^the response status code should be (\d+)$`, s.theResponseStatusCodeShouldBe)
 	ctx.Then(`^all concurrent response status codes should be (\d+)$`, s.allConcurrentResponseStatusCodesShouldBe)
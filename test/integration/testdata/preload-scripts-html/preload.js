@@ -0,0 +1 @@
+window.preloaded = "Preloaded from a file";